@@ -0,0 +1,178 @@
+package authentication
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// CachingUserProvider is a UserProvider which caches the details retrieved from another UserProvider. Users which are
+// not found are also cached for a shorter duration. Password checks are never cached.
+type CachingUserProvider struct {
+	provider UserProvider
+
+	ttl, negativeTTL time.Duration
+	max              int
+
+	entries map[string]*list.Element
+	lru     *list.List
+	mu      sync.Mutex
+
+	clock utils.Clock
+}
+
+type cachingUserProviderEntry struct {
+	username string
+	details  *UserDetails
+	expires  time.Time
+}
+
+// NewCachingUserProvider creates a new instance of CachingUserProvider which wraps the provided UserProvider.
+func NewCachingUserProvider(config schema.AuthenticationBackendCache, provider UserProvider) (caching *CachingUserProvider) {
+	return &CachingUserProvider{
+		provider:    provider,
+		ttl:         config.TTL,
+		negativeTTL: config.NegativeTTL,
+		max:         config.MaxEntries,
+		entries:     map[string]*list.Element{},
+		lru:         list.New(),
+		clock:       &utils.RealClock{},
+	}
+}
+
+// Provider returns the UserProvider wrapped by this CachingUserProvider.
+func (p *CachingUserProvider) Provider() (provider UserProvider) {
+	return p.provider
+}
+
+// Close closes the wrapped provider if it holds resources which must be released on shutdown.
+func (p *CachingUserProvider) Close() {
+	if closer, ok := p.provider.(UserProviderCloser); ok {
+		closer.Close()
+	}
+}
+
+// CheckUserPassword checks if provided password matches for the given user using the wrapped provider.
+func (p *CachingUserProvider) CheckUserPassword(username string, password string) (valid bool, err error) {
+	return p.provider.CheckUserPassword(username, password)
+}
+
+// GetDetails retrieve the details of the given user from the cache or the wrapped provider.
+func (p *CachingUserProvider) GetDetails(username string) (details *UserDetails, err error) {
+	if details, ok := p.get(username); ok {
+		if details == nil {
+			return nil, ErrUserNotFound
+		}
+
+		return details, nil
+	}
+
+	switch details, err = p.provider.GetDetails(username); {
+	case err == nil:
+		p.set(username, details, p.ttl)
+
+		return details.clone(), nil
+	case errors.Is(err, ErrUserNotFound):
+		p.set(username, nil, p.negativeTTL)
+	}
+
+	return details, err
+}
+
+// UpdatePassword update the password of the given user using the wrapped provider and invalidates the cached details.
+func (p *CachingUserProvider) UpdatePassword(username string, newPassword string) (err error) {
+	err = p.provider.UpdatePassword(username, newPassword)
+
+	p.Invalidate(username)
+
+	return err
+}
+
+// StartupCheck implements the startup check provider interface.
+func (p *CachingUserProvider) StartupCheck() (err error) {
+	return p.provider.StartupCheck()
+}
+
+// Invalidate removes the cached details of the given user.
+func (p *CachingUserProvider) Invalidate(username string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, ok := p.entries[username]; ok {
+		p.remove(element)
+	}
+}
+
+// Purge removes the cached details of all users.
+func (p *CachingUserProvider) Purge() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.entries = map[string]*list.Element{}
+	p.lru.Init()
+}
+
+// Len returns the number of cached entries including expired entries which have not yet been evicted.
+func (p *CachingUserProvider) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.lru.Len()
+}
+
+func (p *CachingUserProvider) get(username string) (details *UserDetails, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	element, ok := p.entries[username]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cachingUserProviderEntry)
+
+	if !p.clock.Now().Before(entry.expires) {
+		p.remove(element)
+
+		return nil, false
+	}
+
+	p.lru.MoveToFront(element)
+
+	return entry.details.clone(), true
+}
+
+func (p *CachingUserProvider) set(username string, details *UserDetails, ttl time.Duration) {
+	if p.max == 0 || ttl <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry := &cachingUserProviderEntry{username: username, details: details.clone(), expires: p.clock.Now().Add(ttl)}
+
+	if element, ok := p.entries[username]; ok {
+		element.Value = entry
+
+		p.lru.MoveToFront(element)
+
+		return
+	}
+
+	p.entries[username] = p.lru.PushFront(entry)
+
+	for p.lru.Len() > p.max {
+		p.remove(p.lru.Back())
+	}
+}
+
+func (p *CachingUserProvider) remove(element *list.Element) {
+	p.lru.Remove(element)
+
+	delete(p.entries, element.Value.(*cachingUserProviderEntry).username)
+}
@@ -0,0 +1,201 @@
+package authentication
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+type testCountingUserProvider struct {
+	users map[string]*UserDetails
+	err   error
+
+	calls map[string]int
+}
+
+func (p *testCountingUserProvider) StartupCheck() (err error) {
+	return nil
+}
+
+func (p *testCountingUserProvider) CheckUserPassword(username string, password string) (valid bool, err error) {
+	p.calls["CheckUserPassword"]++
+
+	return password == "password", nil
+}
+
+func (p *testCountingUserProvider) GetDetails(username string) (details *UserDetails, err error) {
+	p.calls[username]++
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	if details = p.users[username]; details == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return details, nil
+}
+
+func (p *testCountingUserProvider) UpdatePassword(username string, newPassword string) (err error) {
+	return nil
+}
+
+type CachingUserProviderSuite struct {
+	suite.Suite
+
+	backend  *testCountingUserProvider
+	clock    *utils.TestingClock
+	provider *CachingUserProvider
+}
+
+func (s *CachingUserProviderSuite) SetupTest() {
+	s.backend = &testCountingUserProvider{
+		users: map[string]*UserDetails{
+			"john":  {Username: "john", Groups: []string{"admins"}},
+			"harry": {Username: "harry", Groups: []string{"dev"}},
+		},
+		calls: map[string]int{},
+	}
+
+	s.provider = NewCachingUserProvider(schema.AuthenticationBackendCache{
+		Enable:      true,
+		TTL:         time.Minute,
+		NegativeTTL: time.Second * 10,
+		MaxEntries:  10,
+	}, s.backend)
+
+	s.clock = &utils.TestingClock{}
+	s.clock.Set(time.Unix(1000000, 0))
+
+	s.provider.clock = s.clock
+}
+
+func (s *CachingUserProviderSuite) TestShouldCacheDetails() {
+
+	for i := 0; i < 3; i++ {
+		details, err := s.provider.GetDetails("john")
+
+		s.Require().NoError(err)
+		s.Equal([]string{"admins"}, details.Groups)
+	}
+
+	s.Equal(1, s.backend.calls["john"])
+
+	// Modifying the returned details must not modify the cached details.
+	details, err := s.provider.GetDetails("john")
+
+	s.Require().NoError(err)
+
+	details.Groups[0] = "modified"
+
+	details, err = s.provider.GetDetails("john")
+
+	s.Require().NoError(err)
+	s.Equal([]string{"admins"}, details.Groups)
+
+	s.clock.Set(s.clock.Now().Add(time.Minute))
+
+	_, err = s.provider.GetDetails("john")
+
+	s.Require().NoError(err)
+	s.Equal(2, s.backend.calls["john"])
+}
+
+func (s *CachingUserProviderSuite) TestShouldCacheUnknownUsers() {
+
+	for i := 0; i < 3; i++ {
+		_, err := s.provider.GetDetails("fred")
+
+		s.ErrorIs(err, ErrUserNotFound)
+	}
+
+	s.Equal(1, s.backend.calls["fred"])
+
+	s.clock.Set(s.clock.Now().Add(time.Second * 10))
+
+	_, err := s.provider.GetDetails("fred")
+
+	s.ErrorIs(err, ErrUserNotFound)
+	s.Equal(2, s.backend.calls["fred"])
+}
+
+func (s *CachingUserProviderSuite) TestShouldNotCacheErrors() {
+
+	s.backend.err = errors.New("connection refused")
+
+	for i := 0; i < 2; i++ {
+		_, err := s.provider.GetDetails("john")
+
+		s.EqualError(err, "connection refused")
+	}
+
+	s.Equal(2, s.backend.calls["john"])
+	s.Equal(0, s.provider.Len())
+}
+
+func (s *CachingUserProviderSuite) TestShouldEvictLeastRecentlyUsed() {
+	s.provider.max = 2
+
+	for i := 0; i < 5; i++ {
+		s.backend.users[fmt.Sprintf("user%d", i)] = &UserDetails{Username: fmt.Sprintf("user%d", i)}
+	}
+
+	_, _ = s.provider.GetDetails("user0")
+	_, _ = s.provider.GetDetails("user1")
+	_, _ = s.provider.GetDetails("user0")
+	_, _ = s.provider.GetDetails("user2")
+
+	s.Equal(2, s.provider.Len())
+
+	_, _ = s.provider.GetDetails("user0")
+	_, _ = s.provider.GetDetails("user1")
+
+	s.Equal(1, s.backend.calls["user0"])
+	s.Equal(2, s.backend.calls["user1"])
+}
+
+func (s *CachingUserProviderSuite) TestShouldInvalidate() {
+
+	_, _ = s.provider.GetDetails("john")
+	_, _ = s.provider.GetDetails("harry")
+
+	s.Require().NoError(s.provider.UpdatePassword("john", "new-password"))
+
+	_, _ = s.provider.GetDetails("john")
+	_, _ = s.provider.GetDetails("harry")
+
+	s.Equal(2, s.backend.calls["john"])
+	s.Equal(1, s.backend.calls["harry"])
+
+	s.provider.Purge()
+
+	s.Equal(0, s.provider.Len())
+
+	_, _ = s.provider.GetDetails("harry")
+
+	s.Equal(2, s.backend.calls["harry"])
+}
+
+func (s *CachingUserProviderSuite) TestShouldNotCachePasswordChecks() {
+
+	for i := 0; i < 2; i++ {
+		valid, err := s.provider.CheckUserPassword("john", "password")
+
+		s.Require().NoError(err)
+		s.True(valid)
+	}
+
+	s.Equal(2, s.backend.calls["CheckUserPassword"])
+	s.Equal(s.backend, s.provider.Provider())
+}
+
+func TestRunCachingUserProviderSuite(t *testing.T) {
+	suite.Run(t, new(CachingUserProviderSuite))
+}
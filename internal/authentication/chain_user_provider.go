@@ -0,0 +1,122 @@
+package authentication
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+// ChainUserProvider is a UserProvider which delegates to several other UserProvider's in order. The first provider
+// which knows about a user is considered the owner of that user.
+type ChainUserProvider struct {
+	names     []string
+	providers []UserProvider
+
+	log *logrus.Logger
+}
+
+// NewChainUserProvider creates a new instance of ChainUserProvider.
+func NewChainUserProvider() (provider *ChainUserProvider) {
+	return &ChainUserProvider{
+		log: logging.Logger(),
+	}
+}
+
+// Add appends a UserProvider to the end of the chain.
+func (p *ChainUserProvider) Add(name string, provider UserProvider) {
+	p.names = append(p.names, name)
+	p.providers = append(p.providers, provider)
+}
+
+// Get returns the UserProvider with the given name if it's part of the chain.
+func (p *ChainUserProvider) Get(name string) (provider UserProvider, ok bool) {
+	for i, n := range p.names {
+		if n == name {
+			return p.providers[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// CheckUserPassword checks if provided password matches for the given user using the first provider which
+// knows about the user.
+func (p *ChainUserProvider) CheckUserPassword(username string, password string) (valid bool, err error) {
+	for _, provider := range p.providers {
+		if valid, err = provider.CheckUserPassword(username, password); errors.Is(err, ErrUserNotFound) {
+			continue
+		}
+
+		return valid, err
+	}
+
+	return false, ErrUserNotFound
+}
+
+// GetDetails retrieve the details of the given user from the first provider which knows about the user.
+func (p *ChainUserProvider) GetDetails(username string) (details *UserDetails, err error) {
+	for _, provider := range p.providers {
+		if details, err = provider.GetDetails(username); errors.Is(err, ErrUserNotFound) {
+			continue
+		}
+
+		return details, err
+	}
+
+	return nil, ErrUserNotFound
+}
+
+// UpdatePassword update the password of the given user in the first provider which knows about the user. The owner
+// is looked up on every call instead of being remembered so the chain doesn't hold state for every user.
+func (p *ChainUserProvider) UpdatePassword(username string, newPassword string) (err error) {
+	for _, provider := range p.providers {
+		if _, err = provider.GetDetails(username); errors.Is(err, ErrUserNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return provider.UpdatePassword(username, newPassword)
+	}
+
+	return ErrUserNotFound
+}
+
+// StartupCheck implements the startup check provider interface. It only fails if every provider in the chain fails
+// so that the remaining providers are still usable when one of them is unavailable.
+func (p *ChainUserProvider) StartupCheck() (err error) {
+	if len(p.providers) == 0 {
+		return fmt.Errorf("no user providers are configured in the chain")
+	}
+
+	var failed []string
+
+	for i, provider := range p.providers {
+		if err = provider.StartupCheck(); err != nil {
+			p.log.WithError(err).Errorf("Failure running the startup check for the '%s' user provider in the chain", p.names[i])
+
+			failed = append(failed, p.names[i])
+		}
+	}
+
+	if len(failed) == len(p.providers) {
+		return fmt.Errorf("all user providers in the chain failed their startup check: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// Close closes every provider in the chain which holds resources which must be released on shutdown.
+func (p *ChainUserProvider) Close() {
+	for _, provider := range p.providers {
+		if closer, ok := provider.(UserProviderCloser); ok {
+			closer.Close()
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package authentication
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestChainUserProviderShouldErrorWhenEmpty(t *testing.T) {
+	provider := NewChainUserProvider()
+
+	assert.EqualError(t, provider.StartupCheck(), "no user providers are configured in the chain")
+}
+
+func TestChainUserProviderShouldErrorWhenAllProvidersFailStartupCheck(t *testing.T) {
+	provider := NewChainUserProvider()
+
+	provider.Add(schema.AuthenticationBackendStorage, NewStorageUserProvider(&schema.StorageAuthenticationBackend{Password: schema.DefaultCIPasswordConfig}, nil))
+
+	assert.EqualError(t, provider.StartupCheck(), "all user providers in the chain failed their startup check: storage")
+}
+
+func TestChainUserProviderShouldNotErrorWhenSomeProvidersFailStartupCheck(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+
+		provider := NewChainUserProvider()
+
+		provider.Add(schema.AuthenticationBackendFile, NewFileUserProvider(&config))
+		provider.Add(schema.AuthenticationBackendStorage, NewStorageUserProvider(&schema.StorageAuthenticationBackend{Password: schema.DefaultCIPasswordConfig}, nil))
+
+		assert.NoError(t, provider.StartupCheck())
+
+		fileProvider, ok := provider.Get(schema.AuthenticationBackendFile)
+
+		assert.True(t, ok)
+		assert.IsType(t, &FileUserProvider{}, fileProvider)
+
+		_, ok = provider.Get(schema.AuthenticationBackendLDAP)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestChainUserProviderShouldNotFallbackOnOtherErrors(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mock := NewMockUserDatabaseProvider(ctrl)
+
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+
+		provider := NewChainUserProvider()
+
+		provider.Add(schema.AuthenticationBackendStorage, NewStorageUserProvider(&schema.StorageAuthenticationBackend{Password: schema.DefaultCIPasswordConfig}, mock))
+		provider.Add(schema.AuthenticationBackendFile, NewFileUserProvider(&config))
+
+		require.NoError(t, provider.StartupCheck())
+
+		mock.EXPECT().LoadUser(gomock.Any(), "john").Return(nil, errors.New("connection refused"))
+
+		ok, err := provider.CheckUserPassword("john", "password")
+
+		assert.EqualError(t, err, "connection refused")
+		assert.False(t, ok)
+	})
+}
+
+type ChainUserProviderSuite struct {
+	suite.Suite
+
+	ctrl     *gomock.Controller
+	mock     *MockUserDatabaseProvider
+	provider *ChainUserProvider
+}
+
+func (s *ChainUserProviderSuite) SetupTest() {
+	path := filepath.Join(s.T().TempDir(), "users_database.yml")
+
+	s.Require().NoError(os.WriteFile(path, UserDatabaseContent, 0600))
+
+	s.ctrl = gomock.NewController(s.T())
+	s.mock = NewMockUserDatabaseProvider(s.ctrl)
+
+	config := DefaultFileAuthenticationBackendConfiguration
+	config.Path = path
+
+	s.provider = NewChainUserProvider()
+
+	s.provider.Add(schema.AuthenticationBackendFile, NewFileUserProvider(&config))
+	s.provider.Add(schema.AuthenticationBackendStorage, NewStorageUserProvider(&schema.StorageAuthenticationBackend{Password: schema.DefaultCIPasswordConfig}, s.mock))
+
+	s.Require().NoError(s.provider.StartupCheck())
+}
+
+func (s *ChainUserProviderSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *ChainUserProviderSuite) TestShouldCheckUserPasswordInFirstProvider() {
+	ok, err := s.provider.CheckUserPassword("john", "password")
+
+	s.NoError(err)
+	s.True(ok)
+
+	ok, err = s.provider.CheckUserPassword("john", "wrong")
+
+	s.NoError(err)
+	s.False(ok)
+}
+
+func (s *ChainUserProviderSuite) TestShouldFallbackToNextProvider() {
+	gomock.InOrder(
+		s.mock.EXPECT().LoadUser(gomock.Any(), "fred").Return(&model.User{Username: "fred", Password: storageUserDigestJohn}, nil),
+		s.mock.EXPECT().LoadUser(gomock.Any(), "fred").Return(&model.User{Username: "fred", DisplayName: "Fred", Groups: []string{"dev"}}, nil),
+	)
+
+	ok, err := s.provider.CheckUserPassword("fred", "password")
+
+	s.NoError(err)
+	s.True(ok)
+
+	details, err := s.provider.GetDetails("fred")
+
+	s.Require().NoError(err)
+	s.Equal("Fred", details.DisplayName)
+	s.Equal([]string{"dev"}, details.Groups)
+}
+
+func (s *ChainUserProviderSuite) TestShouldReturnUserNotFound() {
+	s.mock.EXPECT().LoadUser(gomock.Any(), "fake").Return(nil, storage.ErrNoUser).Times(2)
+
+	ok, err := s.provider.CheckUserPassword("fake", "password")
+
+	s.ErrorIs(err, ErrUserNotFound)
+	s.False(ok)
+
+	details, err := s.provider.GetDetails("fake")
+
+	s.ErrorIs(err, ErrUserNotFound)
+	s.Nil(details)
+}
+
+func (s *ChainUserProviderSuite) TestShouldNotFallbackForDisabledUsers() {
+	ok, err := s.provider.CheckUserPassword("dis", "password")
+
+	s.ErrorIs(err, ErrAccountDisabled)
+	s.False(ok)
+
+	ok, err = s.provider.CheckUserPassword("dis", "wrong")
+
+	s.NoError(err)
+	s.False(ok)
+
+	details, err := s.provider.GetDetails("dis")
+
+	s.ErrorIs(err, ErrAccountDisabled)
+	s.Nil(details)
+
+	s.ErrorIs(s.provider.UpdatePassword("dis", "new"), ErrAccountDisabled)
+}
+
+func (s *ChainUserProviderSuite) TestShouldUpdatePasswordInOwningProvider() {
+	gomock.InOrder(
+		s.mock.EXPECT().LoadUser(gomock.Any(), "fred").Return(&model.User{Username: "fred", Password: storageUserDigestJohn}, nil),
+		s.mock.EXPECT().LoadUser(gomock.Any(), "fred").Return(&model.User{Username: "fred", Password: storageUserDigestJohn}, nil),
+		s.mock.EXPECT().UpdateUserPassword(gomock.Any(), "fred", gomock.Any()).Return(nil),
+	)
+
+	s.NoError(s.provider.UpdatePassword("fred", "newpassword"))
+
+	s.NoError(s.provider.UpdatePassword("harry", "newpassword"))
+
+	ok, err := s.provider.CheckUserPassword("harry", "newpassword")
+
+	s.NoError(err)
+	s.True(ok)
+}
+
+func (s *ChainUserProviderSuite) TestShouldReturnUserNotFoundOnUpdatePassword() {
+	s.mock.EXPECT().LoadUser(gomock.Any(), "fake").Return(nil, storage.ErrNoUser)
+
+	s.ErrorIs(s.provider.UpdatePassword("fake", "newpassword"), ErrUserNotFound)
+}
+
+func TestRunChainUserProviderSuite(t *testing.T) {
+	suite.Run(t, new(ChainUserProviderSuite))
+}
@@ -58,15 +58,70 @@ const (
 	//
 	// See the linked documents for more information.
 	ldapOIDControlMsftServerPolicyHintsDeprecated = "1.2.840.113556.1.4.2066"
+
+	// LDAP Control OID: Password Policy for LDAP Directories.
+	//
+	// Draft: https://datatracker.ietf.org/doc/html/draft-behera-ldap-password-policy-10
+	//
+	// OID Reference: https://oidref.com/1.3.6.1.4.1.42.2.27.8.5.1
+	//
+	// See the linked documents for more information.
+	ldapOIDControlPwdPolicy = "1.3.6.1.4.1.42.2.27.8.5.1"
 )
 
 const (
 	ldapAttributeUnicodePwd   = "unicodePwd"
 	ldapAttributeUserPassword = "userPassword"
+	ldapAttributeMemberOf     = "memberOf"
+)
+
+const (
+	ldapAttributeActiveDirectoryUserAccountControl = "userAccountControl"
+	ldapAttributeActiveDirectoryLockoutTime        = "lockoutTime"
+	ldapAttributeActiveDirectoryAccountExpires     = "accountExpires"
+	ldapAttributeActiveDirectoryPwdLastSet         = "pwdLastSet"
+)
+
+const (
+	// ldapActiveDirectoryUACAccountDisable is the ACCOUNTDISABLE flag of the userAccountControl attribute, see
+	// https://learn.microsoft.com/en-us/troubleshoot/windows-server/identity/useraccountcontrol-manipulate-account-properties.
+	ldapActiveDirectoryUACAccountDisable int64 = 0x2
+
+	// ldapActiveDirectoryAccountExpiresNever is the value of the accountExpires attribute for accounts which never expire.
+	ldapActiveDirectoryAccountExpiresNever int64 = 0x7FFFFFFFFFFFFFFF
+)
+
+const (
+	// Sub-codes of the data field in the diagnostic message of an Active Directory bind failure.
+	ldapActiveDirectoryBindDataPrefix = "data "
+
+	ldapActiveDirectoryBindDataInvalidCredentials = "52e"
+	ldapActiveDirectoryBindDataPasswordExpired    = "532"
+	ldapActiveDirectoryBindDataAccountDisabled    = "533"
+	ldapActiveDirectoryBindDataAccountExpired     = "701"
+	ldapActiveDirectoryBindDataMustResetPassword  = "773"
+	ldapActiveDirectoryBindDataAccountLocked      = "775"
+)
+
+const (
+	ldapPoolCloseReasonError       = "error"
+	ldapPoolCloseReasonHealthCheck = "health_check"
+	ldapPoolCloseReasonIdle        = "idle"
+	ldapPoolCloseReasonMaxLifetime = "max_lifetime"
+	ldapPoolCloseReasonShutdown    = "shutdown"
+)
+
+const (
+	// ldapMatchingRuleInChain is the LDAP_MATCHING_RULE_IN_CHAIN extensible match component which the in_chain
+	// placeholder is replaced with, matching the groups the distinguished name is a direct or nested member of.
+	ldapMatchingRuleInChain = ":1.2.840.113556.1.4.1941:"
 )
 
 const (
 	ldapBaseObjectFilter = "(objectClass=*)"
+
+	// ldapNoAttributes is the special attribute list which requests no attributes be returned, see RFC4511 4.5.1.8.
+	ldapNoAttributes = "1.1"
 )
 
 const (
@@ -76,6 +131,7 @@ const (
 	ldapPlaceholderDateTimeGeneralized          = "{date-time:generalized}"
 	ldapPlaceholderDateTimeMicrosoftNTTimeEpoch = "{date-time:microsoft-nt}"
 	ldapPlaceholderDateTimeUnixEpoch            = "{date-time:unix}"
+	ldapPlaceholderInChain                      = "{in_chain}"
 )
 
 const (
@@ -98,6 +154,19 @@ var (
 	// ErrUserNotFound indicates the user wasn't found in the authentication backend.
 	ErrUserNotFound = errors.New("user not found")
 
+	// ErrAccountDisabled indicates the user account is disabled in the authentication backend.
+	ErrAccountDisabled = errors.New("account is disabled")
+
+	// ErrAccountLocked indicates the user account is locked out in the authentication backend.
+	ErrAccountLocked = errors.New("account is locked")
+
+	// ErrAccountExpired indicates the user account has expired in the authentication backend.
+	ErrAccountExpired = errors.New("account has expired")
+
+	// ErrPasswordMustChange indicates the password of the user is correct but has expired or must be changed before
+	// the user can sign in.
+	ErrPasswordMustChange = errors.New("password must be changed")
+
 	// ErrNoContent is returned when the file is empty.
 	ErrNoContent = errors.New("no file content")
 )
@@ -107,3 +176,11 @@ const fileAuthenticationMode = 0600
 // OWASP recommends to escape some special characters.
 // https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/LDAP_Injection_Prevention_Cheat_Sheet.md
 const specialLDAPRunes = ",#+<>;\"="
+
+const (
+	// digestBCryptSaltKeyLength is the length of the concatenated salt and key of a bcrypt digest.
+	digestBCryptSaltKeyLength = 53
+
+	// digestBCryptSaltLength is the length of the salt of a bcrypt digest.
+	digestBCryptSaltLength = 22
+)
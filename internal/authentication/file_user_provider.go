@@ -17,12 +17,14 @@ import (
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // FileUserProvider is a provider reading details from a file.
 type FileUserProvider struct {
 	config        *schema.FileAuthenticationBackend
 	hash          algorithm.Hash
+	hashParams    *digestParameters
 	database      *FileUserDatabase
 	mutex         *sync.Mutex
 	timeoutReload time.Time
@@ -71,11 +73,27 @@ func (p *FileUserProvider) CheckUserPassword(username string, password string) (
 		return false, err
 	}
 
+	// Disabled users are owned by this provider so they must not be reported as not found, otherwise the next provider
+	// in a chain is able to authenticate them. The disabled state is only disclosed if the password is correct.
+	if match, err = details.Digest.MatchAdvanced(password); err != nil || !match {
+		return false, err
+	}
+
 	if details.Disabled {
-		return false, ErrUserNotFound
+		return false, ErrAccountDisabled
 	}
 
-	return details.Digest.MatchAdvanced(password)
+	// The password has just been verified so if the digest doesn't use the configured algorithm and parameters it's
+	// transparently upgraded. Failing to do so must not prevent the user from logging in.
+	if p.shouldUpgradeDigest(details.Digest) {
+		if err = p.setPassword(details, password); err != nil {
+			logging.Logger().WithError(err).Warnf("Failed to upgrade the password digest for user '%s'", details.Username)
+		} else {
+			logging.Logger().Debugf("Upgraded the password digest for user '%s' to the configured algorithm and parameters", details.Username)
+		}
+	}
+
+	return true, nil
 }
 
 // GetDetails retrieve the groups a user belongs to.
@@ -87,7 +105,7 @@ func (p *FileUserProvider) GetDetails(username string) (details *UserDetails, er
 	}
 
 	if d.Disabled {
-		return nil, ErrUserNotFound
+		return nil, ErrAccountDisabled
 	}
 
 	return d.ToUserDetails(), nil
@@ -102,10 +120,21 @@ func (p *FileUserProvider) UpdatePassword(username string, newPassword string) (
 	}
 
 	if details.Disabled {
-		return ErrUserNotFound
+		return ErrAccountDisabled
 	}
 
-	if details.Digest, err = p.hash.Hash(newPassword); err != nil {
+	return p.setPassword(details, newPassword)
+}
+
+// shouldUpgradeDigest returns true if the digest doesn't use the configured algorithm, variant, and parameters.
+func (p *FileUserProvider) shouldUpgradeDigest(digest algorithm.Digest) bool {
+	parameters, err := decodeDigestParameters(digest.Encode())
+
+	return err != nil || !parameters.Equal(p.hashParams)
+}
+
+func (p *FileUserProvider) setPassword(details DatabaseUserDetails, password string) (err error) {
+	if details.Digest, err = p.hash.Hash(password); err != nil {
 		return err
 	}
 
@@ -136,6 +165,16 @@ func (p *FileUserProvider) StartupCheck() (err error) {
 		return err
 	}
 
+	var digest algorithm.Digest
+
+	if digest, err = p.hash.Hash(utils.RandomString(16, utils.CharSetAlphaNumeric, true)); err != nil {
+		return fmt.Errorf("failed to determine hash parameters: %w", err)
+	}
+
+	if p.hashParams, err = decodeDigestParameters(digest.Encode()); err != nil {
+		return fmt.Errorf("failed to determine hash parameters: %w", err)
+	}
+
 	p.database = NewFileUserDatabase(p.config.Path, p.config.Search.Email, p.config.Search.CaseInsensitive)
 
 	if err = p.database.Load(); err != nil {
@@ -3,6 +3,7 @@ package authentication
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -72,6 +73,8 @@ func (m *FileUserDatabase) Load() (err error) {
 
 // LoadAliases performs the loading of alias information from the database.
 func (m *FileUserDatabase) LoadAliases() (err error) {
+	m.Emails, m.Aliases = map[string]string{}, map[string]string{}
+
 	if m.SearchEmail || m.SearchCI {
 		for k, user := range m.Users {
 			if m.SearchEmail && user.Email != "" {
@@ -184,6 +187,15 @@ func (m *FileUserDatabase) SetUserDetails(username string, details *DatabaseUser
 	m.Unlock()
 }
 
+// DeleteUserDetails removes the DatabaseUserDetails for a given user.
+func (m *FileUserDatabase) DeleteUserDetails(username string) {
+	m.Lock()
+
+	delete(m.Users, username)
+
+	m.Unlock()
+}
+
 // ToDatabaseModel converts the FileUserDatabase into the DatabaseModel for saving.
 func (m *FileUserDatabase) ToDatabaseModel() (model *DatabaseModel) {
 	model = &DatabaseModel{
@@ -209,6 +221,7 @@ type DatabaseUserDetails struct {
 	DisplayName string
 	Email       string
 	Groups      []string
+	Attributes  map[string][]string
 }
 
 // ToUserDetails converts DatabaseUserDetails into a *UserDetails given a username.
@@ -218,6 +231,7 @@ func (m DatabaseUserDetails) ToUserDetails() (details *UserDetails) {
 		DisplayName: m.DisplayName,
 		Emails:      []string{m.Email},
 		Groups:      m.Groups,
+		Attributes:  m.Attributes,
 	}
 }
 
@@ -228,6 +242,8 @@ func (m DatabaseUserDetails) ToUserDetailsModel() (model UserDetailsModel) {
 		DisplayName:    m.DisplayName,
 		Email:          m.Email,
 		Groups:         m.Groups,
+		Disabled:       m.Disabled,
+		Attributes:     attributesToModel(m.Attributes),
 	}
 }
 
@@ -285,26 +301,56 @@ func (m *DatabaseModel) Read(filePath string) (err error) {
 	return nil
 }
 
-// Write a DatabaseModel to disk.
+// Write a DatabaseModel to disk. The content is written to a temporary file in the same directory which is renamed
+// over the existing file once fully written so that a partially written database is never observed.
 func (m *DatabaseModel) Write(fileName string) (err error) {
 	var (
 		data []byte
+		file *os.File
 	)
 
 	if data, err = yaml.Marshal(m); err != nil {
 		return err
 	}
 
-	return os.WriteFile(fileName, data, fileAuthenticationMode)
+	if file, err = os.CreateTemp(filepath.Dir(fileName), fmt.Sprintf(".%s.*.tmp", filepath.Base(fileName))); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	if err = file.Chmod(fileAuthenticationMode); err != nil {
+		return err
+	}
+
+	if _, err = file.Write(data); err != nil {
+		return err
+	}
+
+	if err = file.Sync(); err != nil {
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), fileName)
 }
 
 // UserDetailsModel is the model of user details in the file database.
 type UserDetailsModel struct {
-	HashedPassword string   `yaml:"password" valid:"required"`
-	DisplayName    string   `yaml:"displayname" valid:"required"`
-	Email          string   `yaml:"email"`
-	Groups         []string `yaml:"groups"`
-	Disabled       bool     `yaml:"disabled"`
+	HashedPassword string         `yaml:"password" valid:"required"`
+	DisplayName    string         `yaml:"displayname" valid:"required"`
+	Email          string         `yaml:"email"`
+	Groups         []string       `yaml:"groups"`
+	Disabled       bool           `yaml:"disabled"`
+	Attributes     map[string]any `yaml:"attributes,omitempty"`
 }
 
 // ToDatabaseUserDetailsModel converts a UserDetailsModel into a *DatabaseUserDetails.
@@ -322,5 +368,54 @@ func (m UserDetailsModel) ToDatabaseUserDetailsModel(username string) (model *Da
 		DisplayName: m.DisplayName,
 		Email:       m.Email,
 		Groups:      m.Groups,
+		Attributes:  attributesFromModel(m.Attributes),
 	}, nil
 }
+
+// attributesFromModel converts the attributes of a UserDetailsModel which are either a single value or a list of
+// values into their string representations.
+func attributesFromModel(model map[string]any) (attributes map[string][]string) {
+	if len(model) == 0 {
+		return nil
+	}
+
+	attributes = make(map[string][]string, len(model))
+
+	for name, value := range model {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []any:
+			values := make([]string, len(v))
+
+			for i, item := range v {
+				values[i] = fmt.Sprint(item)
+			}
+
+			attributes[name] = values
+		default:
+			attributes[name] = []string{fmt.Sprint(v)}
+		}
+	}
+
+	return attributes
+}
+
+// attributesToModel converts the attributes of a DatabaseUserDetails into the format used by the UserDetailsModel.
+func attributesToModel(attributes map[string][]string) (model map[string]any) {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	model = make(map[string]any, len(attributes))
+
+	for name, values := range attributes {
+		if len(values) == 1 {
+			model[name] = values[0]
+		} else {
+			model[name] = values
+		}
+	}
+
+	return model
+}
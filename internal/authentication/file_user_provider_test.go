@@ -3,6 +3,7 @@ package authentication
 import (
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
@@ -144,6 +145,41 @@ func TestShouldRetrieveUserDetails(t *testing.T) {
 		assert.Equal(t, "john", details.Username)
 		assert.Equal(t, []string{"john.doe@authelia.com"}, details.Emails)
 		assert.Equal(t, []string{"admins", "dev"}, details.Groups)
+		assert.Equal(t, map[string][]string{
+			"department":      {"engineering"},
+			"employee_number": {"1234"},
+			"phone":           {"+1 555 0100", "+1 555 0101"},
+		}, details.Attributes)
+
+		details, err = provider.GetDetails("harry")
+		assert.NoError(t, err)
+		assert.Nil(t, details.Attributes)
+	})
+}
+
+func TestShouldPreserveAttributesWhenUpdatingPassword(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+
+		provider := NewFileUserProvider(&config)
+
+		assert.NoError(t, provider.StartupCheck())
+
+		assert.NoError(t, provider.UpdatePassword("john", "newpassword"))
+
+		// Reset the provider to force a read from disk.
+		provider = NewFileUserProvider(&config)
+
+		assert.NoError(t, provider.StartupCheck())
+
+		details, err := provider.GetDetails("john")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"department":      {"engineering"},
+			"employee_number": {"1234"},
+			"phone":           {"+1 555 0100", "+1 555 0101"},
+		}, details.Attributes)
 	})
 }
 
@@ -223,6 +259,213 @@ func TestShouldUpdatePasswordHashingAlgorithmToSHA512(t *testing.T) {
 	})
 }
 
+func TestShouldUpgradePasswordDigestOnLogin(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+
+		provider := NewFileUserProvider(&config)
+
+		require.NoError(t, provider.StartupCheck())
+
+		assert.True(t, strings.HasPrefix(provider.database.Users["harry"].Digest.Encode(), "$6$"))
+
+		ok, err := provider.CheckUserPassword("harry", "password")
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		// Reset the provider to force a read from disk.
+		provider = NewFileUserProvider(&config)
+
+		require.NoError(t, provider.StartupCheck())
+
+		digest := provider.database.Users["harry"].Digest.Encode()
+
+		assert.True(t, strings.HasPrefix(digest, "$argon2id$v=19$m=64,t=3,p=4$"))
+
+		ok, err = provider.CheckUserPassword("harry", "password")
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		details, err := provider.GetDetails("harry")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Harry Potter", details.DisplayName)
+		assert.Equal(t, []string{"harry.potter@authelia.com"}, details.Emails)
+
+		before, err := os.ReadFile(path)
+
+		require.NoError(t, err)
+
+		ok, err = provider.CheckUserPassword("harry", "password")
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		after, err := os.ReadFile(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestShouldNotUpgradePasswordDigestWhenPasswordIsWrong(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+
+		provider := NewFileUserProvider(&config)
+
+		require.NoError(t, provider.StartupCheck())
+
+		ok, err := provider.CheckUserPassword("harry", "wrong_password")
+
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		content, err := os.ReadFile(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, UserDatabaseContent, content)
+	})
+}
+
+func TestShouldGetDigestParameters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     string
+		expected bool
+	}{
+		{
+			"ShouldMatchArgon2idWithDifferentSaltAndKey",
+			"$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			"$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0$Y2Jpb3JpYmFmb3JpYmJvcmliYWZvcmliYm9yaWJhZm8",
+			true,
+		},
+		{
+			"ShouldNotMatchArgon2idWithDifferentParameters",
+			"$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			"$argon2id$v=19$m=65536,t=1,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			false,
+		},
+		{
+			"ShouldNotMatchArgon2idWithDifferentKeyLength",
+			"$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			"$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs",
+			false,
+		},
+		{
+			"ShouldMatchBCryptWithDifferentSaltAndKey",
+			"$2b$12$1bqfr/yMtN4Ak0bsT.gU9OLx/UVzHtmpC/yyFN.2Uvqbg3sE8CT6W",
+			"$2b$12$gx2Jv3VgeTCNM2ck/dDpYeH/0nPMVTf6hWq1hIpEQVwC6I3g8aaXm",
+			true,
+		},
+		{
+			"ShouldNotMatchBCryptWithDifferentCost",
+			"$2b$12$1bqfr/yMtN4Ak0bsT.gU9OLx/UVzHtmpC/yyFN.2Uvqbg3sE8CT6W",
+			"$2b$10$gx2Jv3VgeTCNM2ck/dDpYeH/0nPMVTf6hWq1hIpEQVwC6I3g8aaXm",
+			false,
+		},
+		{
+			"ShouldNotMatchDifferentAlgorithms",
+			"$2b$12$1bqfr/yMtN4Ak0bsT.gU9OLx/UVzHtmpC/yyFN.2Uvqbg3sE8CT6W",
+			"$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			false,
+		},
+		{
+			"ShouldMatchArgon2idWithDifferentParameterOrder",
+			"$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			"$argon2id$v=19$p=2,t=3,m=65536$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			true,
+		},
+		{
+			"ShouldNotMatchArgon2Variants",
+			"$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			"$argon2i$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM",
+			false,
+		},
+		{
+			"ShouldNotMatchPBKDF2WithDifferentIterations",
+			"$pbkdf2-sha512$310000$c8p78n7pUMln0jzvd4aK4Q$JNRBzwAo0ek5qKn50cFzzvE0DS0N0z1hBzaIDpXfYRQ",
+			"$pbkdf2-sha512$120000$c8p78n7pUMln0jzvd4aK4Q$JNRBzwAo0ek5qKn50cFzzvE0DS0N0z1hBzaIDpXfYRQ",
+			false,
+		},
+		{
+			"ShouldNotMatchSHA2CryptWithDifferentRounds",
+			"$6$rounds=500000$jgiCMRyGXzoqpxS3$w2pJeZnnH8bwW3zzvoMWtTRfQYsHbWbD/hquuQ5vUeIyl9gdwBIt6RWk2S6afBA0DPakbeWgD/4SZPiS0hYtU/",
+			"$6$rounds=50000$jgiCMRyGXzoqpxS3$w2pJeZnnH8bwW3zzvoMWtTRfQYsHbWbD/hquuQ5vUeIyl9gdwBIt6RWk2S6afBA0DPakbeWgD/4SZPiS0hYtU/",
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := decodeDigestParameters(tc.a)
+			require.NoError(t, err)
+
+			b, err := decodeDigestParameters(tc.b)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, a.Equal(b))
+		})
+	}
+}
+
+func TestShouldNotDecodeDigestParametersWithInvalidFormat(t *testing.T) {
+	testCases := []struct {
+		name string
+		have string
+		err  string
+	}{
+		{"ShouldNotDecodePlainText", "password", "digest is not in the modular crypt format"},
+		{"ShouldNotDecodeTooFewSegments", "$plaintext$password", "digest is not in the modular crypt format"},
+		{"ShouldNotDecodeDuplicateParameters", "$argon2id$v=19$m=65536,m=1024,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM", "digest has a duplicate parameter 'm'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parameters, err := decodeDigestParameters(tc.have)
+
+			assert.Nil(t, parameters)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestShouldNotUpgradePasswordDigestWhenParametersMatch(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		config.Password = schema.Password{
+			Algorithm: "argon2",
+			Argon2: schema.Argon2Password{
+				Variant:     "argon2id",
+				Iterations:  3,
+				Memory:      65536,
+				Parallelism: 2,
+				KeyLength:   32,
+				SaltLength:  12,
+			},
+		}
+
+		provider := NewFileUserProvider(&config)
+
+		require.NoError(t, provider.StartupCheck())
+
+		ok, err := provider.CheckUserPassword("john", "password")
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		content, err := os.ReadFile(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, UserDatabaseContent, content)
+	})
+}
+
 func TestShouldRaiseWhenLoadingMalformedDatabaseForFirstTime(t *testing.T) {
 	WithDatabase(MalformedUserDatabaseContent, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
@@ -317,10 +560,101 @@ func TestShouldNotAllowLoginOfDisabledUsers(t *testing.T) {
 		ok, err := provider.CheckUserPassword("dis", "password")
 
 		assert.False(t, ok)
-		assert.EqualError(t, err, "user not found")
+		assert.ErrorIs(t, err, ErrAccountDisabled)
+
+		ok, err = provider.CheckUserPassword("dis", "wrong")
+
+		assert.False(t, ok)
+		assert.NoError(t, err)
+
+		details, err := provider.GetDetails("dis")
+
+		assert.Nil(t, details)
+		assert.ErrorIs(t, err, ErrAccountDisabled)
 	})
 }
 
+func TestShouldPreserveDisabledUsersWhenSaving(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+
+		provider := NewFileUserProvider(&config)
+
+		assert.NoError(t, provider.StartupCheck())
+
+		assert.NoError(t, provider.UpdatePassword("john", "newpassword"))
+
+		database := NewFileUserDatabase(path, false, false)
+
+		require.NoError(t, database.Load())
+
+		details, err := database.GetUserDetails("dis")
+
+		require.NoError(t, err)
+		assert.True(t, details.Disabled)
+	})
+}
+
+func TestShouldDeleteUserDetails(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		database := NewFileUserDatabase(path, true, false)
+
+		require.NoError(t, database.Load())
+
+		database.DeleteUserDetails("john")
+
+		require.NoError(t, database.LoadAliases())
+		require.NoError(t, database.Save())
+
+		database = NewFileUserDatabase(path, true, false)
+
+		require.NoError(t, database.Load())
+
+		_, err := database.GetUserDetails("john")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+
+		_, err = database.GetUserDetails("john.doe@authelia.com")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+
+		_, err = database.GetUserDetails("harry")
+		assert.NoError(t, err)
+	})
+}
+
+func TestShouldWriteDatabaseAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users_database.yml")
+
+	require.NoError(t, os.WriteFile(path, UserDatabaseContent, 0644))
+
+	database := NewFileUserDatabase(path, false, false)
+
+	require.NoError(t, database.Load())
+	require.NoError(t, database.Save())
+
+	entries, err := os.ReadDir(dir)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "users_database.yml", entries[0].Name())
+
+	info, err := os.Stat(path)
+
+	require.NoError(t, err)
+
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(fileAuthenticationMode), info.Mode().Perm())
+	}
+
+	assert.Error(t, (&DatabaseModel{}).Write(filepath.Join(dir, "missing", "users_database.yml")))
+
+	entries, err = os.ReadDir(dir)
+
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
 func TestShouldErrorOnInvalidCaseSensitiveFile(t *testing.T) {
 	WithDatabase(UserDatabaseContentInvalidSearchCaseInsenstive, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
@@ -452,6 +786,12 @@ users:
     groups:
       - admins
       - dev
+    attributes:
+      department: engineering
+      employee_number: 1234
+      phone:
+        - "+1 555 0100"
+        - "+1 555 0101"
 
   harry:
     displayname: "Harry Potter"
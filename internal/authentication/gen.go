@@ -5,3 +5,4 @@ package authentication
 
 //go:generate mockgen -package authentication -destination ldap_client_mock.go -mock_names LDAPClient=MockLDAPClient github.com/authelia/authelia/v4/internal/authentication LDAPClient
 //go:generate mockgen -package authentication -destination ldap_client_factory_mock.go -mock_names LDAPClientFactory=MockLDAPClientFactory github.com/authelia/authelia/v4/internal/authentication LDAPClientFactory
+//go:generate mockgen -package authentication -destination storage_user_provider_mock.go -mock_names UserDatabaseProvider=MockUserDatabaseProvider github.com/authelia/authelia/v4/internal/storage UserDatabaseProvider
@@ -0,0 +1,257 @@
+package authentication
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ErrLDAPPoolTimeout indicates no connection became available in the LDAP connection pool before the timeout.
+var ErrLDAPPoolTimeout = errors.New("timeout waiting for an available connection from the pool")
+
+// NewLDAPClientPool creates a new bounded pool of LDAP connections which are created with the dial func.
+func NewLDAPClientPool(config schema.LDAPAuthenticationBackendPooling, dial func() (LDAPClient, error)) (pool *LDAPClientPool) {
+	return &LDAPClientPool{
+		config: config,
+		dial:   dial,
+		clock:  &utils.RealClock{},
+		slots:  make(chan struct{}, config.Count),
+	}
+}
+
+// LDAPClientPool is a bounded pool of LDAP connections bound as the service account. Connections retrieved from the
+// pool are returned to it when they are closed.
+type LDAPClientPool struct {
+	config  schema.LDAPAuthenticationBackendPooling
+	dial    func() (LDAPClient, error)
+	clock   utils.Clock
+	metrics LDAPPoolMetricsRecorder
+
+	slots chan struct{}
+
+	mu   sync.Mutex
+	idle []*ldapPoolConn
+	open int
+}
+
+// SetMetricsRecorder sets the metrics recorder used to record the pool metrics.
+func (p *LDAPClientPool) SetMetricsRecorder(metrics LDAPPoolMetricsRecorder) {
+	p.metrics = metrics
+}
+
+// Get retrieves an idle healthy connection from the pool or creates a new one if none are idle. It waits for a
+// connection to be returned to the pool when the pool is at capacity.
+func (p *LDAPClientPool) Get() (client LDAPClient, err error) {
+	start := p.clock.Now()
+
+	timer := time.NewTimer(p.config.Timeout)
+
+	select {
+	case p.slots <- struct{}{}:
+		timer.Stop()
+	case <-timer.C:
+		p.recordAcquire(false, start)
+
+		return nil, ErrLDAPPoolTimeout
+	}
+
+	for conn := p.pop(); conn != nil; conn = p.pop() {
+		if reason, ok := p.healthy(conn); !ok {
+			p.discard(conn, reason)
+
+			continue
+		}
+
+		p.recordAcquire(true, start)
+
+		return &ldapPooledClient{LDAPClient: conn.client, pool: p, conn: conn}, nil
+	}
+
+	if client, err = p.dial(); err != nil {
+		<-p.slots
+
+		p.recordAcquire(false, start)
+
+		return nil, err
+	}
+
+	now := p.clock.Now()
+
+	conn := &ldapPoolConn{client: client, created: now, used: now}
+
+	p.mu.Lock()
+	p.open++
+	p.mu.Unlock()
+
+	p.recordAcquire(true, start)
+
+	return &ldapPooledClient{LDAPClient: client, pool: p, conn: conn}, nil
+}
+
+// Close closes all idle connections in the pool.
+func (p *LDAPClientPool) Close() {
+	p.mu.Lock()
+
+	idle := p.idle
+	p.idle = nil
+
+	p.mu.Unlock()
+
+	for _, conn := range idle {
+		p.discard(conn, ldapPoolCloseReasonShutdown)
+	}
+}
+
+func (p *LDAPClientPool) put(conn *ldapPoolConn) {
+	defer func() {
+		<-p.slots
+	}()
+
+	now := p.clock.Now()
+
+	switch {
+	case conn.client.IsClosing():
+		p.discard(conn, ldapPoolCloseReasonError)
+	case p.expired(conn, now):
+		p.discard(conn, ldapPoolCloseReasonMaxLifetime)
+	default:
+		conn.used = now
+
+		p.mu.Lock()
+		p.idle = append(p.idle, conn)
+		p.mu.Unlock()
+
+		p.evict(now)
+	}
+}
+
+// pop takes the most recently used idle connection from the pool.
+func (p *LDAPClientPool) pop() (conn *ldapPoolConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if n := len(p.idle); n != 0 {
+		conn, p.idle = p.idle[n-1], p.idle[:n-1]
+	}
+
+	return conn
+}
+
+// evict closes the idle connections which have exceeded the idle timeout or max lifetime.
+func (p *LDAPClientPool) evict(now time.Time) {
+	var evicted []*ldapPoolConn
+
+	p.mu.Lock()
+
+	idle := make([]*ldapPoolConn, 0, len(p.idle))
+
+	for _, conn := range p.idle {
+		if now.Sub(conn.used) > p.config.IdleTimeout || p.expired(conn, now) {
+			evicted = append(evicted, conn)
+
+			continue
+		}
+
+		idle = append(idle, conn)
+	}
+
+	p.idle = idle
+
+	p.mu.Unlock()
+
+	for _, conn := range evicted {
+		p.discard(conn, ldapPoolCloseReasonIdle)
+	}
+
+	p.recordConnections()
+}
+
+func (p *LDAPClientPool) healthy(conn *ldapPoolConn) (reason string, ok bool) {
+	now := p.clock.Now()
+
+	switch {
+	case conn.client.IsClosing():
+		return ldapPoolCloseReasonError, false
+	case p.expired(conn, now):
+		return ldapPoolCloseReasonMaxLifetime, false
+	case now.Sub(conn.used) > p.config.IdleTimeout:
+		return ldapPoolCloseReasonIdle, false
+	case now.Sub(conn.used) > p.config.HealthCheckInterval:
+		request := ldap.NewSearchRequest("", ldap.ScopeBaseObject, ldap.NeverDerefAliases,
+			1, 0, false, ldapBaseObjectFilter, []string{ldapNoAttributes}, nil)
+
+		if _, err := conn.client.Search(request); err != nil {
+			return ldapPoolCloseReasonHealthCheck, false
+		}
+	}
+
+	return "", true
+}
+
+func (p *LDAPClientPool) expired(conn *ldapPoolConn, now time.Time) bool {
+	return now.Sub(conn.created) > p.config.MaxLifetime
+}
+
+func (p *LDAPClientPool) discard(conn *ldapPoolConn, reason string) {
+	conn.client.Close()
+
+	p.mu.Lock()
+	p.open--
+	p.mu.Unlock()
+
+	if p.metrics != nil {
+		p.metrics.RecordLDAPPoolConnectionClosed(reason)
+	}
+
+	p.recordConnections()
+}
+
+func (p *LDAPClientPool) recordAcquire(success bool, start time.Time) {
+	if p.metrics == nil {
+		return
+	}
+
+	p.metrics.RecordLDAPPoolAcquire(success, p.clock.Now().Sub(start))
+
+	p.recordConnections()
+}
+
+func (p *LDAPClientPool) recordConnections() {
+	if p.metrics == nil {
+		return
+	}
+
+	p.mu.Lock()
+	open, idle := p.open, len(p.idle)
+	p.mu.Unlock()
+
+	p.metrics.RecordLDAPPoolConnections(open, idle)
+}
+
+// ldapPoolConn is a connection owned by the LDAPClientPool.
+type ldapPoolConn struct {
+	client  LDAPClient
+	created time.Time
+	used    time.Time
+}
+
+// ldapPooledClient is a LDAPClient which returns the connection to the pool it was retrieved from when closed.
+type ldapPooledClient struct {
+	LDAPClient
+
+	pool *LDAPClientPool
+	conn *ldapPoolConn
+	once sync.Once
+}
+
+// Close returns the connection to the pool. Subsequent calls are a no-op.
+func (c *ldapPooledClient) Close() {
+	c.once.Do(func() {
+		c.pool.put(c.conn)
+	})
+}
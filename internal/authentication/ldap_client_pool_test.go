@@ -0,0 +1,361 @@
+package authentication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+type testLDAPPoolMetricsRecorder struct {
+	open, idle int
+	acquired   int
+	failed     int
+	closed     map[string]int
+}
+
+func (r *testLDAPPoolMetricsRecorder) RecordLDAPPoolConnections(open, idle int) {
+	r.open, r.idle = open, idle
+}
+
+func (r *testLDAPPoolMetricsRecorder) RecordLDAPPoolAcquire(success bool, _ time.Duration) {
+	if success {
+		r.acquired++
+	} else {
+		r.failed++
+	}
+}
+
+func (r *testLDAPPoolMetricsRecorder) RecordLDAPPoolConnectionClosed(reason string) {
+	r.closed[reason]++
+}
+
+type LDAPClientPoolSuite struct {
+	suite.Suite
+
+	ctrl     *gomock.Controller
+	clock    *utils.TestingClock
+	recorder *testLDAPPoolMetricsRecorder
+	pool     *LDAPClientPool
+
+	// clients are returned by the dial function of the pool in order, it fails once they're exhausted.
+	clients []LDAPClient
+	dials   int
+}
+
+func (s *LDAPClientPoolSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clients, s.dials = nil, 0
+
+	s.pool = NewLDAPClientPool(schema.LDAPAuthenticationBackendPooling{
+		Enable:              true,
+		Count:               1,
+		Timeout:             time.Millisecond * 10,
+		IdleTimeout:         time.Minute,
+		MaxLifetime:         time.Hour,
+		HealthCheckInterval: time.Second * 30,
+	}, func() (LDAPClient, error) {
+		if s.dials >= len(s.clients) {
+			return nil, errors.New("dial failed")
+		}
+
+		client := s.clients[s.dials]
+
+		s.dials++
+
+		return client, nil
+	})
+
+	s.clock = &utils.TestingClock{}
+	s.clock.Set(time.Unix(1000000, 0))
+
+	s.recorder = &testLDAPPoolMetricsRecorder{closed: map[string]int{}}
+
+	s.pool.clock = s.clock
+	s.pool.SetMetricsRecorder(s.recorder)
+}
+
+func (s *LDAPClientPoolSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *LDAPClientPoolSuite) TestShouldReuseConnections() {
+	mockClient := NewMockLDAPClient(s.ctrl)
+	mockClient.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	s.clients = []LDAPClient{mockClient}
+
+	for i := 0; i < 3; i++ {
+		client, err := s.pool.Get()
+
+		s.Require().NoError(err)
+
+		client.Close()
+	}
+
+	s.Equal(1, s.dials)
+	s.Equal(3, s.recorder.acquired)
+	s.Equal(1, s.recorder.open)
+	s.Equal(1, s.recorder.idle)
+
+	mockClient.EXPECT().Close()
+
+	s.pool.Close()
+
+	s.Equal(0, s.recorder.open)
+	s.Equal(0, s.recorder.idle)
+	s.Equal(1, s.recorder.closed[ldapPoolCloseReasonShutdown])
+}
+
+func (s *LDAPClientPoolSuite) TestShouldBeClosedThroughWrappingProviders() {
+	mockClient := NewMockLDAPClient(s.ctrl)
+	mockClient.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	s.clients = []LDAPClient{mockClient}
+
+	client, err := s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+
+	chain := NewChainUserProvider()
+	chain.Add(schema.AuthenticationBackendLDAP, &LDAPUserProvider{pool: s.pool})
+
+	var provider UserProvider = NewCachingUserProvider(schema.AuthenticationBackendCache{}, chain)
+
+	closer, ok := provider.(UserProviderCloser)
+
+	s.Require().True(ok)
+
+	mockClient.EXPECT().Close()
+
+	closer.Close()
+
+	s.Equal(1, s.recorder.closed[ldapPoolCloseReasonShutdown])
+}
+
+func (s *LDAPClientPoolSuite) TestShouldIgnoreDuplicateClose() {
+	mockClient := NewMockLDAPClient(s.ctrl)
+	mockClient.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	s.clients = []LDAPClient{mockClient}
+
+	client, err := s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+	client.Close()
+
+	client, err = s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+}
+
+func (s *LDAPClientPoolSuite) TestShouldTimeoutWhenExhausted() {
+	mockClient := NewMockLDAPClient(s.ctrl)
+	mockClient.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	s.clients = []LDAPClient{mockClient}
+
+	client, err := s.pool.Get()
+
+	s.Require().NoError(err)
+
+	_, err = s.pool.Get()
+
+	s.ErrorIs(err, ErrLDAPPoolTimeout)
+	s.Equal(1, s.recorder.failed)
+
+	client.Close()
+
+	client, err = s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+}
+
+func (s *LDAPClientPoolSuite) TestShouldReleaseSlotOnDialError() {
+	for i := 0; i < 2; i++ {
+		_, err := s.pool.Get()
+
+		s.EqualError(err, "dial failed")
+	}
+
+	s.Equal(2, s.recorder.failed)
+	s.Equal(0, s.recorder.open)
+}
+
+func (s *LDAPClientPoolSuite) TestShouldDiscardClosingConnections() {
+	mockClientOne := NewMockLDAPClient(s.ctrl)
+	mockClientTwo := NewMockLDAPClient(s.ctrl)
+
+	gomock.InOrder(
+		mockClientOne.EXPECT().IsClosing().Return(true),
+		mockClientOne.EXPECT().Close(),
+	)
+
+	mockClientTwo.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	s.clients = []LDAPClient{mockClientOne, mockClientTwo}
+
+	client, err := s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+
+	client, err = s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+
+	s.Equal(2, s.dials)
+	s.Equal(1, s.recorder.closed[ldapPoolCloseReasonError])
+	s.Equal(1, s.recorder.open)
+}
+
+func (s *LDAPClientPoolSuite) TestShouldHealthCheckConnections() {
+	mockClientOne := NewMockLDAPClient(s.ctrl)
+	mockClientTwo := NewMockLDAPClient(s.ctrl)
+
+	mockClientOne.EXPECT().IsClosing().Return(false).AnyTimes()
+	mockClientTwo.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	s.clients = []LDAPClient{mockClientOne, mockClientTwo}
+
+	client, err := s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+
+	s.clock.Set(s.clock.Now().Add(time.Second * 31))
+
+	mockClientOne.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			s.Equal("", request.BaseDN)
+			s.Equal([]string{ldapNoAttributes}, request.Attributes)
+
+			return &ldap.SearchResult{}, nil
+		})
+
+	client, err = s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+
+	s.clock.Set(s.clock.Now().Add(time.Second * 31))
+
+	gomock.InOrder(
+		mockClientOne.EXPECT().Search(gomock.Any()).Return(nil, errors.New("connection reset")),
+		mockClientOne.EXPECT().Close(),
+	)
+
+	client, err = s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+
+	s.Equal(2, s.dials)
+	s.Equal(1, s.recorder.closed[ldapPoolCloseReasonHealthCheck])
+}
+
+func (s *LDAPClientPoolSuite) TestShouldExpireConnections() {
+	mockClientOne := NewMockLDAPClient(s.ctrl)
+	mockClientTwo := NewMockLDAPClient(s.ctrl)
+	mockClientThree := NewMockLDAPClient(s.ctrl)
+
+	mockClientOne.EXPECT().IsClosing().Return(false).AnyTimes()
+	mockClientTwo.EXPECT().IsClosing().Return(false).AnyTimes()
+	mockClientThree.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	s.clients = []LDAPClient{mockClientOne, mockClientTwo, mockClientThree}
+
+	client, err := s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+
+	s.clock.Set(s.clock.Now().Add(time.Minute * 2))
+
+	mockClientOne.EXPECT().Close()
+
+	client, err = s.pool.Get()
+
+	s.Require().NoError(err)
+
+	s.clock.Set(s.clock.Now().Add(time.Hour * 2))
+
+	mockClientTwo.EXPECT().Close()
+
+	client.Close()
+
+	client, err = s.pool.Get()
+
+	s.Require().NoError(err)
+
+	client.Close()
+
+	s.Equal(3, s.dials)
+	s.Equal(1, s.recorder.closed[ldapPoolCloseReasonIdle])
+	s.Equal(1, s.recorder.closed[ldapPoolCloseReasonMaxLifetime])
+}
+
+func TestLDAPUserProviderShouldUsePoolWhenEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+	mockClient := NewMockLDAPClient(ctrl)
+
+	config := schema.LDAPAuthenticationBackend{
+		URL:      "ldap://127.0.0.1:389",
+		User:     "cn=admin,dc=example,dc=com",
+		Password: "password",
+		Pooling:  schema.DefaultLDAPAuthenticationBackendPooling,
+	}
+
+	config.Pooling.Enable = true
+
+	provider := NewLDAPUserProviderWithFactory(config, false, nil, mockFactory)
+
+	gomock.InOrder(
+		mockFactory.EXPECT().
+			DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+			Return(mockClient, nil),
+		mockClient.EXPECT().
+			Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+			Return(nil),
+	)
+
+	mockClient.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	for i := 0; i < 2; i++ {
+		client, err := provider.connect()
+
+		require.NoError(t, err)
+
+		client.Close()
+	}
+}
+
+func TestRunLDAPClientPoolSuite(t *testing.T) {
+	suite.Run(t, new(LDAPClientPoolSuite))
+}
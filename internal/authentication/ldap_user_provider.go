@@ -23,11 +23,14 @@ type LDAPUserProvider struct {
 	dialOpts  []ldap.DialOpt
 	log       *logrus.Logger
 	factory   LDAPClientFactory
+	pool      *LDAPClientPool
 
 	clock utils.Clock
 
 	disableResetPassword bool
 
+	extraAttributes []schema.AuthenticationBackendExtraAttribute
+
 	// Automatically detected LDAP features.
 	features LDAPSupportedFeatures
 
@@ -51,6 +54,8 @@ type LDAPUserProvider struct {
 func NewLDAPUserProvider(config schema.AuthenticationBackend, certPool *x509.CertPool) (provider *LDAPUserProvider) {
 	provider = NewLDAPUserProviderWithFactory(*config.LDAP, config.PasswordReset.Disable, certPool, NewProductionLDAPClientFactory())
 
+	provider.setExtraAttributes(config.ExtraAttributes)
+
 	return provider
 }
 
@@ -84,12 +89,30 @@ func NewLDAPUserProviderWithFactory(config schema.LDAPAuthenticationBackend, dis
 		clock:                &utils.RealClock{},
 	}
 
+	if config.Pooling.Enable {
+		provider.pool = NewLDAPClientPool(config.Pooling, provider.dial)
+	}
+
 	provider.parseDynamicUsersConfiguration()
 	provider.parseDynamicGroupsConfiguration()
 
 	return provider
 }
 
+// SetMetricsRecorder sets the metrics recorder used to record the connection pool metrics.
+func (p *LDAPUserProvider) SetMetricsRecorder(metrics LDAPPoolMetricsRecorder) {
+	if p.pool != nil {
+		p.pool.SetMetricsRecorder(metrics)
+	}
+}
+
+// Close closes the idle connections of the connection pool.
+func (p *LDAPUserProvider) Close() {
+	if p.pool != nil {
+		p.pool.Close()
+	}
+}
+
 // CheckUserPassword checks if provided password matches for the given user.
 func (p *LDAPUserProvider) CheckUserPassword(username string, password string) (valid bool, err error) {
 	var (
@@ -107,7 +130,11 @@ func (p *LDAPUserProvider) CheckUserPassword(username string, password string) (
 		return false, err
 	}
 
-	if clientUser, err = p.connectCustom(p.config.URL, profile.DN, password, p.config.StartTLS, p.dialOpts...); err != nil {
+	if clientUser, err = p.connectUser(profile.DN, password); err != nil {
+		if errState := p.getBindAccountStateError(profile, err); errState != nil {
+			return false, fmt.Errorf("authentication failed. Cause: %w: %v", errState, err)
+		}
+
 		return false, fmt.Errorf("authentication failed. Cause: %w", err)
 	}
 
@@ -133,39 +160,18 @@ func (p *LDAPUserProvider) GetDetails(username string) (details *UserDetails, er
 		return nil, err
 	}
 
-	var (
-		request *ldap.SearchRequest
-		result  *ldap.SearchResult
-	)
-
-	// Search for the users groups.
-	request = ldap.NewSearchRequest(
-		p.groupsBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
-		0, 0, false, p.resolveGroupsFilter(username, profile), p.groupsAttributes, nil,
-	)
-
-	p.log.
-		WithField("base_dn", request.BaseDN).
-		WithField("filter", request.Filter).
-		WithField("attr", request.Attributes).
-		WithField("scope", request.Scope).
-		WithField("deref", request.DerefAliases).
-		Trace("Performing group search")
-
-	if result, err = p.search(client, request); err != nil {
-		return nil, fmt.Errorf("unable to retrieve groups of user '%s'. Cause: %w", username, err)
+	// Accounts which are disabled or expired must not be able to continue to use existing sessions.
+	switch {
+	case profile.State.Disabled:
+		return nil, ErrAccountDisabled
+	case profile.State.Expired:
+		return nil, ErrAccountExpired
 	}
 
-	groups := make([]string, 0)
-
-	for _, res := range result.Entries {
-		if len(res.Attributes) == 0 {
-			p.log.Warningf("No groups retrieved from LDAP for user %s", username)
-			break
-		}
+	var groups []string
 
-		// Append all values of the document. Normally there should be only one per document.
-		groups = append(groups, res.Attributes[0].Values...)
+	if groups, err = p.getUserGroups(client, username, profile); err != nil {
+		return nil, err
 	}
 
 	return &UserDetails{
@@ -173,6 +179,7 @@ func (p *LDAPUserProvider) GetDetails(username string) (details *UserDetails, er
 		DisplayName: profile.DisplayName,
 		Emails:      profile.Emails,
 		Groups:      groups,
+		Attributes:  profile.Attributes,
 	}, nil
 }
 
@@ -233,11 +240,61 @@ func (p *LDAPUserProvider) UpdatePassword(username, password string) (err error)
 	return nil
 }
 
+// connect returns a connection bound as the service account. The connection is taken from the pool when pooling is
+// enabled, and closing it returns it to the pool.
 func (p *LDAPUserProvider) connect() (client LDAPClient, err error) {
+	if p.pool != nil {
+		return p.pool.Get()
+	}
+
+	return p.dial()
+}
+
+func (p *LDAPUserProvider) dial() (client LDAPClient, err error) {
 	return p.connectCustom(p.config.URL, p.config.User, p.config.Password, p.config.StartTLS, p.dialOpts...)
 }
 
 func (p *LDAPUserProvider) connectCustom(url, username, password string, startTLS bool, opts ...ldap.DialOpt) (client LDAPClient, err error) {
+	if client, err = p.dialCustom(url, startTLS, opts...); err != nil {
+		return nil, err
+	}
+
+	if password == "" {
+		err = client.UnauthenticatedBind(username)
+	} else {
+		err = client.Bind(username, password)
+	}
+
+	if err != nil {
+		client.Close()
+
+		return nil, fmt.Errorf("bind failed with error: %w", err)
+	}
+
+	return client, nil
+}
+
+// connectUser returns a connection bound as the user to check their password. Unlike the service account bind the
+// password policy response control is requested when the server supports it, so the account state can be reported.
+func (p *LDAPUserProvider) connectUser(username, password string) (client LDAPClient, err error) {
+	if !p.features.ControlTypes.PwdPolicy || password == "" {
+		return p.connectCustom(p.config.URL, username, password, p.config.StartTLS, p.dialOpts...)
+	}
+
+	if client, err = p.dialCustom(p.config.URL, p.config.StartTLS, p.dialOpts...); err != nil {
+		return nil, err
+	}
+
+	if err = p.bindPwdPolicy(client, username, password); err != nil {
+		client.Close()
+
+		return nil, fmt.Errorf("bind failed with error: %w", err)
+	}
+
+	return client, nil
+}
+
+func (p *LDAPUserProvider) dialCustom(url string, startTLS bool, opts ...ldap.DialOpt) (client LDAPClient, err error) {
 	if client, err = p.factory.DialURL(url, opts...); err != nil {
 		return nil, fmt.Errorf("dial failed with error: %w", err)
 	}
@@ -250,19 +307,74 @@ func (p *LDAPUserProvider) connectCustom(url, username, password string, startTL
 		}
 	}
 
-	if password == "" {
-		err = client.UnauthenticatedBind(username)
-	} else {
-		err = client.Bind(username, password)
+	return client, nil
+}
+
+// bindPwdPolicy performs a bind which requests the password policy response control. If the control indicates the
+// account is locked or the password must be changed the returned error wraps the error representing that state, even
+// when the bind itself succeeded.
+func (p *LDAPUserProvider) bindPwdPolicy(client LDAPClient, username, password string) (err error) {
+	var result *ldap.SimpleBindResult
+
+	result, err = client.SimpleBind(ldap.NewSimpleBindRequest(username, password, []ldap.Control{ldap.NewControlBeheraPasswordPolicy()}))
+
+	if result == nil {
+		return err
 	}
 
-	if err != nil {
-		client.Close()
+	control, ok := ldap.FindControl(result.Controls, ldap.ControlTypeBeheraPasswordPolicy).(*ldap.ControlBeheraPasswordPolicy)
+	if !ok {
+		return err
+	}
 
-		return nil, fmt.Errorf("bind failed with error: %w", err)
+	var errState error
+
+	switch control.Error {
+	case ldap.BeheraAccountLocked:
+		errState = ErrAccountLocked
+	case ldap.BeheraPasswordExpired, ldap.BeheraChangeAfterReset:
+		errState = ErrPasswordMustChange
+	default:
+		return err
 	}
 
-	return client, nil
+	if err == nil {
+		return fmt.Errorf("%w: %s", errState, control.ErrorString)
+	}
+
+	return fmt.Errorf("%w: %v", errState, err)
+}
+
+// getBindAccountStateError returns the error which represents the account state of the profile after a failed bind.
+// The account state is only disclosed when the directory server indicates the bind didn't fail due to invalid
+// credentials, so the specific error can't be used to determine whether a password is correct.
+func (p *LDAPUserProvider) getBindAccountStateError(profile *ldapUserProfile, err error) (errState error) {
+	if p.config.Implementation != schema.LDAPImplementationActiveDirectory {
+		return nil
+	}
+
+	var data string
+
+	if data = ldapGetActiveDirectoryBindErrorData(err); data == "" || data == ldapActiveDirectoryBindDataInvalidCredentials {
+		return nil
+	}
+
+	if errState = profile.State.Err(); errState != nil {
+		return errState
+	}
+
+	switch data {
+	case ldapActiveDirectoryBindDataAccountDisabled:
+		return ErrAccountDisabled
+	case ldapActiveDirectoryBindDataAccountExpired:
+		return ErrAccountExpired
+	case ldapActiveDirectoryBindDataAccountLocked:
+		return ErrAccountLocked
+	case ldapActiveDirectoryBindDataPasswordExpired, ldapActiveDirectoryBindDataMustResetPassword:
+		return ErrPasswordMustChange
+	default:
+		return nil
+	}
 }
 
 func (p *LDAPUserProvider) search(client LDAPClient, request *ldap.SearchRequest) (result *ldap.SearchResult, err error) {
@@ -388,6 +500,20 @@ func (p *LDAPUserProvider) getUserProfile(client LDAPClient, username string) (p
 		if attr.Name == p.config.DisplayNameAttribute {
 			userProfile.DisplayName = attr.Values[0]
 		}
+
+		if p.config.Implementation == schema.LDAPImplementationActiveDirectory {
+			p.setActiveDirectoryAccountState(&userProfile.State, attr)
+		}
+
+		for _, extra := range p.extraAttributes {
+			if strings.EqualFold(attr.Name, extra.LDAPAttribute) {
+				if userProfile.Attributes == nil {
+					userProfile.Attributes = map[string][]string{}
+				}
+
+				userProfile.Attributes[extra.Name] = attr.Values
+			}
+		}
 	}
 
 	if userProfile.Username == "" {
@@ -402,6 +528,170 @@ func (p *LDAPUserProvider) getUserProfile(client LDAPClient, username string) (p
 	return &userProfile, nil
 }
 
+// setActiveDirectoryAccountState updates the account state using the value of one of the Active Directory account state
+// attributes.
+func (p *LDAPUserProvider) setActiveDirectoryAccountState(state *ldapAccountState, attr *ldap.EntryAttribute) {
+	value, err := strconv.ParseInt(attr.Values[0], 10, 64)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case strings.EqualFold(attr.Name, ldapAttributeActiveDirectoryUserAccountControl):
+		state.Disabled = value&ldapActiveDirectoryUACAccountDisable != 0
+	case strings.EqualFold(attr.Name, ldapAttributeActiveDirectoryLockoutTime):
+		state.Locked = value > 0
+	case strings.EqualFold(attr.Name, ldapAttributeActiveDirectoryAccountExpires):
+		state.Expired = value > 0 && value != ldapActiveDirectoryAccountExpiresNever &&
+			uint64(value) < utils.UnixNanoTimeToMicrosoftNTEpoch(p.clock.Now().UnixNano())
+	case strings.EqualFold(attr.Name, ldapAttributeActiveDirectoryPwdLastSet):
+		state.PasswordMustChange = value == 0
+	}
+}
+
+func (p *LDAPUserProvider) getUserGroups(client LDAPClient, username string, profile *ldapUserProfile) (groups []string, err error) {
+	var entries []*ldap.Entry
+
+	if entries, err = p.searchGroups(client, p.resolveGroupsFilter(username, profile)); err != nil {
+		return nil, fmt.Errorf("unable to retrieve groups of user '%s'. Cause: %w", username, err)
+	}
+
+	groups = make([]string, 0)
+
+	for _, entry := range entries {
+		if len(entry.Attributes) == 0 {
+			p.log.Warningf("No groups retrieved from LDAP for user %s", username)
+			break
+		}
+
+		if len(entry.Attributes) > 1 {
+			groups = append(groups, entry.GetEqualFoldAttributeValues(p.config.GroupNameAttribute)...)
+
+			continue
+		}
+
+		// Append all values of the document. Normally there should be only one per document.
+		groups = append(groups, entry.Attributes[0].Values...)
+	}
+
+	if !p.config.NestedGroups.Enable || p.config.NestedGroups.Method == schema.LDAPNestedGroupsMethodInChain {
+		return groups, nil
+	}
+
+	var nested []string
+
+	if nested, err = p.getNestedGroups(client, username, entries); err != nil {
+		return nil, fmt.Errorf("unable to retrieve nested groups of user '%s'. Cause: %w", username, err)
+	}
+
+	return append(groups, nested...), nil
+}
+
+// getNestedGroups iteratively resolves the names of the groups the given groups are a member of up to the configured
+// max depth. Each group is only visited once so membership cycles are not followed.
+func (p *LDAPUserProvider) getNestedGroups(client LDAPClient, username string, entries []*ldap.Entry) (groups []string, err error) {
+	visited := make(map[string]struct{}, len(entries))
+
+	for _, entry := range entries {
+		visited[strings.ToLower(entry.DN)] = struct{}{}
+	}
+
+	var parents []*ldap.Entry
+
+	for depth := 1; depth <= p.config.NestedGroups.MaxDepth && len(entries) != 0; depth++ {
+		var next []*ldap.Entry
+
+		for _, entry := range entries {
+			if parents, err = p.getParentGroups(client, username, entry, visited); err != nil {
+				return nil, err
+			}
+
+			for _, parent := range parents {
+				dn := strings.ToLower(parent.DN)
+
+				if _, ok := visited[dn]; ok {
+					continue
+				}
+
+				visited[dn] = struct{}{}
+
+				groups = append(groups, parent.GetEqualFoldAttributeValues(p.config.GroupNameAttribute)...)
+
+				next = append(next, parent)
+			}
+		}
+
+		if len(next) != 0 && depth == p.config.NestedGroups.MaxDepth {
+			p.log.Warnf("Nested groups of user '%s' were not fully resolved as the max depth of %d was reached", username, p.config.NestedGroups.MaxDepth)
+		}
+
+		entries = next
+	}
+
+	return groups, nil
+}
+
+// getParentGroups returns the groups the given group entry is a direct member of. Groups which have already been
+// visited may be omitted from the result.
+func (p *LDAPUserProvider) getParentGroups(client LDAPClient, username string, entry *ldap.Entry, visited map[string]struct{}) (parents []*ldap.Entry, err error) {
+	if entry.DN == "" {
+		return nil, nil
+	}
+
+	switch p.config.NestedGroups.Method {
+	case schema.LDAPNestedGroupsMethodMemberOf:
+		var result *ldap.SearchResult
+
+		for _, dn := range entry.GetEqualFoldAttributeValues(ldapAttributeMemberOf) {
+			if _, ok := visited[strings.ToLower(dn)]; ok {
+				continue
+			}
+
+			request := ldap.NewSearchRequest(
+				dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases,
+				1, 0, false, ldapBaseObjectFilter, p.groupsAttributes, nil,
+			)
+
+			if result, err = p.search(client, request); err != nil {
+				if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+					continue
+				}
+
+				return nil, err
+			}
+
+			parents = append(parents, result.Entries...)
+		}
+
+		return parents, nil
+	default:
+		return p.searchGroups(client, p.resolveGroupsFilter(username, &ldapUserProfile{DN: entry.DN, Username: username}))
+	}
+}
+
+func (p *LDAPUserProvider) searchGroups(client LDAPClient, filter string) (entries []*ldap.Entry, err error) {
+	var result *ldap.SearchResult
+
+	request := ldap.NewSearchRequest(
+		p.groupsBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, filter, p.groupsAttributes, nil,
+	)
+
+	p.log.
+		WithField("base_dn", request.BaseDN).
+		WithField("filter", request.Filter).
+		WithField("attr", request.Attributes).
+		WithField("scope", request.Scope).
+		WithField("deref", request.DerefAliases).
+		Trace("Performing group search")
+
+	if result, err = p.search(client, request); err != nil {
+		return nil, err
+	}
+
+	return result.Entries, nil
+}
+
 func (p *LDAPUserProvider) resolveUsersFilter(input string) (filter string) {
 	filter = p.config.UsersFilter
 
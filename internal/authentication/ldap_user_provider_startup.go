@@ -108,6 +108,15 @@ func (p *LDAPUserProvider) parseDynamicUsersConfiguration() {
 		p.usersAttributes = append(p.usersAttributes, p.config.DisplayNameAttribute)
 	}
 
+	if p.config.Implementation == schema.LDAPImplementationActiveDirectory {
+		for _, attribute := range []string{ldapAttributeActiveDirectoryUserAccountControl, ldapAttributeActiveDirectoryLockoutTime,
+			ldapAttributeActiveDirectoryAccountExpires, ldapAttributeActiveDirectoryPwdLastSet} {
+			if !utils.IsStringInSliceFold(attribute, p.usersAttributes) {
+				p.usersAttributes = append(p.usersAttributes, attribute)
+			}
+		}
+	}
+
 	if p.config.AdditionalUsersDN != "" {
 		p.usersBaseDN = p.config.AdditionalUsersDN + "," + p.config.BaseDN
 	} else {
@@ -136,11 +145,39 @@ func (p *LDAPUserProvider) parseDynamicUsersConfiguration() {
 		ldapPlaceholderInput, p.usersFilterReplacementInput)
 }
 
+// setExtraAttributes sets the extra attributes which are retrieved along with the user profile.
+func (p *LDAPUserProvider) setExtraAttributes(attributes []schema.AuthenticationBackendExtraAttribute) {
+	for _, attribute := range attributes {
+		if attribute.LDAPAttribute == "" {
+			attribute.LDAPAttribute = attribute.Name
+		}
+
+		p.extraAttributes = append(p.extraAttributes, attribute)
+
+		if !utils.IsStringInSliceFold(attribute.LDAPAttribute, p.usersAttributes) {
+			p.usersAttributes = append(p.usersAttributes, attribute.LDAPAttribute)
+		}
+	}
+}
+
 func (p *LDAPUserProvider) parseDynamicGroupsConfiguration() {
 	p.groupsAttributes = []string{
 		p.config.GroupNameAttribute,
 	}
 
+	matchingRuleInChain := ""
+
+	if p.config.NestedGroups.Enable {
+		switch p.config.NestedGroups.Method {
+		case schema.LDAPNestedGroupsMethodInChain:
+			matchingRuleInChain = ldapMatchingRuleInChain
+		case schema.LDAPNestedGroupsMethodMemberOf:
+			p.groupsAttributes = append(p.groupsAttributes, ldapAttributeMemberOf)
+		}
+	}
+
+	p.config.GroupsFilter = strings.ReplaceAll(p.config.GroupsFilter, ldapPlaceholderInChain, matchingRuleInChain)
+
 	if p.config.AdditionalGroupsDN != "" {
 		p.groupsBaseDN = p.config.AdditionalGroupsDN + "," + p.config.BaseDN
 	} else {
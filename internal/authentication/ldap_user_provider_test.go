@@ -1019,6 +1019,93 @@ func TestShouldReturnUsernameFromLDAP(t *testing.T) {
 	assert.Equal(t, details.Username, "John")
 }
 
+func TestShouldReturnExtraAttributesFromLDAP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+	mockClient := NewMockLDAPClient(ctrl)
+
+	provider := NewLDAPUserProviderWithFactory(
+		schema.LDAPAuthenticationBackend{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+		},
+		false,
+		nil,
+		mockFactory)
+
+	provider.setExtraAttributes([]schema.AuthenticationBackendExtraAttribute{
+		{Name: "department"},
+		{Name: "employee_number", LDAPAttribute: "employeeNumber"},
+		{Name: "phone", LDAPAttribute: "telephoneNumber"},
+	})
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockClient, nil)
+
+	connBind := mockClient.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	connClose := mockClient.EXPECT().Close()
+
+	searchGroups := mockClient.EXPECT().
+		Search(gomock.Any()).
+		Return(createSearchResultWithAttributeValues("group1"), nil)
+
+	searchProfile := mockClient.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			assert.Equal(t, []string{"uid", "mail", "displayName", "department", "employeeNumber", "telephoneNumber"}, request.Attributes)
+
+			return &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					{
+						DN: "uid=test,dc=example,dc=com",
+						Attributes: []*ldap.EntryAttribute{
+							{
+								Name:   "displayName",
+								Values: []string{"John Doe"},
+							},
+							{
+								Name:   "mail",
+								Values: []string{"test@example.com"},
+							},
+							{
+								Name:   "uid",
+								Values: []string{"John"},
+							},
+							{
+								Name:   "department",
+								Values: []string{"engineering"},
+							},
+							{
+								Name:   "employeenumber",
+								Values: []string{"1234"},
+							},
+						},
+					},
+				},
+			}, nil
+		})
+
+	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, connClose)
+
+	details, err := provider.GetDetails("john")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{"department": {"engineering"}, "employee_number": {"1234"}}, details.Attributes)
+}
+
 func TestShouldReturnUsernameFromLDAPWithReferrals(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -3980,3 +4067,809 @@ func TestShouldReturnLDAPSAlreadySecuredWhenStartTLSAttempted(t *testing.T) {
 	_, err := provider.GetDetails("john")
 	assert.EqualError(t, err, "starttls failed with error: LDAP Result Code 200 \"Network Error\": ldap: already encrypted")
 }
+
+var testLDAPNestedGroupsUserEntry = &ldap.Entry{
+	DN: "uid=john,ou=users,dc=example,dc=com",
+	Attributes: []*ldap.EntryAttribute{
+		{
+			Name:   "uid",
+			Values: []string{"john"},
+		},
+	},
+}
+
+func TestShouldResolveNestedGroupsInChain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+	mockClient := NewMockLDAPClient(ctrl)
+
+	provider := NewLDAPUserProviderWithFactory(
+		schema.LDAPAuthenticationBackend{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			GroupNameAttribute:   "cn",
+			UsersFilter:          "uid={input}",
+			GroupsFilter:         "(&(member{in_chain}={dn})(objectClass=group))",
+			AdditionalUsersDN:    "ou=users",
+			AdditionalGroupsDN:   "ou=groups",
+			BaseDN:               "dc=example,dc=com",
+			NestedGroups: schema.LDAPAuthenticationBackendNestedGroups{
+				Enable: true,
+				Method: schema.LDAPNestedGroupsMethodInChain,
+			},
+		},
+		false,
+		nil,
+		mockFactory)
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockClient, nil)
+
+	connBind := mockClient.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockClient.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{Entries: []*ldap.Entry{testLDAPNestedGroupsUserEntry}}, nil)
+
+	searchGroups := mockClient.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			assert.Equal(t, "(&(member:1.2.840.113556.1.4.1941:=uid=john,ou=users,dc=example,dc=com)(objectClass=group))", request.Filter)
+
+			return &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					{
+						DN: "cn=dev,ou=groups,dc=example,dc=com",
+						Attributes: []*ldap.EntryAttribute{
+							{
+								Name:   "cn",
+								Values: []string{"dev"},
+							},
+						},
+					},
+					{
+						DN: "cn=staff,ou=groups,dc=example,dc=com",
+						Attributes: []*ldap.EntryAttribute{
+							{
+								Name:   "cn",
+								Values: []string{"staff"},
+							},
+						},
+					},
+				},
+			}, nil
+		})
+
+	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, mockClient.EXPECT().Close())
+
+	details, err := provider.GetDetails("john")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "staff"}, details.Groups)
+}
+
+func TestShouldRemoveInChainPlaceholderWhenNestedGroupsDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+	mockClient := NewMockLDAPClient(ctrl)
+
+	provider := NewLDAPUserProviderWithFactory(
+		schema.LDAPAuthenticationBackend{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			GroupNameAttribute:   "cn",
+			UsersFilter:          "uid={input}",
+			GroupsFilter:         "(&(member{in_chain}={dn})(objectClass=group))",
+			AdditionalUsersDN:    "ou=users",
+			AdditionalGroupsDN:   "ou=groups",
+			BaseDN:               "dc=example,dc=com",
+		},
+		false,
+		nil,
+		mockFactory)
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockClient, nil)
+
+	connBind := mockClient.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockClient.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{Entries: []*ldap.Entry{testLDAPNestedGroupsUserEntry}}, nil)
+
+	searchGroups := mockClient.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			assert.Equal(t, "(&(member=uid=john,ou=users,dc=example,dc=com)(objectClass=group))", request.Filter)
+
+			return &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					{
+						DN: "cn=dev,ou=groups,dc=example,dc=com",
+						Attributes: []*ldap.EntryAttribute{
+							{
+								Name:   "cn",
+								Values: []string{"dev"},
+							},
+						},
+					},
+				},
+			}, nil
+		})
+
+	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, mockClient.EXPECT().Close())
+
+	details, err := provider.GetDetails("john")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev"}, details.Groups)
+}
+
+func TestShouldResolveNestedGroupsMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+	mockClient := NewMockLDAPClient(ctrl)
+
+	provider := NewLDAPUserProviderWithFactory(
+		schema.LDAPAuthenticationBackend{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			GroupNameAttribute:   "cn",
+			UsersFilter:          "uid={input}",
+			GroupsFilter:         "(&(member={dn})(objectClass=groupOfNames))",
+			AdditionalUsersDN:    "ou=users",
+			AdditionalGroupsDN:   "ou=groups",
+			BaseDN:               "dc=example,dc=com",
+			NestedGroups: schema.LDAPAuthenticationBackendNestedGroups{
+				Enable:   true,
+				Method:   schema.LDAPNestedGroupsMethodMember,
+				MaxDepth: 2,
+			},
+		},
+		false,
+		nil,
+		mockFactory)
+
+	results := map[string][]*ldap.Entry{
+		"(&(member=uid=john,ou=users,dc=example,dc=com)(objectClass=groupOfNames))": {
+			{
+				DN: "cn=dev,ou=groups,dc=example,dc=com",
+				Attributes: []*ldap.EntryAttribute{
+					{
+						Name:   "cn",
+						Values: []string{"dev"},
+					},
+				},
+			},
+		},
+		"(&(member=cn=dev,ou=groups,dc=example,dc=com)(objectClass=groupOfNames))": {
+			{
+				DN: "cn=engineering,ou=groups,dc=example,dc=com",
+				Attributes: []*ldap.EntryAttribute{
+					{
+						Name:   "cn",
+						Values: []string{"engineering"},
+					},
+				},
+			},
+		},
+		"(&(member=cn=engineering,ou=groups,dc=example,dc=com)(objectClass=groupOfNames))": {
+			{
+				DN: "CN=Dev,ou=groups,dc=example,dc=com",
+				Attributes: []*ldap.EntryAttribute{
+					{
+						Name:   "cn",
+						Values: []string{"dev"},
+					},
+				},
+			},
+			{
+				DN: "cn=staff,ou=groups,dc=example,dc=com",
+				Attributes: []*ldap.EntryAttribute{
+					{
+						Name:   "cn",
+						Values: []string{"staff"},
+					},
+				},
+			},
+		},
+	}
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockClient, nil)
+
+	connBind := mockClient.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockClient.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{Entries: []*ldap.Entry{testLDAPNestedGroupsUserEntry}}, nil)
+
+	searchGroups := mockClient.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			entries, ok := results[request.Filter]
+
+			require.True(t, ok, request.Filter)
+
+			return &ldap.SearchResult{Entries: entries}, nil
+		}).Times(3)
+
+	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, mockClient.EXPECT().Close())
+
+	details, err := provider.GetDetails("john")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "engineering", "staff"}, details.Groups)
+}
+
+func TestShouldResolveNestedGroupsMemberUpToMaxDepth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+	mockClient := NewMockLDAPClient(ctrl)
+
+	provider := NewLDAPUserProviderWithFactory(
+		schema.LDAPAuthenticationBackend{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			GroupNameAttribute:   "cn",
+			UsersFilter:          "uid={input}",
+			GroupsFilter:         "(&(member={dn})(objectClass=groupOfNames))",
+			AdditionalUsersDN:    "ou=users",
+			AdditionalGroupsDN:   "ou=groups",
+			BaseDN:               "dc=example,dc=com",
+			NestedGroups: schema.LDAPAuthenticationBackendNestedGroups{
+				Enable:   true,
+				Method:   schema.LDAPNestedGroupsMethodMember,
+				MaxDepth: 1,
+			},
+		},
+		false,
+		nil,
+		mockFactory)
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockClient, nil)
+
+	connBind := mockClient.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockClient.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{Entries: []*ldap.Entry{testLDAPNestedGroupsUserEntry}}, nil)
+
+	searchGroups := mockClient.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "cn=dev,ou=groups,dc=example,dc=com",
+					Attributes: []*ldap.EntryAttribute{
+						{
+							Name:   "cn",
+							Values: []string{"dev"},
+						},
+					},
+				},
+			},
+		}, nil)
+
+	searchNestedGroups := mockClient.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "cn=engineering,ou=groups,dc=example,dc=com",
+					Attributes: []*ldap.EntryAttribute{
+						{
+							Name:   "cn",
+							Values: []string{"engineering"},
+						},
+					},
+				},
+			},
+		}, nil)
+
+	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, searchNestedGroups, mockClient.EXPECT().Close())
+
+	details, err := provider.GetDetails("john")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "engineering"}, details.Groups)
+}
+
+func TestShouldResolveNestedGroupsMemberOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+	mockClient := NewMockLDAPClient(ctrl)
+
+	provider := NewLDAPUserProviderWithFactory(
+		schema.LDAPAuthenticationBackend{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			GroupNameAttribute:   "cn",
+			UsersFilter:          "uid={input}",
+			GroupsFilter:         "(&(member={dn})(objectClass=groupOfNames))",
+			AdditionalUsersDN:    "ou=users",
+			AdditionalGroupsDN:   "ou=groups",
+			BaseDN:               "dc=example,dc=com",
+			NestedGroups: schema.LDAPAuthenticationBackendNestedGroups{
+				Enable:   true,
+				Method:   schema.LDAPNestedGroupsMethodMemberOf,
+				MaxDepth: 5,
+			},
+		},
+		false,
+		nil,
+		mockFactory)
+
+	results := map[string]*ldap.Entry{
+		"cn=engineering,ou=groups,dc=example,dc=com": {
+			DN: "cn=engineering,ou=groups,dc=example,dc=com",
+			Attributes: []*ldap.EntryAttribute{
+				{
+					Name:   "cn",
+					Values: []string{"engineering"},
+				},
+				{
+					Name:   "memberOf",
+					Values: []string{"cn=staff,ou=groups,dc=example,dc=com"},
+				},
+			},
+		},
+		"cn=staff,ou=groups,dc=example,dc=com": {
+			DN: "cn=staff,ou=groups,dc=example,dc=com",
+			Attributes: []*ldap.EntryAttribute{
+				{
+					Name:   "cn",
+					Values: []string{"staff"},
+				},
+				{
+					Name:   "memberOf",
+					Values: []string{"cn=engineering,ou=groups,dc=example,dc=com"},
+				},
+			},
+		},
+	}
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockClient, nil)
+
+	connBind := mockClient.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockClient.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{Entries: []*ldap.Entry{testLDAPNestedGroupsUserEntry}}, nil)
+
+	searchGroups := mockClient.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			assert.Equal(t, []string{"cn", "memberOf"}, request.Attributes)
+
+			return &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					{
+						DN: "cn=dev,ou=groups,dc=example,dc=com",
+						Attributes: []*ldap.EntryAttribute{
+							{
+								Name:   "cn",
+								Values: []string{"dev"},
+							},
+							{
+								Name:   "memberOf",
+								Values: []string{"cn=engineering,ou=groups,dc=example,dc=com"},
+							},
+						},
+					},
+				},
+			}, nil
+		})
+
+	searchNestedGroups := mockClient.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			assert.Equal(t, ldap.ScopeBaseObject, request.Scope)
+
+			entry, ok := results[request.BaseDN]
+
+			require.True(t, ok, request.BaseDN)
+
+			return &ldap.SearchResult{Entries: []*ldap.Entry{entry}}, nil
+		}).Times(2)
+
+	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, searchNestedGroups, mockClient.EXPECT().Close())
+
+	details, err := provider.GetDetails("john")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "engineering", "staff"}, details.Groups)
+}
+
+func TestShouldRequestActiveDirectoryAccountStateAttributes(t *testing.T) {
+	config := schema.DefaultLDAPAuthenticationBackendConfigurationImplementationActiveDirectory
+
+	config.Implementation = schema.LDAPImplementationActiveDirectory
+	config.URL = "ldap://127.0.0.1:389"
+	config.User = "CN=Administrator,CN=Users,DC=example,DC=com"
+	config.Password = "password"
+	config.BaseDN = "DC=example,DC=com"
+
+	provider := NewLDAPUserProviderWithFactory(config, false, nil, nil)
+
+	assert.Subset(t, provider.usersAttributes, []string{"userAccountControl", "lockoutTime", "accountExpires", "pwdLastSet"})
+
+	provider = NewLDAPUserProviderWithFactory(schema.LDAPAuthenticationBackend{UsernameAttribute: "uid"}, false, nil, nil)
+
+	assert.NotContains(t, provider.usersAttributes, "userAccountControl")
+}
+
+func TestShouldReturnActiveDirectoryAccountStateErrors(t *testing.T) {
+	errFmtBind := "80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data %s, v3839"
+
+	testCases := []struct {
+		name           string
+		uac            string
+		lockoutTime    string
+		accountExpires string
+		pwdLastSet     string
+		data           string
+		expected       error
+		err            string
+	}{
+		{
+			"ShouldReturnDisabled",
+			"514",
+			"0",
+			"0",
+			"133145669190000000",
+			"533",
+			ErrAccountDisabled,
+			"authentication failed. Cause: account is disabled: bind failed with error: LDAP Result Code 49 \"Invalid Credentials\": 80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data 533, v3839",
+		},
+		{
+			"ShouldReturnExpired",
+			"512",
+			"0",
+			"133000000000000000",
+			"133145669190000000",
+			"701",
+			ErrAccountExpired,
+			"",
+		},
+		{
+			"ShouldReturnLocked",
+			"512",
+			"133145669190000000",
+			"9223372036854775807",
+			"133145669190000000",
+			"775",
+			ErrAccountLocked,
+			"",
+		},
+		{
+			"ShouldReturnPasswordMustChange",
+			"512",
+			"0",
+			"0",
+			"0",
+			"773",
+			ErrPasswordMustChange,
+			"",
+		},
+		{
+			"ShouldReturnPasswordMustChangeFromDataWhenPasswordExpired",
+			"512",
+			"0",
+			"0",
+			"133000000000000000",
+			"532",
+			ErrPasswordMustChange,
+			"",
+		},
+		{
+			"ShouldNotDiscloseStateWhenInvalidCredentials",
+			"514",
+			"0",
+			"0",
+			"0",
+			"52e",
+			nil,
+			"authentication failed. Cause: bind failed with error: LDAP Result Code 49 \"Invalid Credentials\": 80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data 52e, v3839",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPClientFactory(ctrl)
+			mockClient := NewMockLDAPClient(ctrl)
+
+			config := schema.DefaultLDAPAuthenticationBackendConfigurationImplementationActiveDirectory
+
+			config.Implementation = schema.LDAPImplementationActiveDirectory
+			config.URL = "ldap://127.0.0.1:389"
+			config.User = "CN=Administrator,CN=Users,DC=example,DC=com"
+			config.Password = "password"
+			config.BaseDN = "DC=example,DC=com"
+
+			provider := NewLDAPUserProviderWithFactory(config, false, nil, mockFactory)
+
+			clock := &utils.TestingClock{}
+			clock.Set(time.Unix(1670250519, 0))
+
+			provider.clock = clock
+
+			entry := &ldap.Entry{
+				DN: "CN=John,OU=users,DC=example,DC=com",
+				Attributes: []*ldap.EntryAttribute{
+					{Name: "sAMAccountName", Values: []string{"john"}},
+					{Name: "mail", Values: []string{"john@example.com"}},
+					{Name: "displayName", Values: []string{"John Doe"}},
+					{Name: "userAccountControl", Values: []string{tc.uac}},
+					{Name: "lockoutTime", Values: []string{tc.lockoutTime}},
+					{Name: "accountExpires", Values: []string{tc.accountExpires}},
+					{Name: "pwdLastSet", Values: []string{tc.pwdLastSet}},
+				},
+			}
+
+			gomock.InOrder(
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockClient, nil),
+				mockClient.EXPECT().
+					Bind(gomock.Eq("CN=Administrator,CN=Users,DC=example,DC=com"), gomock.Eq("password")).
+					Return(nil),
+				mockClient.EXPECT().
+					Search(gomock.Any()).
+					Return(&ldap.SearchResult{Entries: []*ldap.Entry{entry}}, nil),
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockClient, nil),
+				mockClient.EXPECT().
+					Bind(gomock.Eq("CN=John,OU=users,DC=example,DC=com"), gomock.Eq("password")).
+					Return(ldap.NewError(ldap.LDAPResultInvalidCredentials, fmt.Errorf(errFmtBind, tc.data))),
+				mockClient.EXPECT().Close().Times(2),
+			)
+
+			valid, err := provider.CheckUserPassword("john", "password")
+
+			assert.False(t, valid)
+
+			if tc.expected == nil {
+				for _, e := range []error{ErrAccountDisabled, ErrAccountExpired, ErrAccountLocked, ErrPasswordMustChange} {
+					assert.NotErrorIs(t, err, e)
+				}
+			} else {
+				assert.ErrorIs(t, err, tc.expected)
+			}
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestShouldNotReturnDetailsForDisabledActiveDirectoryAccounts(t *testing.T) {
+	testCases := []struct {
+		name           string
+		uac            string
+		lockoutTime    string
+		accountExpires string
+		pwdLastSet     string
+		expected       error
+	}{
+		{"ShouldReturnDisabled", "514", "0", "0", "133145669190000000", ErrAccountDisabled},
+		{"ShouldReturnExpired", "512", "0", "133000000000000000", "133145669190000000", ErrAccountExpired},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPClientFactory(ctrl)
+			mockClient := NewMockLDAPClient(ctrl)
+
+			config := schema.DefaultLDAPAuthenticationBackendConfigurationImplementationActiveDirectory
+
+			config.Implementation = schema.LDAPImplementationActiveDirectory
+			config.URL = "ldap://127.0.0.1:389"
+			config.User = "CN=Administrator,CN=Users,DC=example,DC=com"
+			config.Password = "password"
+			config.BaseDN = "DC=example,DC=com"
+
+			provider := NewLDAPUserProviderWithFactory(config, false, nil, mockFactory)
+
+			clock := &utils.TestingClock{}
+			clock.Set(time.Unix(1670250519, 0))
+
+			provider.clock = clock
+
+			entry := &ldap.Entry{
+				DN: "CN=John,OU=users,DC=example,DC=com",
+				Attributes: []*ldap.EntryAttribute{
+					{Name: "sAMAccountName", Values: []string{"john"}},
+					{Name: "mail", Values: []string{"john@example.com"}},
+					{Name: "displayName", Values: []string{"John Doe"}},
+					{Name: "userAccountControl", Values: []string{tc.uac}},
+					{Name: "lockoutTime", Values: []string{tc.lockoutTime}},
+					{Name: "accountExpires", Values: []string{tc.accountExpires}},
+					{Name: "pwdLastSet", Values: []string{tc.pwdLastSet}},
+				},
+			}
+
+			gomock.InOrder(
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockClient, nil),
+				mockClient.EXPECT().
+					Bind(gomock.Eq("CN=Administrator,CN=Users,DC=example,DC=com"), gomock.Eq("password")).
+					Return(nil),
+				mockClient.EXPECT().
+					Search(gomock.Any()).
+					Return(&ldap.SearchResult{Entries: []*ldap.Entry{entry}}, nil),
+				mockClient.EXPECT().Close(),
+			)
+
+			details, err := provider.GetDetails("john")
+
+			assert.Nil(t, details)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestShouldReturnPasswordPolicyControlErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		control  *ldap.ControlBeheraPasswordPolicy
+		err      error
+		expected error
+	}{
+		{
+			"ShouldReturnPasswordMustChangeAfterReset",
+			&ldap.ControlBeheraPasswordPolicy{Expire: -1, Grace: -1, Error: ldap.BeheraChangeAfterReset, ErrorString: ldap.BeheraPasswordPolicyErrorMap[ldap.BeheraChangeAfterReset]},
+			nil,
+			ErrPasswordMustChange,
+		},
+		{
+			"ShouldReturnPasswordMustChangeWhenExpired",
+			&ldap.ControlBeheraPasswordPolicy{Expire: -1, Grace: -1, Error: ldap.BeheraPasswordExpired, ErrorString: ldap.BeheraPasswordPolicyErrorMap[ldap.BeheraPasswordExpired]},
+			ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")),
+			ErrPasswordMustChange,
+		},
+		{
+			"ShouldReturnLocked",
+			&ldap.ControlBeheraPasswordPolicy{Expire: -1, Grace: -1, Error: ldap.BeheraAccountLocked, ErrorString: ldap.BeheraPasswordPolicyErrorMap[ldap.BeheraAccountLocked]},
+			ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")),
+			ErrAccountLocked,
+		},
+		{
+			"ShouldNotReturnStateWhenNoError",
+			&ldap.ControlBeheraPasswordPolicy{Expire: 3600, Grace: -1, Error: -1},
+			ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")),
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPClientFactory(ctrl)
+			mockClient := NewMockLDAPClient(ctrl)
+
+			provider := NewLDAPUserProviderWithFactory(
+				schema.LDAPAuthenticationBackend{
+					URL:                  "ldap://127.0.0.1:389",
+					User:                 "cn=admin,dc=example,dc=com",
+					Password:             "password",
+					UsernameAttribute:    "uid",
+					MailAttribute:        "mail",
+					DisplayNameAttribute: "displayName",
+					UsersFilter:          "uid={input}",
+					AdditionalUsersDN:    "ou=users",
+					BaseDN:               "dc=example,dc=com",
+				},
+				false,
+				nil,
+				mockFactory)
+
+			provider.features.ControlTypes.PwdPolicy = true
+
+			gomock.InOrder(
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockClient, nil),
+				mockClient.EXPECT().
+					Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+					Return(nil),
+				mockClient.EXPECT().
+					Search(gomock.Any()).
+					Return(&ldap.SearchResult{
+						Entries: []*ldap.Entry{
+							{
+								DN: "uid=test,dc=example,dc=com",
+								Attributes: []*ldap.EntryAttribute{
+									{Name: "uid", Values: []string{"john"}},
+								},
+							},
+						},
+					}, nil),
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockClient, nil),
+				mockClient.EXPECT().
+					SimpleBind(gomock.Any()).
+					DoAndReturn(func(request *ldap.SimpleBindRequest) (*ldap.SimpleBindResult, error) {
+						assert.Equal(t, "uid=test,dc=example,dc=com", request.Username)
+						assert.Equal(t, "password", request.Password)
+						require.Len(t, request.Controls, 1)
+						assert.Equal(t, ldap.ControlTypeBeheraPasswordPolicy, request.Controls[0].GetControlType())
+
+						return &ldap.SimpleBindResult{Controls: []ldap.Control{tc.control}}, tc.err
+					}),
+				mockClient.EXPECT().Close().Times(2),
+			)
+
+			valid, err := provider.CheckUserPassword("john", "password")
+
+			assert.False(t, valid)
+
+			if tc.expected == nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.NotErrorIs(t, err, ErrAccountLocked)
+				assert.NotErrorIs(t, err, ErrPasswordMustChange)
+			} else {
+				assert.ErrorIs(t, err, tc.expected)
+			}
+		})
+	}
+}
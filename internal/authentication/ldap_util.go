@@ -1,6 +1,7 @@
 package authentication
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -38,6 +39,8 @@ func ldapGetFeatureSupportFromEntry(entry *ldap.Entry) (controlTypeOIDs, extensi
 					features.ControlTypes.MsftPwdPolHints = true
 				case ldapOIDControlMsftServerPolicyHintsDeprecated:
 					features.ControlTypes.MsftPwdPolHintsDeprecated = true
+				case ldapOIDControlPwdPolicy:
+					features.ControlTypes.PwdPolicy = true
 				}
 			}
 		case ldapSupportedExtensionAttribute:
@@ -104,3 +107,28 @@ func ldapGetReferral(err error) (referral string, ok bool) {
 		return "", false
 	}
 }
+
+// ldapGetActiveDirectoryBindErrorData returns the data sub-code from the diagnostic message of an Active Directory bind
+// failure, for example 775 from '80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data 775, v3839'.
+func ldapGetActiveDirectoryBindErrorData(err error) (data string) {
+	var e *ldap.Error
+
+	if !errors.As(err, &e) || e.ResultCode != ldap.LDAPResultInvalidCredentials || e.Err == nil {
+		return ""
+	}
+
+	message := e.Err.Error()
+
+	i := strings.Index(message, ldapActiveDirectoryBindDataPrefix)
+	if i == -1 {
+		return ""
+	}
+
+	data = message[i+len(ldapActiveDirectoryBindDataPrefix):]
+
+	if i = strings.IndexAny(data, ", "); i != -1 {
+		data = data[:i]
+	}
+
+	return strings.ToLower(data)
+}
@@ -2,6 +2,7 @@ package authentication
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	ber "github.com/go-asn1-ber/asn1-ber"
@@ -58,6 +59,12 @@ func TestLDAPGetFeatureSupportFromEntry(t *testing.T) {
 			haveExtensionOIDs: []string{},
 			expected:          LDAPSupportedFeatures{ControlTypes: LDAPSupportedControlTypes{MsftPwdPolHints: true, MsftPwdPolHintsDeprecated: true}},
 		},
+		{
+			description:       "ShouldReturnControlPwdPolicy",
+			haveControlOIDs:   []string{ldapOIDControlPwdPolicy},
+			haveExtensionOIDs: []string{},
+			expected:          LDAPSupportedFeatures{ControlTypes: LDAPSupportedControlTypes{PwdPolicy: true}},
+		},
 		{
 			description:       "ShouldReturnExtensionAndControlAll",
 			haveControlOIDs:   []string{ldapOIDControlMsftServerPolicyHints, ldapOIDControlMsftServerPolicyHintsDeprecated},
@@ -318,3 +325,43 @@ var testBERPacketReferralWithoutEnoughChildren = ber.Packet{
 		},
 	},
 }
+
+func TestLDAPGetActiveDirectoryBindErrorData(t *testing.T) {
+	testCases := []struct {
+		description string
+		have        error
+		expected    string
+	}{
+		{
+			description: "ShouldReturnDataFromInvalidCredentials",
+			have:        ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data 775, v3839")),
+			expected:    "775",
+		},
+		{
+			description: "ShouldReturnDataFromWrappedError",
+			have:        fmt.Errorf("bind failed with error: %w", ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data 52E, v3839"))),
+			expected:    "52e",
+		},
+		{
+			description: "ShouldNotReturnDataFromOtherResultCodes",
+			have:        ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("00002035: LdapErr: DSID-0C090F78, comment: data 0, v3839")),
+			expected:    "",
+		},
+		{
+			description: "ShouldNotReturnDataWhenAbsent",
+			have:        ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")),
+			expected:    "",
+		},
+		{
+			description: "ShouldNotReturnDataFromOtherErrors",
+			have:        errors.New("data 533"),
+			expected:    "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ldapGetActiveDirectoryBindErrorData(tc.have))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-crypt/crypt"
+	"github.com/go-crypt/crypt/algorithm"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// StorageUserProvider is a UserProvider that reads and writes users using the storage provider.
+type StorageUserProvider struct {
+	config   *schema.StorageAuthenticationBackend
+	hash     algorithm.Hash
+	provider storage.UserDatabaseProvider
+}
+
+// NewStorageUserProvider creates a new instance of StorageUserProvider.
+func NewStorageUserProvider(config *schema.StorageAuthenticationBackend, provider storage.UserDatabaseProvider) (p *StorageUserProvider) {
+	return &StorageUserProvider{
+		config:   config,
+		provider: provider,
+	}
+}
+
+// CheckUserPassword checks if provided password matches for the given user.
+func (p *StorageUserProvider) CheckUserPassword(username string, password string) (match bool, err error) {
+	var (
+		user   *model.User
+		digest algorithm.Digest
+	)
+
+	if user, err = p.getUser(username); err != nil {
+		return false, err
+	}
+
+	if digest, err = crypt.Decode(user.Password); err != nil {
+		return false, fmt.Errorf("failed to parse hash for user '%s': %w", user.Username, err)
+	}
+
+	// The disabled state is only disclosed if the password is correct.
+	if match, err = digest.MatchAdvanced(password); err != nil || !match {
+		return false, err
+	}
+
+	if user.Disabled {
+		return false, ErrAccountDisabled
+	}
+
+	return true, nil
+}
+
+// GetDetails retrieve the groups a user belongs to.
+func (p *StorageUserProvider) GetDetails(username string) (details *UserDetails, err error) {
+	var user *model.User
+
+	if user, err = p.getUser(username); err != nil {
+		return nil, err
+	}
+
+	if user.Disabled {
+		return nil, ErrAccountDisabled
+	}
+
+	details = &UserDetails{
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Groups:      user.Groups,
+	}
+
+	if user.Email != "" {
+		details.Emails = []string{user.Email}
+	}
+
+	return details, nil
+}
+
+// UpdatePassword update the password of the given user.
+func (p *StorageUserProvider) UpdatePassword(username string, newPassword string) (err error) {
+	var (
+		user   *model.User
+		digest algorithm.Digest
+	)
+
+	if user, err = p.getUser(username); err != nil {
+		return err
+	}
+
+	if user.Disabled {
+		return ErrAccountDisabled
+	}
+
+	if digest, err = p.hash.Hash(newPassword); err != nil {
+		return err
+	}
+
+	if err = p.provider.UpdateUserPassword(context.Background(), user.Username, digest.Encode()); err != nil {
+		if errors.Is(err, storage.ErrNoUser) {
+			return ErrUserNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// StartupCheck implements the startup check provider interface.
+func (p *StorageUserProvider) StartupCheck() (err error) {
+	if p.provider == nil {
+		return fmt.Errorf("the storage provider is not configured")
+	}
+
+	if p.hash, err = NewFileCryptoHashFromConfig(p.config.Password); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *StorageUserProvider) getUser(username string) (user *model.User, err error) {
+	ctx := context.Background()
+
+	if user, err = p.provider.LoadUser(ctx, username); err != nil && errors.Is(err, storage.ErrNoUser) && p.config.Search.Email {
+		user, err = p.provider.LoadUserByEmail(ctx, username)
+	}
+
+	switch {
+	case err == nil:
+		break
+	case errors.Is(err, storage.ErrNoUser):
+		return nil, ErrUserNotFound
+	default:
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,174 @@
+package authentication
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-crypt/crypt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+const storageUserDigestJohn = "$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM"
+
+func TestStorageUserProviderShouldErrorStartupCheckWithoutStorage(t *testing.T) {
+	provider := NewStorageUserProvider(&schema.StorageAuthenticationBackend{Password: schema.DefaultCIPasswordConfig}, nil)
+
+	assert.EqualError(t, provider.StartupCheck(), "the storage provider is not configured")
+}
+
+type StorageUserProviderSuite struct {
+	suite.Suite
+
+	ctrl     *gomock.Controller
+	mock     *MockUserDatabaseProvider
+	provider *StorageUserProvider
+}
+
+func (s *StorageUserProviderSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mock = NewMockUserDatabaseProvider(s.ctrl)
+	s.provider = NewStorageUserProvider(&schema.StorageAuthenticationBackend{Password: schema.DefaultCIPasswordConfig}, s.mock)
+
+	s.Require().NoError(s.provider.StartupCheck())
+}
+
+func (s *StorageUserProviderSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *StorageUserProviderSuite) TestShouldCheckUserPassword() {
+	gomock.InOrder(
+		s.mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{Username: "john", Password: storageUserDigestJohn}, nil),
+		s.mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{Username: "john", Password: storageUserDigestJohn}, nil),
+	)
+
+	ok, err := s.provider.CheckUserPassword("john", "password")
+
+	s.NoError(err)
+	s.True(ok)
+
+	ok, err = s.provider.CheckUserPassword("john", "wrong")
+
+	s.NoError(err)
+	s.False(ok)
+}
+
+func (s *StorageUserProviderSuite) TestShouldReturnUserNotFound() {
+	s.mock.EXPECT().LoadUser(gomock.Any(), "fake").Return(nil, storage.ErrNoUser)
+
+	ok, err := s.provider.CheckUserPassword("fake", "password")
+
+	s.ErrorIs(err, ErrUserNotFound)
+	s.False(ok)
+}
+
+func (s *StorageUserProviderSuite) TestShouldNotAllowDisabledUsers() {
+	s.mock.EXPECT().LoadUser(gomock.Any(), "dis").Return(&model.User{Username: "dis", Password: storageUserDigestJohn, Disabled: true}, nil).Times(2)
+
+	ok, err := s.provider.CheckUserPassword("dis", "password")
+
+	s.ErrorIs(err, ErrAccountDisabled)
+	s.False(ok)
+
+	ok, err = s.provider.CheckUserPassword("dis", "wrong")
+
+	s.NoError(err)
+	s.False(ok)
+}
+
+func (s *StorageUserProviderSuite) TestShouldRaiseErrorOnBadDigest() {
+	s.mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{Username: "john", Password: "$bad$digest"}, nil)
+
+	ok, err := s.provider.CheckUserPassword("john", "password")
+
+	s.EqualError(err, "failed to parse hash for user 'john': provided encoded hash has an invalid identifier: the identifier 'bad' is unknown to the global decoder")
+	s.False(ok)
+}
+
+func (s *StorageUserProviderSuite) TestShouldPassThroughStorageErrors() {
+	s.provider.config.Search.Email = true
+
+	s.mock.EXPECT().LoadUser(gomock.Any(), "john").Return(nil, errors.New("connection refused"))
+
+	_, err := s.provider.GetDetails("john")
+
+	s.EqualError(err, "connection refused")
+}
+
+func (s *StorageUserProviderSuite) TestShouldGetDetails() {
+	s.mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{
+		Username:    "john",
+		DisplayName: "John Doe",
+		Email:       "john.doe@authelia.com",
+		Groups:      []string{"admins", "dev"},
+	}, nil)
+
+	details, err := s.provider.GetDetails("john")
+
+	s.Require().NoError(err)
+	s.Equal("john", details.Username)
+	s.Equal("John Doe", details.DisplayName)
+	s.Equal([]string{"john.doe@authelia.com"}, details.Emails)
+	s.Equal([]string{"admins", "dev"}, details.Groups)
+}
+
+func (s *StorageUserProviderSuite) TestShouldLookupByEmail() {
+	s.provider.config.Search.Email = true
+
+	gomock.InOrder(
+		s.mock.EXPECT().LoadUser(gomock.Any(), "john.doe@authelia.com").Return(nil, storage.ErrNoUser),
+		s.mock.EXPECT().LoadUserByEmail(gomock.Any(), "john.doe@authelia.com").Return(&model.User{Username: "john", Email: "john.doe@authelia.com"}, nil),
+	)
+
+	details, err := s.provider.GetDetails("john.doe@authelia.com")
+
+	s.Require().NoError(err)
+	s.Equal("john", details.Username)
+}
+
+func (s *StorageUserProviderSuite) TestShouldNotLookupByEmailWhenDisabled() {
+	s.mock.EXPECT().LoadUser(gomock.Any(), "john.doe@authelia.com").Return(nil, storage.ErrNoUser)
+
+	_, err := s.provider.GetDetails("john.doe@authelia.com")
+
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *StorageUserProviderSuite) TestShouldUpdatePassword() {
+	var digest string
+
+	gomock.InOrder(
+		s.mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{Username: "john", Password: storageUserDigestJohn}, nil),
+		s.mock.EXPECT().UpdateUserPassword(gomock.Any(), "john", gomock.Any()).DoAndReturn(func(_ interface{}, _ string, password string) error {
+			digest = password
+
+			return nil
+		}),
+	)
+
+	s.Require().NoError(s.provider.UpdatePassword("john", "newpassword"))
+
+	ok, err := crypt.CheckPassword("newpassword", digest)
+
+	s.NoError(err)
+	s.True(ok)
+}
+
+func (s *StorageUserProviderSuite) TestShouldReturnUserNotFoundOnUpdatePassword() {
+	gomock.InOrder(
+		s.mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{Username: "john", Password: storageUserDigestJohn}, nil),
+		s.mock.EXPECT().UpdateUserPassword(gomock.Any(), "john", gomock.Any()).Return(storage.ErrNoUser),
+	)
+
+	s.ErrorIs(s.provider.UpdatePassword("john", "newpassword"), ErrUserNotFound)
+}
+
+func TestRunStorageUserProviderSuite(t *testing.T) {
+	suite.Run(t, new(StorageUserProviderSuite))
+}
@@ -3,6 +3,7 @@ package authentication
 import (
 	"crypto/tls"
 	"net/mail"
+	"time"
 
 	"github.com/go-ldap/ldap/v3"
 	"golang.org/x/text/encoding/unicode"
@@ -18,9 +19,11 @@ type LDAPClientFactory interface {
 // Methods added to this interface that have a direct correlation with one from ldap.Client should have the same signature.
 type LDAPClient interface {
 	Close()
+	IsClosing() bool
 	StartTLS(config *tls.Config) (err error)
 
 	Bind(username, password string) (err error)
+	SimpleBind(simpleBindRequest *ldap.SimpleBindRequest) (simpleBindResult *ldap.SimpleBindResult, err error)
 	UnauthenticatedBind(username string) (err error)
 
 	Modify(modifyRequest *ldap.ModifyRequest) (err error)
@@ -29,12 +32,26 @@ type LDAPClient interface {
 	Search(searchRequest *ldap.SearchRequest) (searchResult *ldap.SearchResult, err error)
 }
 
+// LDAPPoolMetricsRecorder represents the methods used to record LDAP connection pool metrics, it's implemented by the
+// metrics.LDAPPoolRecorder.
+type LDAPPoolMetricsRecorder interface {
+	RecordLDAPPoolConnections(open, idle int)
+	RecordLDAPPoolAcquire(success bool, elapsed time.Duration)
+	RecordLDAPPoolConnectionClosed(reason string)
+}
+
+// UserProviderCloser is implemented by the user providers which hold resources which must be released on shutdown.
+type UserProviderCloser interface {
+	Close()
+}
+
 // UserDetails represent the details retrieved for a given user.
 type UserDetails struct {
 	Username    string
 	DisplayName string
 	Emails      []string
 	Groups      []string
+	Attributes  map[string][]string
 }
 
 // Addresses returns the Emails []string as []mail.Address formatted with DisplayName as the Name attribute.
@@ -55,11 +72,62 @@ func (d UserDetails) Addresses() (addresses []mail.Address) {
 	return addresses
 }
 
+// clone returns a deep copy of the UserDetails so cached details can't be modified by the caller.
+func (d *UserDetails) clone() (details *UserDetails) {
+	if d == nil {
+		return nil
+	}
+
+	details = &UserDetails{
+		Username:    d.Username,
+		DisplayName: d.DisplayName,
+		Emails:      append([]string(nil), d.Emails...),
+		Groups:      append([]string(nil), d.Groups...),
+	}
+
+	if d.Attributes != nil {
+		details.Attributes = make(map[string][]string, len(d.Attributes))
+
+		for name, values := range d.Attributes {
+			details.Attributes[name] = append([]string(nil), values...)
+		}
+	}
+
+	return details
+}
+
 type ldapUserProfile struct {
 	DN          string
 	Emails      []string
 	DisplayName string
 	Username    string
+	Attributes  map[string][]string
+
+	State ldapAccountState
+}
+
+// ldapAccountState represents the state of an account as advertised by the directory server.
+type ldapAccountState struct {
+	Disabled           bool
+	Locked             bool
+	Expired            bool
+	PasswordMustChange bool
+}
+
+// Err returns the error which represents the account state, or nil if the account state does not prevent a sign in.
+func (s ldapAccountState) Err() (err error) {
+	switch {
+	case s.Disabled:
+		return ErrAccountDisabled
+	case s.Expired:
+		return ErrAccountExpired
+	case s.Locked:
+		return ErrAccountLocked
+	case s.PasswordMustChange:
+		return ErrPasswordMustChange
+	default:
+		return nil
+	}
 }
 
 // LDAPSupportedFeatures represents features which a server may support which are implemented in code.
@@ -78,6 +146,7 @@ type LDAPSupportedExtensions struct {
 type LDAPSupportedControlTypes struct {
 	MsftPwdPolHints           bool
 	MsftPwdPolHintsDeprecated bool
+	PwdPolicy                 bool
 }
 
 var utf16LittleEndian = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
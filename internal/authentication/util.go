@@ -1,5 +1,10 @@
 package authentication
 
+import (
+	"fmt"
+	"strings"
+)
+
 // String returns a string representation of an authentication.Level.
 func (l Level) String() string {
 	switch l {
@@ -13,3 +18,91 @@ func (l Level) String() string {
 		return "invalid"
 	}
 }
+
+// ParseLevel returns the authentication.Level represented by its string representation. The empty string represents
+// NotAuthenticated.
+func ParseLevel(value string) (level Level, err error) {
+	switch value {
+	case "", "not_authenticated":
+		return NotAuthenticated, nil
+	case "one_factor":
+		return OneFactor, nil
+	case "two_factor":
+		return TwoFactor, nil
+	default:
+		return NotAuthenticated, fmt.Errorf("must be one of 'not_authenticated', 'one_factor' or 'two_factor' but it's configured as '%s'", value)
+	}
+}
+
+// digestParameters are the decoded algorithm, variant, and parameters of an encoded digest. The salt and key are only
+// represented by their encoded lengths.
+type digestParameters struct {
+	identifier string
+	parameters map[string]string
+	saltLength int
+	keyLength  int
+}
+
+// Equal returns true if both digests use the same algorithm, variant, and parameters.
+func (d *digestParameters) Equal(other *digestParameters) bool {
+	if d == nil || other == nil {
+		return false
+	}
+
+	if d.identifier != other.identifier || d.saltLength != other.saltLength || d.keyLength != other.keyLength || len(d.parameters) != len(other.parameters) {
+		return false
+	}
+
+	for key, value := range d.parameters {
+		if v, ok := other.parameters[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decodeDigestParameters decodes the parameters of a digest in the modular crypt format. Each parameter segment is
+// decoded into its named parameters so the order they're encoded in doesn't matter, a positional parameter such as the
+// PBKDF2 iterations is stored with an empty name.
+func decodeDigestParameters(encoded string) (parameters *digestParameters, err error) {
+	parts := strings.Split(encoded, "$")
+
+	n := len(parts)
+
+	if n < 4 || parts[0] != "" || parts[1] == "" {
+		return nil, fmt.Errorf("digest is not in the modular crypt format")
+	}
+
+	parameters = &digestParameters{
+		identifier: parts[1],
+		parameters: map[string]string{},
+	}
+
+	if n == 4 && len(parts[3]) == digestBCryptSaltKeyLength {
+		// The bcrypt format encodes the cost as a positional parameter and concatenates the salt and key.
+		parameters.parameters[""] = parts[2]
+		parameters.saltLength, parameters.keyLength = digestBCryptSaltLength, digestBCryptSaltKeyLength-digestBCryptSaltLength
+
+		return parameters, nil
+	}
+
+	for _, segment := range parts[2 : n-2] {
+		for _, parameter := range strings.Split(segment, ",") {
+			key, value, found := strings.Cut(parameter, "=")
+			if !found {
+				key, value = "", parameter
+			}
+
+			if _, ok := parameters.parameters[key]; ok {
+				return nil, fmt.Errorf("digest has a duplicate parameter '%s'", key)
+			}
+
+			parameters.parameters[key] = value
+		}
+	}
+
+	parameters.saltLength, parameters.keyLength = len(parts[n-2]), len(parts[n-1])
+
+	return parameters, nil
+}
@@ -0,0 +1,259 @@
+package authorization
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// NewAccessControlCondition compiles a condition expression written in the Common Expression Language. The expression
+// is type checked during compilation and must evaluate to a bool.
+func NewAccessControlCondition(expression string) (condition *AccessControlCondition, err error) {
+	var env *cel.Env
+
+	if env, err = getConditionEnv(); err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("the expression must evaluate to a bool but it evaluates to a %s", ast.OutputType())
+	}
+
+	var checked *exprpb.CheckedExpr
+
+	if checked, err = cel.AstToCheckedExpr(ast); err != nil {
+		return nil, err
+	}
+
+	if err = validateConditionNetworks(checked.GetExpr()); err != nil {
+		return nil, err
+	}
+
+	condition = &AccessControlCondition{
+		Expression: expression,
+	}
+
+	for _, reference := range checked.GetReferenceMap() {
+		switch reference.GetName() {
+		case conditionSubjectUsername, conditionSubjectGroups, conditionSubjectEmails, conditionSubjectAttributes:
+			condition.HasSubjects = true
+		case conditionSubjectLevel, conditionSubjectAMR:
+			condition.HasAuthentication = true
+		case conditionObjectHeaders:
+			condition.HasHeaders = true
+		}
+	}
+
+	if condition.program, err = env.Program(ast, cel.EvalOptions(cel.OptOptimize)); err != nil {
+		return nil, err
+	}
+
+	return condition, nil
+}
+
+// AccessControlCondition represents a compiled ACL condition expression.
+type AccessControlCondition struct {
+	Expression string
+
+	// HasSubjects is true if the expression refers to the identity of the subject, i.e. the username, groups, emails, or
+	// attributes.
+	HasSubjects bool
+
+	// HasAuthentication is true if the expression refers to the authentication of the subject, i.e. the authentication
+	// level or the authentication method references. These are known for anonymous subjects but may change once the
+	// subject authenticates.
+	HasAuthentication bool
+
+	// HasHeaders is true if the expression refers to the request headers.
+	HasHeaders bool
+
+	program cel.Program
+}
+
+// IsMatch returns true if the expression evaluates to true for the subject and object. An expression which fails to
+// evaluate, for example because it indexes a missing key, does not match.
+func (c *AccessControlCondition) IsMatch(subject Subject, object Object) (match bool) {
+	out, _, err := c.program.Eval(newConditionActivation(subject, object))
+	if err != nil {
+		return false
+	}
+
+	match, _ = out.Value().(bool)
+
+	return match
+}
+
+const (
+	conditionSubjectUsername   = "subject.username"
+	conditionSubjectGroups     = "subject.groups"
+	conditionSubjectEmails     = "subject.emails"
+	conditionSubjectAttributes = "subject.attributes"
+	conditionSubjectLevel      = "subject.level"
+	conditionSubjectAMR        = "subject.amr"
+	conditionSubjectIP         = "subject.ip"
+
+	conditionObjectURL     = "object.url"
+	conditionObjectDomain  = "object.domain"
+	conditionObjectPath    = "object.path"
+	conditionObjectMethod  = "object.method"
+	conditionObjectQuery   = "object.query"
+	conditionObjectHeaders = "object.headers"
+
+	conditionFunctionInNetwork = "inNetwork"
+)
+
+var (
+	conditionEnv     *cel.Env
+	conditionEnvErr  error
+	conditionEnvOnce sync.Once
+)
+
+func getConditionEnv() (env *cel.Env, err error) {
+	conditionEnvOnce.Do(func() {
+		stringList, stringListMap := cel.ListType(cel.StringType), cel.MapType(cel.StringType, cel.ListType(cel.StringType))
+
+		conditionEnv, conditionEnvErr = cel.NewEnv(
+			ext.Strings(),
+			cel.Variable(conditionSubjectUsername, cel.StringType),
+			cel.Variable(conditionSubjectGroups, stringList),
+			cel.Variable(conditionSubjectEmails, stringList),
+			cel.Variable(conditionSubjectAttributes, stringListMap),
+			cel.Variable(conditionSubjectLevel, cel.StringType),
+			cel.Variable(conditionSubjectAMR, stringList),
+			cel.Variable(conditionSubjectIP, cel.StringType),
+			cel.Variable(conditionObjectURL, cel.StringType),
+			cel.Variable(conditionObjectDomain, cel.StringType),
+			cel.Variable(conditionObjectPath, cel.StringType),
+			cel.Variable(conditionObjectMethod, cel.StringType),
+			cel.Variable(conditionObjectQuery, stringListMap),
+			cel.Variable(conditionObjectHeaders, stringListMap),
+			cel.Function(conditionFunctionInNetwork,
+				cel.MemberOverload("string_in_network_string", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+					cel.BinaryBinding(conditionInNetwork),
+				),
+			),
+		)
+	})
+
+	return conditionEnv, conditionEnvErr
+}
+
+func conditionInNetwork(lhs, rhs ref.Val) ref.Val {
+	ip := net.ParseIP(string(lhs.(types.String)))
+
+	cidr, err := parseNetwork(string(rhs.(types.String)))
+	if err != nil {
+		return types.NewErr("network '%s' is not a valid IP or CIDR notation", rhs)
+	}
+
+	return types.Bool(ip != nil && cidr.Contains(ip))
+}
+
+// validateConditionNetworks ensures the inNetwork function is only called with valid IP or CIDR notation literals so
+// invalid networks are reported when the expression is compiled instead of when it's evaluated.
+func validateConditionNetworks(expr *exprpb.Expr) (err error) {
+	if expr == nil {
+		return nil
+	}
+
+	var children []*exprpb.Expr
+
+	switch e := expr.GetExprKind().(type) {
+	case *exprpb.Expr_CallExpr:
+		if e.CallExpr.GetFunction() == conditionFunctionInNetwork {
+			network, ok := e.CallExpr.GetArgs()[0].GetConstExpr().GetConstantKind().(*exprpb.Constant_StringValue)
+			if !ok {
+				return fmt.Errorf("function '%s' requires a string literal argument", conditionFunctionInNetwork)
+			}
+
+			if _, err = parseNetwork(network.StringValue); err != nil {
+				return fmt.Errorf("function '%s' network '%s' is not a valid IP or CIDR notation", conditionFunctionInNetwork, network.StringValue)
+			}
+		}
+
+		children = append([]*exprpb.Expr{e.CallExpr.GetTarget()}, e.CallExpr.GetArgs()...)
+	case *exprpb.Expr_SelectExpr:
+		children = []*exprpb.Expr{e.SelectExpr.GetOperand()}
+	case *exprpb.Expr_ListExpr:
+		children = e.ListExpr.GetElements()
+	case *exprpb.Expr_StructExpr:
+		for _, entry := range e.StructExpr.GetEntries() {
+			children = append(children, entry.GetMapKey(), entry.GetValue())
+		}
+	case *exprpb.Expr_ComprehensionExpr:
+		c := e.ComprehensionExpr
+
+		children = []*exprpb.Expr{c.GetIterRange(), c.GetAccuInit(), c.GetLoopCondition(), c.GetLoopStep(), c.GetResult()}
+	}
+
+	for _, child := range children {
+		if err = validateConditionNetworks(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newConditionActivation returns the variables of an expression for the subject and object. The query and headers
+// are only converted if the expression refers to them. The header names are lower case.
+func newConditionActivation(subject Subject, object Object) map[string]any {
+	ip, requestURL, query := "", "", map[string][]string{}
+
+	if subject.IP != nil {
+		ip = subject.IP.String()
+	}
+
+	if object.URL != nil {
+		requestURL = object.URL.String()
+	}
+
+	return map[string]any{
+		conditionSubjectUsername:   subject.Username,
+		conditionSubjectGroups:     subject.Groups,
+		conditionSubjectEmails:     subject.Emails,
+		conditionSubjectAttributes: subject.Attributes,
+		conditionSubjectLevel:      subject.AuthenticationLevel.String(),
+		conditionSubjectAMR:        subject.AMR,
+		conditionSubjectIP:         ip,
+		conditionObjectURL:         requestURL,
+		conditionObjectDomain:      object.Domain,
+		conditionObjectPath:        object.Path,
+		conditionObjectMethod:      object.Method,
+		conditionObjectQuery: func() any {
+			if object.URL != nil {
+				query = object.URL.Query()
+			}
+
+			return query
+		},
+		conditionObjectHeaders: func() any {
+			return newConditionHeaders(object.Header)
+		},
+	}
+}
+
+func newConditionHeaders(header http.Header) (headers map[string][]string) {
+	headers = make(map[string][]string, len(header))
+
+	for name, values := range header {
+		name = strings.ToLower(name)
+
+		headers[name] = append(headers[name], values...)
+	}
+
+	return headers
+}
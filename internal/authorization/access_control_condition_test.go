@@ -0,0 +1,185 @@
+package authorization
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestConditionSubjectObject() (subject Subject, object Object) {
+	subject = Subject{
+		Username:   "john",
+		Groups:     []string{"admins", "dev"},
+		Emails:     []string{"john@example.com"},
+		Attributes: map[string][]string{"department": {"engineering"}},
+		IP:         net.ParseIP("192.168.1.20"),
+
+		AuthenticationLevel: authentication.TwoFactor,
+		AMR:                 []string{"pwd", "otp", "mfa"},
+	}
+
+	object = NewObject(&url.URL{Scheme: "https", Host: "app.example.com", Path: "/api/users", RawQuery: "page=2&debug"}, http.MethodPost)
+
+	object.Header = http.Header{"X-Forwarded-Proto": {"https"}, "Content-Type": {"application/json"}}
+
+	return subject, object
+}
+
+func TestAccessControlConditionIsMatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected bool
+	}{
+		{"ShouldMatchUsername", `subject.username == 'john'`, true},
+		{"ShouldNotMatchUsername", `subject.username == "harry"`, false},
+		{"ShouldMatchNotEqual", `subject.username != 'harry'`, true},
+		{"ShouldMatchGroup", `'admins' in subject.groups`, true},
+		{"ShouldNotMatchGroup", `'users' in subject.groups`, false},
+		{"ShouldMatchGroupMacro", `subject.groups.exists(g, g.startsWith('adm'))`, true},
+		{"ShouldMatchEmail", `subject.emails[0].endsWith('@example.com')`, true},
+		{"ShouldNotMatchOutOfRangeIndex", `subject.emails[5] == ''`, false},
+		{"ShouldMatchAttribute", `'engineering' in subject.attributes.department`, true},
+		{"ShouldMatchAttributeIndex", `subject.attributes['department'][0] == 'engineering'`, true},
+		{"ShouldNotMatchMissingAttribute", `size(subject.attributes.location) == 0`, false},
+		{"ShouldMatchAttributeKey", `'department' in subject.attributes && !('location' in subject.attributes)`, true},
+		{"ShouldMatchIP", `subject.ip.inNetwork('192.168.0.0/16')`, true},
+		{"ShouldNotMatchIP", `subject.ip.inNetwork('10.0.0.1')`, false},
+		{"ShouldMatchLevel", `subject.level == 'two_factor'`, true},
+		{"ShouldMatchAMR", `'mfa' in subject.amr && size(subject.amr) == 3`, true},
+		{"ShouldMatchDomain", `object.domain.startsWith('app.')`, true},
+		{"ShouldMatchPath", `object.path.matches('^/api/(users|groups)\\?')`, true},
+		{"ShouldMatchMethod", `object.method in ['POST', 'PUT']`, true},
+		{"ShouldMatchURL", `object.url == 'https://app.example.com/api/users?page=2&debug'`, true},
+		{"ShouldMatchQuery", `object.query.page[0] == '2' && 'debug' in object.query`, true},
+		{"ShouldMatchHeaderLowerCase", `object.headers['content-type'][0].contains('json')`, true},
+		{"ShouldNotMatchHeaderCanonical", `'X-Forwarded-Proto' in object.headers`, false},
+		{"ShouldMatchHeaderPresent", `'https' in object.headers['x-forwarded-proto'] && !('x-api-client' in object.headers)`, true},
+		{"ShouldMatchCaseFunctions", `subject.username.upperAscii() == 'JOHN' && 'JOHN'.lowerAscii() == subject.username`, true},
+		{"ShouldMatchIntComparison", `size(subject.groups) >= 2 && size(subject.groups) < 3 && 1 <= 1 && 2 > 1`, true},
+		{"ShouldMatchStringComparison", `'a' < 'b' && 'b' > 'a'`, true},
+		{"ShouldMatchPrecedence", `true || false && false`, true},
+		{"ShouldMatchParentheses", `(true || false) && false`, false},
+		{"ShouldMatchNegation", `!(subject.username == 'harry')`, true},
+		{"ShouldMatchBoolEquality", `('admins' in subject.groups) == true`, true},
+		{"ShouldMatchEscapes", `'it\'s' == "it's"`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			condition, err := NewAccessControlCondition(tc.have)
+
+			require.NoError(t, err)
+
+			subject, object := newTestConditionSubjectObject()
+
+			assert.Equal(t, tc.expected, condition.IsMatch(subject, object))
+		})
+	}
+}
+
+func TestAccessControlConditionShouldMatchAnonymousSubject(t *testing.T) {
+	condition, err := NewAccessControlCondition(`subject.level == 'not_authenticated' && size(subject.groups) == 0 && subject.ip == '' && object.url == ''`)
+
+	require.NoError(t, err)
+	assert.True(t, condition.IsMatch(Subject{}, Object{}))
+}
+
+func TestAccessControlConditionReferences(t *testing.T) {
+	testCases := []struct {
+		name                                         string
+		have                                         string
+		expectedSubjects, expectedAuthn, expectedHdr bool
+	}{
+		{"ShouldNotReferToAnything", `object.method == 'GET' && subject.ip.inNetwork('10.0.0.0/8')`, false, false, false},
+		{"ShouldReferToSubjects", `object.method == 'GET' || 'admins' in subject.groups`, true, false, false},
+		{"ShouldReferToSubjectsMacro", `subject.emails.exists(e, e.endsWith('@example.com'))`, true, false, false},
+		{"ShouldReferToAuthentication", `subject.level == 'two_factor' || 'hwk' in subject.amr`, false, true, false},
+		{"ShouldReferToHeaders", `'x-api-client' in object.headers`, false, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			condition, err := NewAccessControlCondition(tc.have)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSubjects, condition.HasSubjects)
+			assert.Equal(t, tc.expectedAuthn, condition.HasAuthentication)
+			assert.Equal(t, tc.expectedHdr, condition.HasHeaders)
+		})
+	}
+}
+
+func TestShouldErrorInvalidAccessControlCondition(t *testing.T) {
+	testCases := []struct {
+		name string
+		have string
+		err  string
+	}{
+		{"ShouldErrorNotBool", `subject.username`, "the expression must evaluate to a bool but it evaluates to a string"},
+		{"ShouldErrorEmpty", ``, "ERROR: <input>:1:1: Syntax error: mismatched input '<EOF>'"},
+		{"ShouldErrorUnknownIdentifier", `user == 'john'`, "ERROR: <input>:1:1: undeclared reference to 'user'"},
+		{"ShouldErrorUnknownField", `subject.name == 'john'`, "ERROR: <input>:1:1: undeclared reference to 'subject'"},
+		{"ShouldErrorUnknownFunction", `subject.username.exists() == ''`, "ERROR: <input>:1:24: undeclared reference to 'exists'"},
+		{"ShouldErrorComparisonTypes", `subject.groups == 'admins'`, "ERROR: <input>:1:16: found no matching overload for '_==_'"},
+		{"ShouldErrorMatchesPattern", `object.path.matches('^(')`, "error parsing regexp: missing closing ): `^(`"},
+		{"ShouldErrorInNetwork", `subject.ip.inNetwork('example.com')`, "function 'inNetwork' network 'example.com' is not a valid IP or CIDR notation"},
+		{"ShouldErrorInNetworkNested", `subject.groups.exists(g, subject.ip.inNetwork('example.com'))`, "function 'inNetwork' network 'example.com' is not a valid IP or CIDR notation"},
+		{"ShouldErrorInNetworkNotLiteral", `subject.ip.inNetwork(subject.username)`, "function 'inNetwork' requires a string literal argument"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			condition, err := NewAccessControlCondition(tc.have)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+			assert.Nil(t, condition)
+		})
+	}
+}
+
+func TestAccessControlRuleShouldMatchConditionAnonymous(t *testing.T) {
+	rule := NewAccessControlRule(1, schema.ACLRule{
+		Domains:   []string{"app.example.com"},
+		Policy:    "one_factor",
+		Condition: `'admins' in subject.groups`,
+	}, nil, nil, nil, nil)
+
+	require.NotNil(t, rule.Condition)
+	assert.True(t, rule.HasSubjects)
+
+	_, object := newTestConditionSubjectObject()
+
+	assert.True(t, rule.MatchesCondition(Subject{}, object))
+	assert.False(t, rule.MatchesConditionExact(Subject{}, object))
+	assert.True(t, rule.MatchesConditionExact(Subject{Username: "john", Groups: []string{"admins"}}, object))
+	assert.False(t, rule.MatchesCondition(Subject{Username: "harry", Groups: []string{"users"}}, object))
+
+	rule = NewAccessControlRule(1, schema.ACLRule{
+		Domains:   []string{"app.example.com"},
+		Policy:    "one_factor",
+		Condition: `object.method == 'GET'`,
+	}, nil, nil, nil, nil)
+
+	assert.False(t, rule.HasSubjects)
+	assert.False(t, rule.MatchesCondition(Subject{}, object))
+
+	rule = NewAccessControlRule(1, schema.ACLRule{
+		Domains:   []string{"app.example.com"},
+		Policy:    "two_factor",
+		Condition: `'hwk' in subject.amr`,
+	}, nil, nil, nil, nil)
+
+	assert.True(t, rule.HasSubjects)
+	assert.True(t, rule.MatchesCondition(Subject{}, object))
+	assert.False(t, rule.MatchesConditionExact(Subject{}, object))
+	assert.True(t, rule.MatchesCondition(Subject{Username: "john", AMR: []string{"pwd", "hwk"}}, object))
+}
@@ -0,0 +1,71 @@
+package authorization
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// NewAccessControlGeoNetwork parses a country or autonomous system network. The network is nil if the value is not a
+// country or autonomous system network.
+func NewAccessControlGeoNetwork(value string, provider GeoIPProvider) (network *AccessControlGeoNetwork, err error) {
+	switch {
+	case strings.HasPrefix(value, prefixNetworkCountry):
+		code := strings.ToUpper(strings.TrimPrefix(value, prefixNetworkCountry))
+
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			return nil, fmt.Errorf("the country '%s' is not a valid ISO 3166-1 alpha-2 country code", code)
+		}
+
+		return &AccessControlGeoNetwork{Provider: provider, Country: code}, nil
+	case strings.HasPrefix(value, prefixNetworkASN):
+		number := strings.TrimPrefix(value, prefixNetworkASN)
+
+		if len(number) > 2 && strings.EqualFold(number[:2], "AS") {
+			number = number[2:]
+		}
+
+		asn, err := strconv.ParseUint(number, 10, 32)
+		if err != nil || asn == 0 {
+			return nil, fmt.Errorf("the autonomous system number '%s' is not a valid number", number)
+		}
+
+		return &AccessControlGeoNetwork{Provider: provider, ASN: uint(asn)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// AccessControlGeoNetwork represents an ACL network which matches IPs located in a country or announced by an
+// autonomous system according to the GeoIP databases.
+type AccessControlGeoNetwork struct {
+	Provider GeoIPProvider
+
+	Country string
+	ASN     uint
+}
+
+// IsMatch returns true if the IP is located in the country or announced by the autonomous system.
+func (n AccessControlGeoNetwork) IsMatch(ip net.IP) (match bool) {
+	if ip == nil || n.Provider == nil {
+		return false
+	}
+
+	record := n.Provider.Lookup(ip)
+
+	if n.Country != "" {
+		return record.Country == n.Country
+	}
+
+	return record.ASN != 0 && record.ASN == n.ASN
+}
+
+// String returns the network in the configuration format.
+func (n AccessControlGeoNetwork) String() string {
+	if n.Country != "" {
+		return prefixNetworkCountry + n.Country
+	}
+
+	return fmt.Sprintf("%s%d", prefixNetworkASN, n.ASN)
+}
@@ -0,0 +1,128 @@
+package authorization
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/geoip"
+)
+
+type testGeoIPProvider map[string]geoip.Record
+
+func (p testGeoIPProvider) Lookup(ip net.IP) (record geoip.Record) {
+	return p[ip.String()]
+}
+
+func TestNewAccessControlGeoNetwork(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected *AccessControlGeoNetwork
+		err      string
+	}{
+		{"ShouldParseCountry", "country:DE", &AccessControlGeoNetwork{Country: "DE"}, ""},
+		{"ShouldParseCountryLowerCase", "country:de", &AccessControlGeoNetwork{Country: "DE"}, ""},
+		{"ShouldParseASN", "asn:13335", &AccessControlGeoNetwork{ASN: 13335}, ""},
+		{"ShouldParseASNWithPrefix", "asn:AS13335", &AccessControlGeoNetwork{ASN: 13335}, ""},
+		{"ShouldIgnoreCIDR", "10.0.0.0/8", nil, ""},
+		{"ShouldIgnoreNamedNetwork", "internal", nil, ""},
+		{"ShouldErrorCountryLength", "country:DEU", nil, "the country 'DEU' is not a valid ISO 3166-1 alpha-2 country code"},
+		{"ShouldErrorCountryCharacters", "country:D1", nil, "the country 'D1' is not a valid ISO 3166-1 alpha-2 country code"},
+		{"ShouldErrorASN", "asn:cloudflare", nil, "the autonomous system number 'cloudflare' is not a valid number"},
+		{"ShouldErrorASNZero", "asn:0", nil, "the autonomous system number '0' is not a valid number"},
+		{"ShouldErrorASNTooLarge", "asn:4294967296", nil, "the autonomous system number '4294967296' is not a valid number"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			network, err := NewAccessControlGeoNetwork(tc.have, nil)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, network)
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, network)
+			}
+		})
+	}
+}
+
+func TestAccessControlGeoNetworkString(t *testing.T) {
+	assert.Equal(t, "country:DE", AccessControlGeoNetwork{Country: "DE"}.String())
+	assert.Equal(t, "asn:13335", AccessControlGeoNetwork{ASN: 13335}.String())
+}
+
+func TestAccessControlRulesShouldMatchGeoNetworks(t *testing.T) {
+	provider := testGeoIPProvider{
+		"1.1.1.1":      {Country: "AU", ASN: 13335},
+		"81.2.69.1":    {Country: "GB", ASN: 20712},
+		"185.1.1.1":    {Country: "DE", ASN: 3320},
+		"192.168.1.10": {},
+	}
+
+	rules := NewAccessControlRules(schema.AccessControlConfiguration{
+		DefaultPolicy: deny,
+		Networks: []schema.ACLNetwork{
+			{Name: "eu", Networks: []string{"country:DE", "country:FR"}},
+			{Name: "internal", Networks: []string{"192.168.1.0/24", "asn:20712"}},
+		},
+		Rules: []schema.ACLRule{
+			{Domains: []string{"admin.example.com"}, Policy: oneFactor, Networks: []string{"eu", "internal"}},
+			{Domains: []string{"admin.example.com"}, Policy: deny},
+			{Domains: []string{"app.example.com"}, Policy: twoFactor, Networks: []string{"asn:AS13335", "country:de"}},
+		},
+	}, provider)
+
+	require.Len(t, rules, 3)
+
+	assert.Equal(t, []AccessControlGeoNetwork{{Provider: provider, Country: "DE"}, {Provider: provider, Country: "FR"}, {Provider: provider, ASN: 20712}}, rules[0].GeoNetworks)
+	assert.Len(t, rules[0].Networks, 1)
+	assert.Len(t, rules[1].GeoNetworks, 0)
+	assert.Len(t, rules[2].Networks, 0)
+
+	testCases := []struct {
+		name     string
+		domain   string
+		ip       string
+		expected int
+	}{
+		{"ShouldMatchNamedCountry", "admin.example.com", "185.1.1.1", 1},
+		{"ShouldMatchNamedASN", "admin.example.com", "81.2.69.1", 1},
+		{"ShouldMatchNamedCIDR", "admin.example.com", "192.168.1.10", 1},
+		{"ShouldNotMatchNamedOutside", "admin.example.com", "1.1.1.1", 2},
+		{"ShouldMatchASN", "app.example.com", "1.1.1.1", 3},
+		{"ShouldMatchCountry", "app.example.com", "185.1.1.1", 3},
+		{"ShouldNotMatch", "app.example.com", "81.2.69.1", 0},
+		{"ShouldNotMatchUnknownIP", "app.example.com", "8.8.8.8", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			subject := Subject{IP: net.ParseIP(tc.ip)}
+			object := NewObject(&url.URL{Scheme: "https", Host: tc.domain, Path: "/"}, "GET")
+
+			actual := 0
+
+			for _, rule := range rules {
+				if rule.IsMatch(subject, object) {
+					actual = rule.Position
+
+					break
+				}
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestAuthorizerShouldCreateGeoIPProvider(t *testing.T) {
+	assert.Nil(t, NewAuthorizer(&schema.Configuration{}).GeoIP())
+	assert.NotNil(t, NewAuthorizer(&schema.Configuration{GeoIP: schema.GeoIPConfiguration{CountryDatabase: "/tmp/country.mmdb"}}).GeoIP())
+}
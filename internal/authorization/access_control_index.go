@@ -0,0 +1,154 @@
+package authorization
+
+import (
+	"sort"
+	"strings"
+)
+
+// newAccessControlIndex precompiles an index of the rules by the domains they match. Rules with basic domains are
+// indexed by exact domain, rules with wildcard domains are indexed in a trie of the domain labels in reverse order, and
+// rules with regex domains, without domains, or with domains which can't be indexed are always evaluated.
+func newAccessControlIndex(rules []*AccessControlRule) (index *accessControlIndex) {
+	index = &accessControlIndex{
+		rules:    rules,
+		exact:    map[string][]int{},
+		wildcard: &accessControlIndexNode{},
+	}
+
+	for i, rule := range rules {
+		if len(rule.Domains) == 0 {
+			index.regex = appendAccessControlIndexPosition(index.regex, i)
+
+			continue
+		}
+
+		for _, domain := range rule.Domains {
+			m, ok := domain.Matcher.(*AccessControlDomainMatcher)
+
+			switch {
+			case !ok:
+				index.regex = appendAccessControlIndexPosition(index.regex, i)
+			case !m.Wildcard && !m.UserWildcard && !m.GroupWildcard:
+				index.exact[m.Name] = appendAccessControlIndexPosition(index.exact[m.Name], i)
+			case len(m.Name) > 1 && m.Name[0] == '.':
+				index.wildcard.add(strings.Split(m.Name[1:], "."), i)
+			default:
+				index.regex = appendAccessControlIndexPosition(index.regex, i)
+			}
+		}
+	}
+
+	return index
+}
+
+// accessControlIndex is a precompiled index of rules which narrows the rules that must be evaluated for an object to
+// the rules which may match the domain of the object. The candidates are always returned in the order of the rules so
+// the first matching rule is the same as it would be if every rule was evaluated.
+type accessControlIndex struct {
+	rules []*AccessControlRule
+
+	exact    map[string][]int
+	wildcard *accessControlIndexNode
+	regex    []int
+}
+
+// candidates returns the rules which may match the domain in the order they're configured.
+func (idx *accessControlIndex) candidates(domain string) (rules []*AccessControlRule) {
+	domain = strings.ToLower(domain)
+
+	buckets := make([][]int, 0, 4)
+
+	if positions, ok := idx.exact[domain]; ok {
+		buckets = append(buckets, positions)
+	}
+
+	buckets = idx.wildcard.collect(strings.Split(domain, "."), buckets)
+
+	if len(idx.regex) != 0 {
+		buckets = append(buckets, idx.regex)
+	}
+
+	switch len(buckets) {
+	case 0:
+		return nil
+	case 1:
+		rules = make([]*AccessControlRule, len(buckets[0]))
+
+		for i, position := range buckets[0] {
+			rules[i] = idx.rules[position]
+		}
+
+		return rules
+	}
+
+	var positions []int
+
+	for _, bucket := range buckets {
+		positions = append(positions, bucket...)
+	}
+
+	sort.Ints(positions)
+
+	for i, position := range positions {
+		if i != 0 && positions[i-1] == position {
+			continue
+		}
+
+		rules = append(rules, idx.rules[position])
+	}
+
+	return rules
+}
+
+// accessControlIndexNode is a node in the wildcard domain trie. Each node represents a domain suffix and the rules
+// which match any domain which ends with that suffix and has at least one additional label.
+type accessControlIndexNode struct {
+	children map[string]*accessControlIndexNode
+	rules    []int
+}
+
+func (n *accessControlIndexNode) add(labels []string, position int) {
+	node := n
+
+	for i := len(labels) - 1; i >= 0; i-- {
+		if node.children == nil {
+			node.children = map[string]*accessControlIndexNode{}
+		}
+
+		child, ok := node.children[labels[i]]
+		if !ok {
+			child = &accessControlIndexNode{}
+			node.children[labels[i]] = child
+		}
+
+		node = child
+	}
+
+	node.rules = appendAccessControlIndexPosition(node.rules, position)
+}
+
+func (n *accessControlIndexNode) collect(labels []string, buckets [][]int) [][]int {
+	node := n
+
+	for i := len(labels) - 1; i > 0; i-- {
+		var ok bool
+
+		if node, ok = node.children[labels[i]]; !ok {
+			break
+		}
+
+		if len(node.rules) != 0 {
+			buckets = append(buckets, node.rules)
+		}
+	}
+
+	return buckets
+}
+
+func appendAccessControlIndexPosition(positions []int, position int) []int {
+	if n := len(positions); n != 0 && positions[n-1] == position {
+		return positions
+	}
+
+	return append(positions, position)
+}
@@ -0,0 +1,173 @@
+package authorization
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newAccessControlIndexTestConfig() schema.AccessControlConfiguration {
+	return schema.AccessControlConfiguration{
+		DefaultPolicy: deny,
+		Rules: []schema.ACLRule{
+			{Domains: []string{"app.example.com"}, Policy: bypass},
+			{Domains: []string{"*.example.com"}, Policy: oneFactor, Subjects: [][]string{{"group:admins"}}},
+			{Domains: []string{"{user}.users.example.com"}, Policy: twoFactor},
+			{DomainsRegex: []regexp.Regexp{*regexp.MustCompile(`^api-[a-z]+\.example\.org$`)}, Policy: oneFactor},
+			{Domains: []string{"APP.example.com", "app.example.com", "*.app.example.com"}, Policy: twoFactor},
+			{Domains: []string{"{group}.groups.example.com"}, Policy: oneFactor},
+			{Domains: []string{"*.example.org"}, Policy: twoFactor},
+		},
+	}
+}
+
+func TestAccessControlIndexCandidates(t *testing.T) {
+	index := newAccessControlIndex(NewAccessControlRules(newAccessControlIndexTestConfig(), nil))
+
+	testCases := []struct {
+		name     string
+		domain   string
+		expected []int
+	}{
+		{"ShouldIncludeExactWildcardAndRegex", "app.example.com", []int{1, 2, 4, 5}},
+		{"ShouldIncludeExactCaseInsensitive", "APP.Example.com", []int{1, 2, 4, 5}},
+		{"ShouldIncludeNestedWildcards", "x.app.example.com", []int{2, 4, 5}},
+		{"ShouldIncludeUserWildcard", "john.users.example.com", []int{2, 3, 4}},
+		{"ShouldIncludeGroupWildcard", "dev.groups.example.com", []int{2, 4, 6}},
+		{"ShouldIncludeWildcardAndRegex", "api-one.example.org", []int{4, 7}},
+		{"ShouldNotIncludeWildcardForApexDomain", "example.com", []int{4}},
+		{"ShouldOnlyIncludeRegex", "example.net", []int{4}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []int
+
+			for _, rule := range index.candidates(tc.domain) {
+				actual = append(actual, rule.Position)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestAccessControlIndexShouldMatchLinearEvaluation(t *testing.T) {
+	config := newAccessControlIndexTestConfig()
+	config.Rules = append(config.Rules, newAccessControlIndexBenchmarkRules(200)...)
+
+	authorizer := NewAuthorizer(&schema.Configuration{AccessControl: config})
+
+	subjects := []Subject{
+		{},
+		{Username: "john", Groups: []string{"admins", "dev"}, IP: net.ParseIP("10.0.0.1")},
+		{Username: "harry", Groups: []string{"users"}, IP: net.ParseIP("192.168.1.1")},
+	}
+
+	domains := []string{
+		"app.example.com", "x.app.example.com", "john.users.example.com", "harry.users.example.com",
+		"dev.groups.example.com", "api-one.example.org", "example.com", "example.net",
+		"app42.example.com", "x.wild42.example.com", "wild42.example.com", "app199.example.com",
+	}
+
+	for _, domain := range domains {
+		for _, subject := range subjects {
+			object := NewObject(&url.URL{Scheme: "https", Host: domain, Path: "/admin"}, "GET")
+
+			expected := Requirement{Level: authorizer.defaultPolicy}
+
+			for _, rule := range authorizer.index.rules {
+				if rule.IsMatch(subject, object) {
+					expected = Requirement{Matched: true, Position: rule.Position, HasSubjects: rule.HasSubjects, Level: rule.Policy}
+
+					break
+				}
+			}
+
+			assert.Equal(t, expected, authorizer.GetRequirement(subject, object), "domain %s subject %s", domain, subject)
+		}
+	}
+}
+
+type testAccessControlMetricsRecorder struct {
+	matched, unmatched int
+}
+
+func (r *testAccessControlMetricsRecorder) RecordAccessControlEvaluation(matched bool, _ time.Duration) {
+	if matched {
+		r.matched++
+	} else {
+		r.unmatched++
+	}
+}
+
+func TestAuthorizerShouldRecordEvaluationMetrics(t *testing.T) {
+	authorizer := NewAuthorizer(&schema.Configuration{AccessControl: newAccessControlIndexTestConfig()})
+
+	recorder := &testAccessControlMetricsRecorder{}
+
+	authorizer.SetMetricsRecorder(recorder)
+
+	authorizer.GetRequirement(Subject{}, NewObject(&url.URL{Scheme: "https", Host: "app.example.com"}, "GET"))
+	authorizer.GetRequirement(Subject{}, NewObject(&url.URL{Scheme: "https", Host: "example.net"}, "GET"))
+	authorizer.GetRequirement(Subject{}, NewObject(&url.URL{Scheme: "https", Host: "example.com"}, "GET"))
+
+	assert.Equal(t, 1, recorder.matched)
+	assert.Equal(t, 2, recorder.unmatched)
+}
+
+func newAccessControlIndexBenchmarkRules(n int) (rules []schema.ACLRule) {
+	for i := 0; i < n; i++ {
+		switch i % 4 {
+		case 0:
+			rules = append(rules, schema.ACLRule{Domains: []string{fmt.Sprintf("app%d.example.com", i)}, Policy: oneFactor, Subjects: [][]string{{"group:admins"}}})
+		case 1:
+			rules = append(rules, schema.ACLRule{Domains: []string{fmt.Sprintf("app%d.example.com", i)}, Policy: twoFactor, Resources: []regexp.Regexp{*regexp.MustCompile(`^/admin.*$`)}})
+		case 2:
+			rules = append(rules, schema.ACLRule{Domains: []string{fmt.Sprintf("*.wild%d.example.com", i)}, Policy: oneFactor, Networks: []string{"10.0.0.0/8"}})
+		default:
+			rules = append(rules, schema.ACLRule{Domains: []string{fmt.Sprintf("app%d.example.com", i), fmt.Sprintf("app%d.example.net", i)}, Policy: bypass, Methods: []string{"GET"}})
+		}
+	}
+
+	return rules
+}
+
+func BenchmarkAuthorizerGetRequirement(b *testing.B) {
+	authorizer := NewAuthorizer(&schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: deny,
+			Rules:         newAccessControlIndexBenchmarkRules(2000),
+		},
+	})
+
+	subject := Subject{Username: "john", Groups: []string{"users"}, IP: net.ParseIP("192.168.1.1")}
+	object := NewObject(&url.URL{Scheme: "https", Host: "app1999.example.com", Path: "/"}, "GET")
+
+	b.Run("Indexed", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			authorizer.GetRequirement(subject, object)
+		}
+	})
+
+	b.Run("Linear", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			for _, rule := range authorizer.index.rules {
+				if rule.IsMatch(subject, object) {
+					break
+				}
+			}
+		}
+	})
+}
@@ -2,34 +2,55 @@ package authorization
 
 import (
 	"net"
+	"time"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
-// NewAccessControlRules converts a schema.AccessControlConfiguration into an AccessControlRule slice.
-func NewAccessControlRules(config schema.AccessControlConfiguration) (rules []*AccessControlRule) {
+// NewAccessControlRules converts a schema.AccessControlConfiguration into an AccessControlRule slice. The
+// GeoIPProvider is used to match the country and autonomous system networks and may be nil if there are none.
+func NewAccessControlRules(config schema.AccessControlConfiguration, provider GeoIPProvider) (rules []*AccessControlRule) {
 	networksMap, networksCacheMap := parseSchemaNetworks(config.Networks)
+	geoNetworksMap := parseSchemaGeoNetworks(config.Networks, provider)
 
 	for i, schemaRule := range config.Rules {
-		rules = append(rules, NewAccessControlRule(i+1, schemaRule, networksMap, networksCacheMap))
+		rules = append(rules, NewAccessControlRule(i+1, schemaRule, networksMap, networksCacheMap, geoNetworksMap, provider))
 	}
 
 	return rules
 }
 
 // NewAccessControlRule parses a schema ACL and generates an internal ACL.
-func NewAccessControlRule(pos int, rule schema.ACLRule, networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) *AccessControlRule {
+func NewAccessControlRule(pos int, rule schema.ACLRule, networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet,
+	geoNetworksMap map[string][]AccessControlGeoNetwork, provider GeoIPProvider) *AccessControlRule {
 	r := &AccessControlRule{
-		Position: pos,
-		Query:    NewAccessControlQuery(rule.Query),
-		Methods:  schemaMethodsToACL(rule.Methods),
-		Networks: schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
-		Subjects: schemaSubjectsToACL(rule.Subjects),
-		Policy:   NewLevel(rule.Policy),
+		Position:    pos,
+		Query:       NewAccessControlQuery(rule.Query),
+		Headers:     NewAccessControlHeaders(rule.Headers),
+		Methods:     schemaMethodsToACL(rule.Methods),
+		Networks:    schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
+		GeoNetworks: schemaGeoNetworksToACL(rule.Networks, geoNetworksMap, provider),
+		Subjects:    schemaSubjectsToACL(rule.Subjects),
+		Policy:      NewLevel(rule.Policy),
+
+		SecondFactorMethods: rule.SecondFactorMethods,
+
+		MaxAuthenticationAgeFirstFactor:  rule.MaxAuthenticationAge.FirstFactor,
+		MaxAuthenticationAgeSecondFactor: rule.MaxAuthenticationAge.SecondFactor,
 	}
 
-	if len(r.Subjects) != 0 {
+	// The schedule is validated by the configuration validator so an error is not expected here.
+	r.Schedule, _ = NewAccessControlSchedule(rule.Schedule)
+
+	// The condition is validated by the configuration validator so an error is not expected here.
+	if rule.Condition != "" {
+		r.Condition, _ = NewAccessControlCondition(rule.Condition)
+	}
+
+	// Conditions which refer to the authentication of the subject are treated the same as the conditions which refer to
+	// the identity of the subject as they may match once an anonymous subject authenticates.
+	if len(r.Subjects) != 0 || (r.Condition != nil && (r.Condition.HasSubjects || r.Condition.HasAuthentication)) {
 		r.HasSubjects = true
 	}
 
@@ -44,14 +65,23 @@ func NewAccessControlRule(pos int, rule schema.ACLRule, networksMap map[string][
 type AccessControlRule struct {
 	HasSubjects bool
 
-	Position  int
-	Domains   []AccessControlDomain
-	Resources []AccessControlResource
-	Query     []AccessControlQuery
-	Methods   []string
-	Networks  []*net.IPNet
-	Subjects  []AccessControlSubjects
-	Policy    Level
+	Position    int
+	Domains     []AccessControlDomain
+	Resources   []AccessControlResource
+	Query       []AccessControlQuery
+	Headers     []AccessControlHeaders
+	Methods     []string
+	Networks    []*net.IPNet
+	GeoNetworks []AccessControlGeoNetwork
+	Schedule    *AccessControlSchedule
+	Subjects    []AccessControlSubjects
+	Condition   *AccessControlCondition
+	Policy      Level
+
+	SecondFactorMethods []string
+
+	MaxAuthenticationAgeFirstFactor  time.Duration
+	MaxAuthenticationAgeSecondFactor time.Duration
 }
 
 // IsMatch returns true if all elements of an AccessControlRule match the object and subject.
@@ -68,6 +98,10 @@ func (acr *AccessControlRule) IsMatch(subject Subject, object Object) (match boo
 		return false
 	}
 
+	if !acr.MatchesHeaders(object) {
+		return false
+	}
+
 	if !acr.MatchesMethods(object) {
 		return false
 	}
@@ -76,10 +110,18 @@ func (acr *AccessControlRule) IsMatch(subject Subject, object Object) (match boo
 		return false
 	}
 
+	if !acr.MatchesSchedule(object) {
+		return false
+	}
+
 	if !acr.MatchesSubjects(subject) {
 		return false
 	}
 
+	if !acr.MatchesCondition(subject, object) {
+		return false
+	}
+
 	return true
 }
 
@@ -134,6 +176,23 @@ func (acr *AccessControlRule) MatchesQuery(object Object) (match bool) {
 	return false
 }
 
+// MatchesHeaders returns true if the rule matches the request headers.
+func (acr *AccessControlRule) MatchesHeaders(object Object) (match bool) {
+	// If there are no header rules in this rule then the header condition is a match.
+	if len(acr.Headers) == 0 {
+		return true
+	}
+
+	// Iterate over the headers until we find a match (return true) or until we exit the loop (return false).
+	for _, headers := range acr.Headers {
+		if headers.IsMatch(object) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // MatchesMethods returns true if the rule matches the method.
 func (acr *AccessControlRule) MatchesMethods(object Object) (match bool) {
 	// If there are no methods in this rule then the method condition is a match.
@@ -147,7 +206,7 @@ func (acr *AccessControlRule) MatchesMethods(object Object) (match bool) {
 // MatchesNetworks returns true if the rule matches the networks.
 func (acr *AccessControlRule) MatchesNetworks(subject Subject) (match bool) {
 	// If there are no networks in this rule then the network condition is a match.
-	if len(acr.Networks) == 0 {
+	if len(acr.Networks) == 0 && len(acr.GeoNetworks) == 0 {
 		return true
 	}
 
@@ -158,9 +217,25 @@ func (acr *AccessControlRule) MatchesNetworks(subject Subject) (match bool) {
 		}
 	}
 
+	for _, network := range acr.GeoNetworks {
+		if network.IsMatch(subject.IP) {
+			return true
+		}
+	}
+
 	return false
 }
 
+// MatchesSchedule returns true if the rule matches the schedule.
+func (acr *AccessControlRule) MatchesSchedule(object Object) (match bool) {
+	// If there is no schedule in this rule then the schedule condition is a match.
+	if acr.Schedule == nil {
+		return true
+	}
+
+	return acr.Schedule.IsMatch(object)
+}
+
 // MatchesSubjects returns true if the rule matches the subjects.
 func (acr *AccessControlRule) MatchesSubjects(subject Subject) (match bool) {
 	if subject.IsAnonymous() {
@@ -188,3 +263,23 @@ func (acr *AccessControlRule) MatchesSubjectExact(subject Subject) (match bool)
 
 	return false
 }
+
+// MatchesCondition returns true if the rule matches the condition. Conditions which refer to the identity or the
+// authentication of the subject are always a match for anonymous subjects.
+func (acr *AccessControlRule) MatchesCondition(subject Subject, object Object) (match bool) {
+	if acr.Condition != nil && (acr.Condition.HasSubjects || acr.Condition.HasAuthentication) && subject.IsAnonymous() {
+		return true
+	}
+
+	return acr.MatchesConditionExact(subject, object)
+}
+
+// MatchesConditionExact returns true if the rule matches the condition exactly.
+func (acr *AccessControlRule) MatchesConditionExact(subject Subject, object Object) (match bool) {
+	// If there is no condition in this rule then the condition is a match.
+	if acr.Condition == nil {
+		return true
+	}
+
+	return acr.Condition.IsMatch(subject, object)
+}
@@ -0,0 +1,279 @@
+package authorization
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewAccessControlSchedule creates a new AccessControlSchedule from a schema.ACLSchedule. A nil schedule is returned if
+// the schedule has no criteria, and an error is returned if it has no criteria but has a timezone.
+func NewAccessControlSchedule(config schema.ACLSchedule) (schedule *AccessControlSchedule, err error) {
+	location := time.Local
+
+	if config.Timezone != "" {
+		if location, err = time.LoadLocation(config.Timezone); err != nil {
+			return nil, fmt.Errorf("timezone '%s' is invalid: %w", config.Timezone, err)
+		}
+	}
+
+	if len(config.Days) == 0 && len(config.Times) == 0 && len(config.Dates) == 0 {
+		if config.Timezone != "" {
+			return nil, fmt.Errorf("timezone '%s' is configured without any days, times, or dates", config.Timezone)
+		}
+
+		return nil, nil
+	}
+
+	schedule = &AccessControlSchedule{
+		Location: location,
+	}
+
+	for _, value := range config.Days {
+		var days AccessControlScheduleDays
+
+		if days, err = parseScheduleDays(value); err != nil {
+			return nil, err
+		}
+
+		schedule.Days = append(schedule.Days, days)
+	}
+
+	for _, value := range config.Times {
+		var times AccessControlScheduleTimes
+
+		if times, err = parseScheduleTimes(value); err != nil {
+			return nil, err
+		}
+
+		schedule.Times = append(schedule.Times, times)
+	}
+
+	for _, value := range config.Dates {
+		var dates AccessControlScheduleDates
+
+		if dates, err = parseScheduleDates(value); err != nil {
+			return nil, err
+		}
+
+		schedule.Dates = append(schedule.Dates, dates)
+	}
+
+	return schedule, nil
+}
+
+// AccessControlSchedule represents an ACL schedule rule. Each of the days, times, and dates criteria must match if they
+// are configured, and each criteria matches if any of its values match.
+type AccessControlSchedule struct {
+	Location *time.Location
+
+	Days  []AccessControlScheduleDays
+	Times []AccessControlScheduleTimes
+	Dates []AccessControlScheduleDates
+}
+
+// IsMatch returns true if the time of the object is within the schedule.
+func (acs *AccessControlSchedule) IsMatch(object Object) (match bool) {
+	now := object.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	now = now.In(acs.Location)
+
+	return acs.matchesDays(now) && acs.matchesTimes(now) && acs.matchesDates(now)
+}
+
+func (acs *AccessControlSchedule) matchesDays(now time.Time) (match bool) {
+	if len(acs.Days) == 0 {
+		return true
+	}
+
+	for _, days := range acs.Days {
+		if days.IsMatch(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (acs *AccessControlSchedule) matchesTimes(now time.Time) (match bool) {
+	if len(acs.Times) == 0 {
+		return true
+	}
+
+	for _, times := range acs.Times {
+		if times.IsMatch(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (acs *AccessControlSchedule) matchesDates(now time.Time) (match bool) {
+	if len(acs.Dates) == 0 {
+		return true
+	}
+
+	for _, dates := range acs.Dates {
+		if dates.IsMatch(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AccessControlScheduleDays represents an inclusive range of days of the week which may wrap around the end of the week.
+type AccessControlScheduleDays struct {
+	Start, End time.Weekday
+}
+
+// IsMatch returns true if the day of the week is within the range.
+func (d AccessControlScheduleDays) IsMatch(now time.Time) (match bool) {
+	day := now.Weekday()
+
+	if d.Start <= d.End {
+		return day >= d.Start && day <= d.End
+	}
+
+	return day >= d.Start || day <= d.End
+}
+
+// AccessControlScheduleTimes represents a range of times of the day which includes the start and excludes the end. If
+// the end is before the start the range wraps around midnight.
+type AccessControlScheduleTimes struct {
+	Start, End time.Duration
+}
+
+// IsMatch returns true if the time of the day is within the range.
+func (t AccessControlScheduleTimes) IsMatch(now time.Time) (match bool) {
+	offset := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute + time.Duration(now.Second())*time.Second
+
+	if t.Start < t.End {
+		return offset >= t.Start && offset < t.End
+	}
+
+	return offset >= t.Start || offset < t.End
+}
+
+// AccessControlScheduleDates represents an inclusive range of dates.
+type AccessControlScheduleDates struct {
+	Start, End string
+}
+
+// IsMatch returns true if the date is within the range.
+func (d AccessControlScheduleDates) IsMatch(now time.Time) (match bool) {
+	date := now.Format(layoutScheduleDate)
+
+	return date >= d.Start && date <= d.End
+}
+
+func parseScheduleDays(value string) (days AccessControlScheduleDays, err error) {
+	start, end, ok := strings.Cut(value, "-")
+
+	if days.Start, err = parseScheduleWeekday(start); err != nil {
+		return days, err
+	}
+
+	if !ok {
+		days.End = days.Start
+
+		return days, nil
+	}
+
+	if days.End, err = parseScheduleWeekday(end); err != nil {
+		return days, err
+	}
+
+	return days, nil
+}
+
+func parseScheduleWeekday(value string) (day time.Weekday, err error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	for day = time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+
+		if value == name || value == name[:3] {
+			return day, nil
+		}
+	}
+
+	return 0, fmt.Errorf("day '%s' is not a valid day of the week", value)
+}
+
+func parseScheduleTimes(value string) (times AccessControlScheduleTimes, err error) {
+	start, end, ok := strings.Cut(value, "-")
+	if !ok {
+		return times, fmt.Errorf("time range '%s' is not in the format HH:MM-HH:MM", value)
+	}
+
+	if times.Start, err = parseScheduleTime(start); err != nil {
+		return times, err
+	}
+
+	if times.End, err = parseScheduleTime(end); err != nil {
+		return times, err
+	}
+
+	if times.Start == times.End {
+		return times, fmt.Errorf("time range '%s' is invalid: the start and end must not be the same", value)
+	}
+
+	return times, nil
+}
+
+func parseScheduleTime(value string) (offset time.Duration, err error) {
+	value = strings.TrimSpace(value)
+
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+
+	var t time.Time
+
+	if t, err = time.Parse(layoutScheduleTime, value); err != nil {
+		return 0, fmt.Errorf("time '%s' is not in the format HH:MM", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func parseScheduleDates(value string) (dates AccessControlScheduleDates, err error) {
+	start, end, ok := strings.Cut(value, "/")
+
+	if dates.Start, err = parseScheduleDate(start); err != nil {
+		return dates, err
+	}
+
+	if !ok {
+		dates.End = dates.Start
+
+		return dates, nil
+	}
+
+	if dates.End, err = parseScheduleDate(end); err != nil {
+		return dates, err
+	}
+
+	if dates.End < dates.Start {
+		return dates, fmt.Errorf("date range '%s' is invalid: the end must not be before the start", value)
+	}
+
+	return dates, nil
+}
+
+func parseScheduleDate(value string) (date string, err error) {
+	value = strings.TrimSpace(value)
+
+	if _, err = time.Parse(layoutScheduleDate, value); err != nil {
+		return "", fmt.Errorf("date '%s' is not in the format YYYY-MM-DD", value)
+	}
+
+	return value, nil
+}
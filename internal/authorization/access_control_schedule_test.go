@@ -0,0 +1,92 @@
+package authorization
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestNewAccessControlScheduleShouldReturnNilWithoutCriteria(t *testing.T) {
+	schedule, err := NewAccessControlSchedule(schema.ACLSchedule{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, schedule)
+}
+
+func TestNewAccessControlScheduleShouldErrorOnTimezoneWithoutCriteria(t *testing.T) {
+	schedule, err := NewAccessControlSchedule(schema.ACLSchedule{Timezone: "UTC"})
+
+	assert.EqualError(t, err, "timezone 'UTC' is configured without any days, times, or dates")
+	assert.Nil(t, schedule)
+
+	schedule, err = NewAccessControlSchedule(schema.ACLSchedule{Timezone: "Not/AZone"})
+
+	assert.EqualError(t, err, "timezone 'Not/AZone' is invalid: unknown time zone Not/AZone")
+	assert.Nil(t, schedule)
+}
+
+func TestAccessControlScheduleIsMatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.ACLSchedule
+		time     string
+		expected bool
+	}{
+		{"ShouldMatchBusinessHours", schema.ACLSchedule{Days: []string{"mon-fri"}, Times: []string{"09:00-17:00"}}, "2023-02-08T10:30:00Z", true},
+		{"ShouldNotMatchBusinessHoursWeekend", schema.ACLSchedule{Days: []string{"mon-fri"}, Times: []string{"09:00-17:00"}}, "2023-02-11T10:30:00Z", false},
+		{"ShouldNotMatchBusinessHoursEndExclusive", schema.ACLSchedule{Days: []string{"mon-fri"}, Times: []string{"09:00-17:00"}}, "2023-02-08T17:00:00Z", false},
+		{"ShouldMatchBusinessHoursStartInclusive", schema.ACLSchedule{Days: []string{"mon-fri"}, Times: []string{"09:00-17:00"}}, "2023-02-08T09:00:00Z", true},
+		{"ShouldMatchDaysWrapAround", schema.ACLSchedule{Days: []string{"Saturday-Monday"}}, "2023-02-12T10:30:00Z", true},
+		{"ShouldNotMatchDaysWrapAround", schema.ACLSchedule{Days: []string{"Saturday-Monday"}}, "2023-02-08T10:30:00Z", false},
+		{"ShouldMatchTimesAcrossMidnightBefore", schema.ACLSchedule{Times: []string{"22:00-02:00"}}, "2023-02-08T23:30:00Z", true},
+		{"ShouldMatchTimesAcrossMidnightAfter", schema.ACLSchedule{Times: []string{"22:00-02:00"}}, "2023-02-08T01:30:00Z", true},
+		{"ShouldNotMatchTimesAcrossMidnight", schema.ACLSchedule{Times: []string{"22:00-02:00"}}, "2023-02-08T12:00:00Z", false},
+		{"ShouldMatchTimesUntilEndOfDay", schema.ACLSchedule{Times: []string{"20:00-24:00"}}, "2023-02-08T23:59:59Z", true},
+		{"ShouldMatchAnyTimes", schema.ACLSchedule{Times: []string{"01:00-02:00", "12:00-13:00"}}, "2023-02-08T12:30:00Z", true},
+		{"ShouldMatchTimezone", schema.ACLSchedule{Timezone: "Australia/Sydney", Times: []string{"09:00-17:00"}}, "2023-02-08T00:30:00Z", true},
+		{"ShouldNotMatchTimezone", schema.ACLSchedule{Timezone: "Australia/Sydney", Times: []string{"09:00-17:00"}}, "2023-02-08T10:30:00Z", false},
+		{"ShouldMatchDate", schema.ACLSchedule{Dates: []string{"2023-02-08"}}, "2023-02-08T10:30:00Z", true},
+		{"ShouldNotMatchDate", schema.ACLSchedule{Dates: []string{"2023-02-08"}}, "2023-02-09T10:30:00Z", false},
+		{"ShouldMatchDateRangeInclusive", schema.ACLSchedule{Dates: []string{"2023-02-01/2023-02-08"}}, "2023-02-08T23:59:59Z", true},
+		{"ShouldNotMatchDateRange", schema.ACLSchedule{Dates: []string{"2023-02-01/2023-02-08"}}, "2023-02-09T00:00:00Z", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := NewAccessControlSchedule(tc.have)
+
+			require.NoError(t, err)
+			require.NotNil(t, schedule)
+
+			now, err := time.Parse(time.RFC3339, tc.time)
+
+			require.NoError(t, err)
+
+			object := NewObject(&url.URL{Scheme: "https", Host: "example.com", Path: "/"}, "GET")
+			object.Time = now
+
+			assert.Equal(t, tc.expected, schedule.IsMatch(object))
+		})
+	}
+}
+
+func TestAccessControlRuleShouldMatchSchedule(t *testing.T) {
+	rule := NewAccessControlRule(1, schema.ACLRule{
+		Domains:  []string{"admin.example.com"},
+		Policy:   oneFactor,
+		Schedule: schema.ACLSchedule{Timezone: "UTC", Days: []string{"mon-fri"}, Times: []string{"09:00-17:00"}},
+	}, nil, nil, nil, nil)
+
+	object := NewObject(&url.URL{Scheme: "https", Host: "admin.example.com", Path: "/"}, "GET")
+
+	object.Time = time.Date(2023, time.February, 8, 10, 0, 0, 0, time.UTC)
+	assert.True(t, rule.IsMatch(Subject{}, object))
+
+	object.Time = time.Date(2023, time.February, 8, 18, 0, 0, 0, time.UTC)
+	assert.False(t, rule.IsMatch(Subject{}, object))
+}
@@ -1,6 +1,8 @@
 package authorization
 
 import (
+	"regexp"
+
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -48,3 +50,58 @@ type AccessControlGroup struct {
 func (acg AccessControlGroup) IsMatch(subject Subject) (match bool) {
 	return utils.IsStringInSlice(acg.Name, subject.Groups)
 }
+
+// AccessControlUserPattern represents an ACL subject of type `user-regex:` or `user-glob:`.
+type AccessControlUserPattern struct {
+	Pattern *regexp.Regexp
+}
+
+// IsMatch returns true if the AccessControlUserPattern pattern matches the Subject username.
+func (acu AccessControlUserPattern) IsMatch(subject Subject) (match bool) {
+	return subject.Username != "" && acu.Pattern.MatchString(subject.Username)
+}
+
+// AccessControlGroupPattern represents an ACL subject of type `group-regex:` or `group-glob:`.
+type AccessControlGroupPattern struct {
+	Pattern *regexp.Regexp
+}
+
+// IsMatch returns true if the AccessControlGroupPattern pattern matches one of the groups of the Subject.
+func (acg AccessControlGroupPattern) IsMatch(subject Subject) (match bool) {
+	for _, group := range subject.Groups {
+		if acg.Pattern.MatchString(group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AccessControlEmail represents an ACL subject of type `email:`, `email-regex:`, or `email-glob:`. The pattern is not
+// case-sensitive.
+type AccessControlEmail struct {
+	Pattern *regexp.Regexp
+}
+
+// IsMatch returns true if the AccessControlEmail pattern matches one of the emails of the Subject.
+func (ace AccessControlEmail) IsMatch(subject Subject) (match bool) {
+	for _, email := range subject.Emails {
+		if ace.Pattern.MatchString(email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AccessControlAttribute represents an ACL subject of type `attribute:`.
+type AccessControlAttribute struct {
+	Name  string
+	Value string
+}
+
+// IsMatch returns true if one of the values of the Subject attribute with the AccessControlAttribute name matches the
+// AccessControlAttribute value.
+func (aca AccessControlAttribute) IsMatch(subject Subject) (match bool) {
+	return utils.IsStringInSlice(aca.Value, subject.Attributes[aca.Name])
+}
@@ -1,72 +1,177 @@
 package authorization
 
 import (
+	"sync"
+	"time"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/geoip"
 	"github.com/authelia/authelia/v4/internal/logging"
 )
 
 // Authorizer the component in charge of checking whether a user can access a given resource.
 type Authorizer struct {
 	defaultPolicy Level
-	rules         []*AccessControlRule
+	index         *accessControlIndex
 	mfa           bool
+	headers       bool
 	config        *schema.Configuration
 	log           *logrus.Logger
+	metrics       MetricsRecorder
+	geoip         *geoip.Provider
+
+	mu sync.RWMutex
 }
 
 // NewAuthorizer create an instance of authorizer with a given access control config.
 func NewAuthorizer(config *schema.Configuration) (authorizer *Authorizer) {
+	var provider *geoip.Provider
+
+	if config.GeoIP.CountryDatabase != "" || config.GeoIP.ASNDatabase != "" {
+		provider = geoip.NewProvider(&config.GeoIP)
+	}
+
+	rules := NewAccessControlRules(config.AccessControl, provider)
+
 	authorizer = &Authorizer{
 		defaultPolicy: NewLevel(config.AccessControl.DefaultPolicy),
-		rules:         NewAccessControlRules(config.AccessControl),
+		index:         newAccessControlIndex(rules),
 		config:        config,
 		log:           logging.Logger(),
+		geoip:         provider,
 	}
 
-	if authorizer.defaultPolicy == TwoFactor {
-		authorizer.mfa = true
+	authorizer.mfa = authorizer.isSecondFactorEnabled(authorizer.defaultPolicy, rules)
+	authorizer.headers = isHeadersRequired(rules)
+
+	return authorizer
+}
+
+// GeoIP returns the geoip.Provider used to match the country and autonomous system networks, it's nil if no GeoIP
+// databases are configured.
+func (p *Authorizer) GeoIP() *geoip.Provider {
+	return p.geoip
+}
+
+// SetMetricsRecorder sets the metrics recorder used to record the rule evaluation metrics.
+func (p *Authorizer) SetMetricsRecorder(metrics MetricsRecorder) {
+	p.metrics = metrics
+}
+
+// Update atomically replaces the default policy and rules with the ones from the provided access control configuration.
+// The configuration must be validated before it's provided to this function.
+func (p *Authorizer) Update(config schema.AccessControlConfiguration) {
+	defaultPolicy, rules := NewLevel(config.DefaultPolicy), NewAccessControlRules(config, p.geoip)
+
+	mfa, headers, index := p.isSecondFactorEnabled(defaultPolicy, rules), isHeadersRequired(rules), newAccessControlIndex(rules)
+
+	p.mu.Lock()
+
+	p.defaultPolicy, p.index, p.mfa, p.headers = defaultPolicy, index, mfa, headers
+
+	p.mu.Unlock()
+}
 
-		return authorizer
+func (p *Authorizer) isSecondFactorEnabled(defaultPolicy Level, rules []*AccessControlRule) bool {
+	if defaultPolicy == TwoFactor {
+		return true
 	}
 
-	for _, rule := range authorizer.rules {
+	for _, rule := range rules {
 		if rule.Policy == TwoFactor {
-			authorizer.mfa = true
-
-			return authorizer
+			return true
 		}
 	}
 
-	if authorizer.config.IdentityProviders.OIDC != nil {
-		for _, client := range authorizer.config.IdentityProviders.OIDC.Clients {
+	if p.config.IdentityProviders.OIDC != nil {
+		for _, client := range p.config.IdentityProviders.OIDC.Clients {
 			if client.Policy == twoFactor {
-				authorizer.mfa = true
-
-				return authorizer
+				return true
 			}
 		}
 	}
 
-	return authorizer
+	return false
+}
+
+func isHeadersRequired(rules []*AccessControlRule) bool {
+	for _, rule := range rules {
+		if len(rule.Headers) != 0 || (rule.Condition != nil && rule.Condition.HasHeaders) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Authorizer) current() (defaultPolicy Level, index *accessControlIndex) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.defaultPolicy, p.index
+}
+
+// GetDefaultPolicy returns the policy applied when no rule matches a request.
+func (p *Authorizer) GetDefaultPolicy() Level {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.defaultPolicy
 }
 
 // IsSecondFactorEnabled return true if at least one policy is set to second factor.
-func (p Authorizer) IsSecondFactorEnabled() bool {
+func (p *Authorizer) IsSecondFactorEnabled() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	return p.mfa
 }
 
+// IsHeadersRequired returns true if at least one rule matches the request headers, in which case the request headers
+// must be provided with the object.
+func (p *Authorizer) IsHeadersRequired() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.headers
+}
+
 // GetRequiredLevel retrieve the required level of authorization to access the object.
-func (p Authorizer) GetRequiredLevel(subject Subject, object Object) (hasSubjects bool, level Level) {
+func (p *Authorizer) GetRequiredLevel(subject Subject, object Object) (hasSubjects bool, level Level) {
+	requirement := p.GetRequirement(subject, object)
+
+	return requirement.HasSubjects, requirement.Level
+}
+
+// GetRequirement retrieve the requirements which must be satisfied to access the object.
+func (p *Authorizer) GetRequirement(subject Subject, object Object) (requirement Requirement) {
 	p.log.Debugf("Check authorization of subject %s and object %s (method %s).",
 		subject.String(), object.String(), object.Method)
 
-	for _, rule := range p.rules {
+	if p.metrics != nil {
+		defer func(start time.Time) {
+			p.metrics.RecordAccessControlEvaluation(requirement.Matched, time.Since(start))
+		}(time.Now())
+	}
+
+	defaultPolicy, index := p.current()
+
+	for _, rule := range index.candidates(object.Domain) {
 		if rule.IsMatch(subject, object) {
 			p.log.Tracef(traceFmtACLHitMiss, "HIT", rule.Position, subject, object, object.Method)
 
-			return rule.HasSubjects, rule.Policy
+			return Requirement{
+				Matched:             true,
+				Position:            rule.Position,
+				HasSubjects:         rule.HasSubjects,
+				Level:               rule.Policy,
+				SecondFactorMethods: rule.SecondFactorMethods,
+
+				MaxAuthenticationAgeFirstFactor:  rule.MaxAuthenticationAgeFirstFactor,
+				MaxAuthenticationAgeSecondFactor: rule.MaxAuthenticationAgeSecondFactor,
+			}
 		}
 
 		p.log.Tracef(traceFmtACLHitMiss, "MISS", rule.Position, subject, object, object.Method)
@@ -74,16 +179,18 @@ func (p Authorizer) GetRequiredLevel(subject Subject, object Object) (hasSubject
 
 	p.log.Debugf("No matching rule for subject %s and url %s (method %s) applying default policy", subject, object, object.Method)
 
-	return false, p.defaultPolicy
+	return Requirement{Level: defaultPolicy}
 }
 
 // GetRuleMatchResults iterates through the rules and produces a list of RuleMatchResult provided a subject and object.
-func (p Authorizer) GetRuleMatchResults(subject Subject, object Object) (results []RuleMatchResult) {
+func (p *Authorizer) GetRuleMatchResults(subject Subject, object Object) (results []RuleMatchResult) {
 	skipped := false
 
-	results = make([]RuleMatchResult, len(p.rules))
+	_, index := p.current()
 
-	for i, rule := range p.rules {
+	results = make([]RuleMatchResult, len(index.rules))
+
+	for i, rule := range index.rules {
 		results[i] = RuleMatchResult{
 			Rule:    rule,
 			Skipped: skipped,
@@ -91,10 +198,15 @@ func (p Authorizer) GetRuleMatchResults(subject Subject, object Object) (results
 			MatchDomain:        rule.MatchesDomains(subject, object),
 			MatchResources:     rule.MatchesResources(subject, object),
 			MatchQuery:         rule.MatchesQuery(object),
+			MatchHeaders:       rule.MatchesHeaders(object),
 			MatchMethods:       rule.MatchesMethods(object),
 			MatchNetworks:      rule.MatchesNetworks(subject),
+			MatchSchedule:      rule.MatchesSchedule(object),
 			MatchSubjects:      rule.MatchesSubjects(subject),
 			MatchSubjectsExact: rule.MatchesSubjectExact(subject),
+
+			MatchCondition:      rule.MatchesCondition(subject, object),
+			MatchConditionExact: rule.MatchesConditionExact(subject, object),
 		}
 
 		skipped = skipped || results[i].IsMatch()
@@ -2,6 +2,7 @@ package authorization
 
 import (
 	"net"
+	"net/http"
 	"net/url"
 	"regexp"
 	"testing"
@@ -331,6 +332,140 @@ func (s *AuthorizerSuite) TestShouldCheckQueryPolicy() {
 	}
 }
 
+func (s *AuthorizerSuite) TestShouldCheckHeadersPolicy() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(oneFactor).
+		WithRule(schema.ACLRule{
+			Domains: []string{"api.example.com"},
+			Headers: [][]schema.ACLQueryRule{
+				{
+					{
+						Operator: operatorEqual,
+						Key:      "X-Api-Client",
+						Value:    "backup",
+					},
+					{
+						Operator: operatorPattern,
+						Key:      "User-Agent",
+						Value:    regexp.MustCompile(`^restic/`),
+					},
+				},
+			},
+			Policy: bypass,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"api.example.com"},
+			Headers: [][]schema.ACLQueryRule{
+				{
+					{
+						Operator: operatorPresent,
+						Key:      "X-Api-Client",
+					},
+				},
+			},
+			Policy: deny,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"files.example.com"},
+			Methods: []string{"POST"},
+			Headers: [][]schema.ACLQueryRule{
+				{
+					{
+						Operator: operatorPattern,
+						Key:      "Content-Type",
+						Value:    regexp.MustCompile(`^multipart/form-data`),
+					},
+				},
+			},
+			Policy: twoFactor,
+		}).
+		Build()
+
+	testCases := []struct {
+		name, requestURL, method string
+		header                   http.Header
+		expected                 Level
+	}{
+		{"ShouldBypassMatchingClient", "https://api.example.com/", "GET", http.Header{"X-Api-Client": []string{"backup"}, "User-Agent": []string{"restic/0.15.1"}}, Bypass},
+		{"ShouldDenyClientWithWrongAgent", "https://api.example.com/", "GET", http.Header{"X-Api-Client": []string{"backup"}, "User-Agent": []string{"curl/7.88.1"}}, Denied},
+		{"ShouldDenyUnknownClient", "https://api.example.com/", "GET", http.Header{"X-Api-Client": []string{"other"}}, Denied},
+		{"ShouldApplyDefaultWithoutHeaders", "https://api.example.com/", "GET", nil, OneFactor},
+		{"ShouldRequire2FAForUploads", "https://files.example.com/", "POST", http.Header{"Content-Type": []string{"multipart/form-data; boundary=abc"}}, TwoFactor},
+		{"ShouldApplyDefaultForOtherContentType", "https://files.example.com/", "POST", http.Header{"Content-Type": []string{"application/json"}}, OneFactor},
+		{"ShouldApplyDefaultForOtherMethod", "https://files.example.com/", "GET", http.Header{"Content-Type": []string{"multipart/form-data"}}, OneFactor},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			targetURL, _ := url.ParseRequestURI(tc.requestURL)
+
+			object := NewObject(targetURL, tc.method)
+			object.Header = tc.header
+
+			_, level := tester.GetRequiredLevel(UserWithGroups, object)
+
+			assert.Equal(t, tc.expected, level)
+		})
+	}
+}
+
+func (s *AuthorizerSuite) TestShouldCheckPatternSubjects() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains:  []string{"admin.example.com"},
+			Policy:   twoFactor,
+			Subjects: [][]string{{"group-regex:^team-.*-admins$"}, {"group:^literal$"}, {"group-regex:ops"}},
+		}).
+		WithRule(schema.ACLRule{
+			Domains:  []string{"api.example.com"},
+			Policy:   oneFactor,
+			Subjects: [][]string{{"user-regex:svc-.*"}, {"user-regex:^bot-.$"}, {"user:ops-*"}, {"user-glob:run-*"}, {"user-glob:job-?"}},
+		}).
+		WithRule(schema.ACLRule{
+			Domains:  []string{"contractors.example.com"},
+			Policy:   oneFactor,
+			Subjects: [][]string{{"email-regex:.*@contractor\\.example"}, {"email:Bob@Example.com"}, {"email:*@example.net"}, {"email-glob:*@Partner.example"}},
+		}).
+		Build()
+
+	testCases := []struct {
+		name, requestURL string
+		subject          Subject
+		expected         Level
+	}{
+		{"ShouldMatchGroupRegex", "https://admin.example.com/", Subject{Username: "john", Groups: []string{"dev", "team-web-admins"}}, TwoFactor},
+		{"ShouldNotMatchGroupRegex", "https://admin.example.com/", Subject{Username: "john", Groups: []string{"team-web-admins-old"}}, Denied},
+		{"ShouldMatchGroupRegexEntireValue", "https://admin.example.com/", Subject{Username: "john", Groups: []string{"ops"}}, TwoFactor},
+		{"ShouldNotMatchGroupRegexPartialValue", "https://admin.example.com/", Subject{Username: "john", Groups: []string{"not-ops", "ops-old"}}, Denied},
+		{"ShouldMatchGroupLiteral", "https://admin.example.com/", Subject{Username: "john", Groups: []string{"^literal$"}}, TwoFactor},
+		{"ShouldNotMatchGroupLiteralAsRegex", "https://admin.example.com/", Subject{Username: "john", Groups: []string{"literal"}}, Denied},
+		{"ShouldMatchUserRegex", "https://api.example.com/", Subject{Username: "svc-backup"}, OneFactor},
+		{"ShouldMatchUserRegexSingleCharacter", "https://api.example.com/", Subject{Username: "bot-1"}, OneFactor},
+		{"ShouldNotMatchUserRegexSingleCharacter", "https://api.example.com/", Subject{Username: "bot-12"}, Denied},
+		{"ShouldNotMatchUserRegex", "https://api.example.com/", Subject{Username: "john-svc-backup"}, Denied},
+		{"ShouldMatchUserLiteral", "https://api.example.com/", Subject{Username: "ops-*"}, OneFactor},
+		{"ShouldNotMatchUserLiteralAsGlob", "https://api.example.com/", Subject{Username: "ops-backup"}, Denied},
+		{"ShouldMatchUserGlob", "https://api.example.com/", Subject{Username: "run-backup"}, OneFactor},
+		{"ShouldNotMatchUserGlob", "https://api.example.com/", Subject{Username: "john-run-backup"}, Denied},
+		{"ShouldMatchUserGlobSingleCharacter", "https://api.example.com/", Subject{Username: "job-1"}, OneFactor},
+		{"ShouldNotMatchUserGlobSingleCharacter", "https://api.example.com/", Subject{Username: "job-12"}, Denied},
+		{"ShouldMatchEmailGlob", "https://contractors.example.com/", Subject{Username: "fred", Emails: []string{"fred@partner.example"}}, OneFactor},
+		{"ShouldNotMatchEmailGlobSubdomain", "https://contractors.example.com/", Subject{Username: "fred", Emails: []string{"fred@sub.partner.example"}}, Denied},
+		{"ShouldMatchEmailDomain", "https://contractors.example.com/", Subject{Username: "fred", Emails: []string{"fred@example.com", "Fred@Contractor.Example"}}, OneFactor},
+		{"ShouldMatchEmailExact", "https://contractors.example.com/", Subject{Username: "bob", Emails: []string{"bob@example.com"}}, OneFactor},
+		{"ShouldNotMatchEmailSubdomain", "https://contractors.example.com/", Subject{Username: "fred", Emails: []string{"fred@sub.contractor.example.com"}}, Denied},
+		{"ShouldNotMatchWithoutEmails", "https://contractors.example.com/", Subject{Username: "fred"}, Denied},
+		{"ShouldNotMatchEmailLiteralAsGlob", "https://contractors.example.com/", Subject{Username: "fred", Emails: []string{"fred@example.net"}}, Denied},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tester.CheckAuthorizations(t, tc.subject, tc.requestURL, "GET", tc.expected)
+		})
+	}
+}
+
 func (s *AuthorizerSuite) TestShouldCheckRulePrecedence() {
 	tester := NewAuthorizerBuilder().
 		WithDefaultPolicy(deny).
@@ -397,57 +532,57 @@ func (s *AuthorizerSuite) TestShouldCheckDomainMatching() {
 	tester.CheckAuthorizations(s.T(), Bob, "https://x.example.com", "GET", TwoFactor)
 	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://x.example.com", "GET", OneFactor)
 
-	s.Require().Len(tester.rules, 5)
+	s.Require().Len(tester.index.rules, 5)
 
-	s.Require().Len(tester.rules[0].Domains, 1)
+	s.Require().Len(tester.index.rules[0].Domains, 1)
 
 	s.Assert().Equal("public.example.com", tester.config.AccessControl.Rules[0].Domains[0])
 
-	ruleMatcher0, ok := tester.rules[0].Domains[0].Matcher.(*AccessControlDomainMatcher)
+	ruleMatcher0, ok := tester.index.rules[0].Domains[0].Matcher.(*AccessControlDomainMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("public.example.com", ruleMatcher0.Name)
 	s.Assert().False(ruleMatcher0.Wildcard)
 	s.Assert().False(ruleMatcher0.UserWildcard)
 	s.Assert().False(ruleMatcher0.GroupWildcard)
 
-	s.Require().Len(tester.rules[1].Domains, 1)
+	s.Require().Len(tester.index.rules[1].Domains, 1)
 
 	s.Assert().Equal("one-factor.example.com", tester.config.AccessControl.Rules[1].Domains[0])
 
-	ruleMatcher1, ok := tester.rules[1].Domains[0].Matcher.(*AccessControlDomainMatcher)
+	ruleMatcher1, ok := tester.index.rules[1].Domains[0].Matcher.(*AccessControlDomainMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("one-factor.example.com", ruleMatcher1.Name)
 	s.Assert().False(ruleMatcher1.Wildcard)
 	s.Assert().False(ruleMatcher1.UserWildcard)
 	s.Assert().False(ruleMatcher1.GroupWildcard)
 
-	s.Require().Len(tester.rules[2].Domains, 1)
+	s.Require().Len(tester.index.rules[2].Domains, 1)
 
 	s.Assert().Equal("two-factor.example.com", tester.config.AccessControl.Rules[2].Domains[0])
 
-	ruleMatcher2, ok := tester.rules[2].Domains[0].Matcher.(*AccessControlDomainMatcher)
+	ruleMatcher2, ok := tester.index.rules[2].Domains[0].Matcher.(*AccessControlDomainMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("two-factor.example.com", ruleMatcher2.Name)
 	s.Assert().False(ruleMatcher2.Wildcard)
 	s.Assert().False(ruleMatcher2.UserWildcard)
 	s.Assert().False(ruleMatcher2.GroupWildcard)
 
-	s.Require().Len(tester.rules[3].Domains, 1)
+	s.Require().Len(tester.index.rules[3].Domains, 1)
 
 	s.Assert().Equal("*.example.com", tester.config.AccessControl.Rules[3].Domains[0])
 
-	ruleMatcher3, ok := tester.rules[3].Domains[0].Matcher.(*AccessControlDomainMatcher)
+	ruleMatcher3, ok := tester.index.rules[3].Domains[0].Matcher.(*AccessControlDomainMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal(".example.com", ruleMatcher3.Name)
 	s.Assert().True(ruleMatcher3.Wildcard)
 	s.Assert().False(ruleMatcher3.UserWildcard)
 	s.Assert().False(ruleMatcher3.GroupWildcard)
 
-	s.Require().Len(tester.rules[4].Domains, 1)
+	s.Require().Len(tester.index.rules[4].Domains, 1)
 
 	s.Assert().Equal("*.example.com", tester.config.AccessControl.Rules[4].Domains[0])
 
-	ruleMatcher4, ok := tester.rules[4].Domains[0].Matcher.(*AccessControlDomainMatcher)
+	ruleMatcher4, ok := tester.index.rules[4].Domains[0].Matcher.(*AccessControlDomainMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal(".example.com", ruleMatcher4.Name)
 	s.Assert().True(ruleMatcher4.Wildcard)
@@ -494,45 +629,45 @@ func (s *AuthorizerSuite) TestShouldCheckDomainRegexMatching() {
 	tester.CheckAuthorizations(s.T(), John, "https://group-dev.regex.com", "GET", TwoFactor)
 	tester.CheckAuthorizations(s.T(), Bob, "https://group-dev.regex.com", "GET", Denied)
 
-	s.Require().Len(tester.rules, 5)
+	s.Require().Len(tester.index.rules, 5)
 
-	s.Require().Len(tester.rules[0].Domains, 1)
+	s.Require().Len(tester.index.rules[0].Domains, 1)
 
 	s.Assert().Equal("^.*\\.example.com$", tester.config.AccessControl.Rules[0].DomainsRegex[0].String())
 
-	ruleMatcher0, ok := tester.rules[0].Domains[0].Matcher.(RegexpStringSubjectMatcher)
+	ruleMatcher0, ok := tester.index.rules[0].Domains[0].Matcher.(RegexpStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^.*\\.example.com$", ruleMatcher0.String())
 
-	s.Require().Len(tester.rules[1].Domains, 1)
+	s.Require().Len(tester.index.rules[1].Domains, 1)
 
 	s.Assert().Equal("^.*\\.example2.com$", tester.config.AccessControl.Rules[1].DomainsRegex[0].String())
 
-	ruleMatcher1, ok := tester.rules[1].Domains[0].Matcher.(RegexpStringSubjectMatcher)
+	ruleMatcher1, ok := tester.index.rules[1].Domains[0].Matcher.(RegexpStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^.*\\.example2.com$", ruleMatcher1.String())
 
-	s.Require().Len(tester.rules[2].Domains, 1)
+	s.Require().Len(tester.index.rules[2].Domains, 1)
 
 	s.Assert().Equal("^(?P<User>[a-zA-Z0-9]+)\\.regex.com$", tester.config.AccessControl.Rules[2].DomainsRegex[0].String())
 
-	ruleMatcher2, ok := tester.rules[2].Domains[0].Matcher.(RegexpGroupStringSubjectMatcher)
+	ruleMatcher2, ok := tester.index.rules[2].Domains[0].Matcher.(RegexpGroupStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^(?P<User>[a-zA-Z0-9]+)\\.regex.com$", ruleMatcher2.String())
 
-	s.Require().Len(tester.rules[3].Domains, 1)
+	s.Require().Len(tester.index.rules[3].Domains, 1)
 
 	s.Assert().Equal("^group-(?P<Group>[a-zA-Z0-9]+)\\.regex.com$", tester.config.AccessControl.Rules[3].DomainsRegex[0].String())
 
-	ruleMatcher3, ok := tester.rules[3].Domains[0].Matcher.(RegexpGroupStringSubjectMatcher)
+	ruleMatcher3, ok := tester.index.rules[3].Domains[0].Matcher.(RegexpGroupStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^group-(?P<Group>[a-zA-Z0-9]+)\\.regex.com$", ruleMatcher3.String())
 
-	s.Require().Len(tester.rules[4].Domains, 1)
+	s.Require().Len(tester.index.rules[4].Domains, 1)
 
 	s.Assert().Equal("^.*\\.(one|two).com$", tester.config.AccessControl.Rules[4].DomainsRegex[0].String())
 
-	ruleMatcher4, ok := tester.rules[4].Domains[0].Matcher.(RegexpStringSubjectMatcher)
+	ruleMatcher4, ok := tester.index.rules[4].Domains[0].Matcher.(RegexpStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^.*\\.(one|two).com$", ruleMatcher4.String())
 }
@@ -602,25 +737,25 @@ func (s *AuthorizerSuite) TestShouldCheckResourceSubjectMatching() {
 	tester.CheckAuthorizations(s.T(), Bob, "https://id.example.com/invalidgroup/group", "GET", Denied)
 	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://id.example.com/invalidgroup/group", "GET", OneFactor)
 
-	s.Require().Len(tester.rules, 3)
+	s.Require().Len(tester.index.rules, 3)
 
-	s.Require().Len(tester.rules[0].Resources, 2)
+	s.Require().Len(tester.index.rules[0].Resources, 2)
 
-	ruleMatcher00, ok := tester.rules[0].Resources[0].Matcher.(RegexpGroupStringSubjectMatcher)
+	ruleMatcher00, ok := tester.index.rules[0].Resources[0].Matcher.(RegexpGroupStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^/(?P<User>[a-zA-Z0-9]+)/personal(/|/.*)?$", ruleMatcher00.String())
 
-	ruleMatcher01, ok := tester.rules[0].Resources[1].Matcher.(RegexpGroupStringSubjectMatcher)
+	ruleMatcher01, ok := tester.index.rules[0].Resources[1].Matcher.(RegexpGroupStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^/(?P<Group>[a-zA-Z0-9]+)/group(/|/.*)?$", ruleMatcher01.String())
 
-	s.Require().Len(tester.rules[1].Resources, 2)
+	s.Require().Len(tester.index.rules[1].Resources, 2)
 
-	ruleMatcher10, ok := tester.rules[1].Resources[0].Matcher.(RegexpStringSubjectMatcher)
+	ruleMatcher10, ok := tester.index.rules[1].Resources[0].Matcher.(RegexpStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^/([a-zA-Z0-9]+)/personal(/|/.*)?$", ruleMatcher10.String())
 
-	ruleMatcher11, ok := tester.rules[1].Resources[1].Matcher.(RegexpStringSubjectMatcher)
+	ruleMatcher11, ok := tester.index.rules[1].Resources[1].Matcher.(RegexpStringSubjectMatcher)
 	s.Require().True(ok)
 	s.Assert().Equal("^/([a-zA-Z0-9]+)/group(/|/.*)?$", ruleMatcher11.String())
 }
@@ -653,6 +788,35 @@ func (s *AuthorizerSuite) TestShouldCheckGroupMatching() {
 	tester.CheckAuthorizations(s.T(), Bob, "https://protected.example.com/", "GET", Denied)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckAttributeMatching() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains:  []string{"protected.example.com"},
+			Policy:   oneFactor,
+			Subjects: [][]string{{"attribute:department=engineering", "group:dev"}, {"attribute: phone = 555-0100"}},
+		}).
+		Build()
+
+	engineer := Subject{
+		Username:   "john",
+		Groups:     []string{"dev"},
+		Attributes: map[string][]string{"department": {"sales", "engineering"}},
+		IP:         net.ParseIP("10.0.0.8"),
+	}
+
+	salesperson := Subject{
+		Username:   "bob",
+		Groups:     []string{"dev"},
+		Attributes: map[string][]string{"department": {"sales"}, "phone": {"555-0100"}},
+		IP:         net.ParseIP("10.0.0.7"),
+	}
+
+	tester.CheckAuthorizations(s.T(), engineer, "https://protected.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), salesperson, "https://protected.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://protected.example.com/", "GET", Denied)
+}
+
 func (s *AuthorizerSuite) TestShouldCheckSubjectsMatching() {
 	tester := NewAuthorizerBuilder().
 		WithDefaultPolicy(deny).
@@ -1025,13 +1189,13 @@ func TestNewAuthorizer(t *testing.T) {
 	authorizer := NewAuthorizer(config)
 
 	assert.Equal(t, Denied, authorizer.defaultPolicy)
-	assert.Equal(t, TwoFactor, authorizer.rules[0].Policy)
+	assert.Equal(t, TwoFactor, authorizer.index.rules[0].Policy)
 
-	user, ok := authorizer.rules[0].Subjects[0].Subjects[0].(AccessControlUser)
+	user, ok := authorizer.index.rules[0].Subjects[0].Subjects[0].(AccessControlUser)
 	require.True(t, ok)
 	assert.Equal(t, "admin", user.Name)
 
-	group, ok := authorizer.rules[0].Subjects[1].Subjects[0].(AccessControlGroup)
+	group, ok := authorizer.index.rules[0].Subjects[1].Subjects[0].(AccessControlGroup)
 	require.True(t, ok)
 	assert.Equal(t, "admins", group.Name)
 }
@@ -1057,6 +1221,36 @@ func TestAuthorizerIsSecondFactorEnabledRuleWithNoOIDC(t *testing.T) {
 	assert.True(t, authorizer.IsSecondFactorEnabled())
 }
 
+func TestAuthorizerIsHeadersRequired(t *testing.T) {
+	config := &schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: deny,
+			Rules: []schema.ACLRule{
+				{
+					Domains: []string{"example.com"},
+					Policy:  oneFactor,
+				},
+			},
+		},
+	}
+
+	authorizer := NewAuthorizer(config)
+	assert.False(t, authorizer.IsHeadersRequired())
+
+	config.AccessControl.Rules[0].Condition = "object.method == 'GET'"
+	authorizer.Update(config.AccessControl)
+	assert.False(t, authorizer.IsHeadersRequired())
+
+	config.AccessControl.Rules[0].Condition = "'backup' in object.headers['X-Api-Client']"
+	authorizer.Update(config.AccessControl)
+	assert.True(t, authorizer.IsHeadersRequired())
+
+	config.AccessControl.Rules[0].Condition = ""
+	config.AccessControl.Rules[0].Headers = [][]schema.ACLQueryRule{{{Operator: operatorPresent, Key: "X-Api-Client"}}}
+	authorizer = NewAuthorizer(config)
+	assert.True(t, authorizer.IsHeadersRequired())
+}
+
 func TestAuthorizerIsSecondFactorEnabledRuleWithOIDC(t *testing.T) {
 	config := &schema.Configuration{
 		AccessControl: schema.AccessControlConfiguration{
@@ -1103,3 +1297,67 @@ func TestAuthorizerIsSecondFactorEnabledRuleWithOIDC(t *testing.T) {
 	authorizer = NewAuthorizer(config)
 	assert.True(t, authorizer.IsSecondFactorEnabled())
 }
+
+func TestAuthorizerUpdate(t *testing.T) {
+	config := &schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: deny,
+			Rules: []schema.ACLRule{
+				{
+					Domains: []string{"example.com"},
+					Policy:  oneFactor,
+				},
+			},
+		},
+	}
+
+	authorizer := NewAuthorizer(config)
+
+	object := NewObject(&url.URL{Scheme: "https", Host: "example.com", Path: "/"}, "GET")
+
+	_, level := authorizer.GetRequiredLevel(Subject{}, object)
+	assert.Equal(t, OneFactor, level)
+	assert.False(t, authorizer.IsSecondFactorEnabled())
+
+	authorizer.Update(schema.AccessControlConfiguration{
+		DefaultPolicy: twoFactor,
+		Rules: []schema.ACLRule{
+			{
+				Domains: []string{"other.example.com"},
+				Policy:  bypass,
+			},
+		},
+	})
+
+	_, level = authorizer.GetRequiredLevel(Subject{}, object)
+	assert.Equal(t, TwoFactor, level)
+	assert.True(t, authorizer.IsSecondFactorEnabled())
+
+	results := authorizer.GetRuleMatchResults(Subject{}, object)
+
+	require.Len(t, results, 1)
+	assert.False(t, results[0].IsMatch())
+	assert.Equal(t, deny, config.AccessControl.DefaultPolicy)
+}
+
+func TestAuthorizerGetRequirement(t *testing.T) {
+	authorizer := NewAuthorizer(&schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: oneFactor,
+			Rules: []schema.ACLRule{
+				{
+					Domains:             []string{"console.example.com"},
+					Policy:              twoFactor,
+					SecondFactorMethods: []string{"webauthn"},
+					Subjects:            [][]string{{"group:admins"}},
+				},
+			},
+		},
+	})
+
+	object := NewObject(&url.URL{Scheme: "https", Host: "console.example.com", Path: "/"}, "GET")
+
+	assert.Equal(t, Requirement{Matched: true, Position: 1, HasSubjects: true, Level: TwoFactor, SecondFactorMethods: []string{"webauthn"}},
+		authorizer.GetRequirement(Subject{Username: "john", Groups: []string{"admins"}}, object))
+	assert.Equal(t, Requirement{Level: OneFactor}, authorizer.GetRequirement(Subject{Username: "bob"}, object))
+}
@@ -15,8 +15,21 @@ const (
 )
 
 const (
-	prefixUser  = "user:"
-	prefixGroup = "group:"
+	prefixUser       = "user:"
+	prefixUserRegex  = "user-regex:"
+	prefixUserGlob   = "user-glob:"
+	prefixGroup      = "group:"
+	prefixGroupRegex = "group-regex:"
+	prefixGroupGlob  = "group-glob:"
+	prefixEmail      = "email:"
+	prefixEmailRegex = "email-regex:"
+	prefixEmailGlob  = "email-glob:"
+	prefixAttribute  = "attribute:"
+)
+
+const (
+	prefixNetworkCountry = "country:"
+	prefixNetworkASN     = "asn:"
 )
 
 const (
@@ -35,6 +48,11 @@ const (
 	operatorNotPattern = "not pattern"
 )
 
+const (
+	layoutScheduleTime = "15:04"
+	layoutScheduleDate = "2006-01-02"
+)
+
 const (
 	subexpNameUser  = "User"
 	subexpNameGroup = "Group"
@@ -43,6 +61,9 @@ const (
 var (
 	// IdentitySubexpNames is a list of valid regex subexp names.
 	IdentitySubexpNames = []string{subexpNameUser, subexpNameGroup}
+
+	// SensitiveHeaders is a list of request headers which are never provided to the header rules.
+	SensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
 )
 
 const traceFmtACLHitMiss = "ACL %s Position %d for subject %s and object %s (method %s)"
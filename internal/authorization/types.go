@@ -3,12 +3,26 @@ package authorization
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/geoip"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
+// MetricsRecorder represents the methods used to record authorization metrics.
+type MetricsRecorder interface {
+	RecordAccessControlEvaluation(matched bool, elapsed time.Duration)
+}
+
+// GeoIPProvider represents the methods used to lookup the GeoIP information of an IP.
+type GeoIPProvider interface {
+	Lookup(ip net.IP) (record geoip.Record)
+}
+
 // SubjectMatcher is a matcher that takes a subject.
 type SubjectMatcher interface {
 	IsMatch(subject Subject) (match bool)
@@ -31,9 +45,17 @@ type ObjectMatcher interface {
 
 // Subject represents the identity of a user for the purposes of ACL matching.
 type Subject struct {
-	Username string
-	Groups   []string
-	IP       net.IP
+	Username   string
+	Groups     []string
+	Emails     []string
+	Attributes map[string][]string
+	IP         net.IP
+
+	// AuthenticationLevel is the level the subject is currently authenticated at.
+	AuthenticationLevel authentication.Level
+
+	// AMR is the RFC8176 authentication method references of the subject.
+	AMR []string
 }
 
 // String returns a string representation of the Subject.
@@ -53,6 +75,12 @@ type Object struct {
 	Domain string
 	Path   string
 	Method string
+
+	// Header is the request headers forwarded by the proxy.
+	Header http.Header
+
+	// Time is the time of the request used to match schedules, the current time is used if it's the zero value.
+	Time time.Time
 }
 
 // String is a string representation of the Object.
@@ -75,6 +103,33 @@ func NewObject(targetURL *url.URL, method string) (object Object) {
 	}
 }
 
+// Requirement describes the requirements which must be satisfied to access an object.
+type Requirement struct {
+	// Matched is true if a rule matched, otherwise the default policy was applied.
+	Matched bool
+
+	// Position is the position of the rule which matched, it's 0 if the default policy was applied.
+	Position int
+
+	// HasSubjects is true if the matched rule has subjects.
+	HasSubjects bool
+
+	// Level is the required level of authorization.
+	Level Level
+
+	// SecondFactorMethods are the second factor methods which satisfy the requirement, any method satisfies the
+	// requirement if it's empty.
+	SecondFactorMethods []string
+
+	// MaxAuthenticationAgeFirstFactor is the maximum age of the first factor authentication, the age isn't checked if
+	// it's 0.
+	MaxAuthenticationAgeFirstFactor time.Duration
+
+	// MaxAuthenticationAgeSecondFactor is the maximum age of the second factor authentication, the age isn't checked if
+	// it's 0.
+	MaxAuthenticationAgeSecondFactor time.Duration
+}
+
 // RuleMatchResult describes how well a rule matched a subject/object combo.
 type RuleMatchResult struct {
 	Rule *AccessControlRule
@@ -84,18 +139,24 @@ type RuleMatchResult struct {
 	MatchDomain        bool
 	MatchResources     bool
 	MatchQuery         bool
+	MatchHeaders       bool
 	MatchMethods       bool
 	MatchNetworks      bool
+	MatchSchedule      bool
 	MatchSubjects      bool
 	MatchSubjectsExact bool
+
+	MatchCondition      bool
+	MatchConditionExact bool
 }
 
 // IsMatch returns true if all the criteria matched.
 func (r RuleMatchResult) IsMatch() (match bool) {
-	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchSubjectsExact
+	return r.MatchDomain && r.MatchResources && r.MatchQuery && r.MatchHeaders && r.MatchMethods && r.MatchNetworks && r.MatchSchedule && r.MatchSubjectsExact && r.MatchConditionExact
 }
 
 // IsPotentialMatch returns true if the rule is potentially a match.
 func (r RuleMatchResult) IsPotentialMatch() (match bool) {
-	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchSubjects && !r.MatchSubjectsExact
+	return r.MatchDomain && r.MatchResources && r.MatchQuery && r.MatchHeaders && r.MatchMethods && r.MatchNetworks && r.MatchSchedule && r.MatchSubjects && r.MatchCondition &&
+		!(r.MatchSubjectsExact && r.MatchConditionExact)
 }
@@ -1,6 +1,7 @@
 package authorization
 
 import (
+	"fmt"
 	"net"
 	"regexp"
 	"strings"
@@ -55,19 +56,77 @@ func stringSliceToRegexpSlice(strings []string) (regexps []regexp.Regexp, err er
 }
 
 func schemaSubjectToACLSubject(subjectRule string) (subject SubjectMatcher) {
-	if strings.HasPrefix(subjectRule, prefixUser) {
-		user := strings.Trim(subjectRule[len(prefixUser):], " ")
+	switch {
+	case strings.HasPrefix(subjectRule, prefixUser):
+		return AccessControlUser{Name: strings.Trim(subjectRule[len(prefixUser):], " ")}
+	case strings.HasPrefix(subjectRule, prefixUserRegex):
+		if pattern, err := NewSubjectPattern(strings.Trim(subjectRule[len(prefixUserRegex):], " "), false); err == nil {
+			return AccessControlUserPattern{Pattern: pattern}
+		}
+	case strings.HasPrefix(subjectRule, prefixUserGlob):
+		return AccessControlUserPattern{Pattern: NewSubjectGlob(strings.Trim(subjectRule[len(prefixUserGlob):], " "), false)}
+	case strings.HasPrefix(subjectRule, prefixGroup):
+		return AccessControlGroup{Name: strings.Trim(subjectRule[len(prefixGroup):], " ")}
+	case strings.HasPrefix(subjectRule, prefixGroupRegex):
+		if pattern, err := NewSubjectPattern(strings.Trim(subjectRule[len(prefixGroupRegex):], " "), false); err == nil {
+			return AccessControlGroupPattern{Pattern: pattern}
+		}
+	case strings.HasPrefix(subjectRule, prefixGroupGlob):
+		return AccessControlGroupPattern{Pattern: NewSubjectGlob(strings.Trim(subjectRule[len(prefixGroupGlob):], " "), false)}
+	case strings.HasPrefix(subjectRule, prefixEmail):
+		email := strings.Trim(subjectRule[len(prefixEmail):], " ")
+
+		return AccessControlEmail{Pattern: regexp.MustCompile("(?i)^" + regexp.QuoteMeta(email) + "$")}
+	case strings.HasPrefix(subjectRule, prefixEmailRegex):
+		if pattern, err := NewSubjectPattern(strings.Trim(subjectRule[len(prefixEmailRegex):], " "), true); err == nil {
+			return AccessControlEmail{Pattern: pattern}
+		}
+	case strings.HasPrefix(subjectRule, prefixEmailGlob):
+		return AccessControlEmail{Pattern: NewSubjectGlob(strings.Trim(subjectRule[len(prefixEmailGlob):], " "), true)}
+	case strings.HasPrefix(subjectRule, prefixAttribute):
+		name, value, ok := strings.Cut(subjectRule[len(prefixAttribute):], "=")
+		if !ok {
+			return nil
+		}
 
-		return AccessControlUser{Name: user}
+		return AccessControlAttribute{Name: strings.Trim(name, " "), Value: strings.Trim(value, " ")}
 	}
 
-	if strings.HasPrefix(subjectRule, prefixGroup) {
-		group := strings.Trim(subjectRule[len(prefixGroup):], " ")
+	return nil
+}
 
-		return AccessControlGroup{Name: group}
+// NewSubjectPattern compiles the regular expression of a `user-regex:`, `group-regex:`, or `email-regex:` subject. The
+// regular expression is anchored so it must match the entire value. If fold is true the pattern is not case-sensitive.
+func NewSubjectPattern(value string, fold bool) (pattern *regexp.Regexp, err error) {
+	// The value is compiled on its own first so the error refers to the configured value.
+	if _, err = regexp.Compile(value); err != nil {
+		return nil, fmt.Errorf("pattern '%s' is invalid: %w", value, err)
 	}
 
-	return nil
+	expr := "^(?:" + value + ")$"
+
+	if fold {
+		expr = "(?i)" + expr
+	}
+
+	if pattern, err = regexp.Compile(expr); err != nil {
+		return nil, fmt.Errorf("pattern '%s' is invalid: %w", value, err)
+	}
+
+	return pattern, nil
+}
+
+// NewSubjectGlob compiles the glob pattern of a `user-glob:`, `group-glob:`, or `email-glob:` subject where * matches any
+// number of characters and ? matches exactly one character. The pattern must match the entire value. If fold is true
+// the pattern is not case-sensitive.
+func NewSubjectGlob(value string, fold bool) (pattern *regexp.Regexp) {
+	expr := "^" + strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(value)) + "$"
+
+	if fold {
+		expr = "(?i)" + expr
+	}
+
+	return regexp.MustCompile(expr)
 }
 
 func ruleAddDomain(domainRules []string, rule *AccessControlRule) {
@@ -138,6 +197,39 @@ func schemaNetworksToACL(networkRules []string, networksMap map[string][]*net.IP
 	return networks
 }
 
+func schemaGeoNetworksToACL(networkRules []string, geoNetworksMap map[string][]AccessControlGeoNetwork, provider GeoIPProvider) (networks []AccessControlGeoNetwork) {
+	for _, network := range networkRules {
+		if named, ok := geoNetworksMap[network]; ok {
+			networks = append(networks, named...)
+
+			continue
+		}
+
+		// The networks are validated by the configuration validator so an error is not expected here.
+		if geo, err := NewAccessControlGeoNetwork(network, provider); err == nil && geo != nil {
+			networks = append(networks, *geo)
+		}
+	}
+
+	return networks
+}
+
+func parseSchemaGeoNetworks(schemaNetworks []schema.ACLNetwork, provider GeoIPProvider) (geoNetworksMap map[string][]AccessControlGeoNetwork) {
+	geoNetworksMap = map[string][]AccessControlGeoNetwork{}
+
+	for _, aclNetwork := range schemaNetworks {
+		if _, ok := geoNetworksMap[aclNetwork.Name]; ok {
+			continue
+		}
+
+		if networks := schemaGeoNetworksToACL(aclNetwork.Networks, nil, provider); len(networks) != 0 {
+			geoNetworksMap[aclNetwork.Name] = networks
+		}
+	}
+
+	return geoNetworksMap
+}
+
 func parseSchemaNetworks(schemaNetworks []schema.ACLNetwork) (networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) {
 	// These maps store pointers to the net.IPNet values so we can reuse them efficiently.
 	// The networksMap contains the named networks as keys, the networksCacheMap contains the CIDR notations as keys.
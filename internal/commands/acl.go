@@ -4,11 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/valyala/fasthttp"
+	"gopkg.in/yaml.v3"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/validator"
 )
@@ -25,6 +31,7 @@ func newAccessControlCommand(ctx *CmdCtx) (cmd *cobra.Command) {
 
 	cmd.AddCommand(
 		newAccessControlCheckCommand(ctx),
+		newAccessControlTestCommand(ctx),
 	)
 
 	return cmd
@@ -41,14 +48,21 @@ func newAccessControlCheckCommand(ctx *CmdCtx) (cmd *cobra.Command) {
 		),
 		RunE: ctx.AccessControlCheckRunE,
 
+		SilenceUsage:      true,
 		DisableAutoGenTag: true,
 	}
 
 	cmd.Flags().String("url", "", "the url of the object")
 	cmd.Flags().String("method", "GET", "the HTTP method of the object")
+	cmd.Flags().StringArray("header", nil, "the headers of the request in the format name:value")
 	cmd.Flags().String("username", "", "the username of the subject")
 	cmd.Flags().StringSlice("groups", nil, "the groups of the subject")
+	cmd.Flags().StringSlice("emails", nil, "the emails of the subject")
+	cmd.Flags().StringSlice("attributes", nil, "the extra attributes of the subject in the format name=value")
 	cmd.Flags().String("ip", "", "the ip of the subject")
+	cmd.Flags().String("level", "", "the authentication level of the subject, one of 'not_authenticated', 'one_factor' or 'two_factor'")
+	cmd.Flags().StringSlice("amr", nil, "the RFC8176 authentication method references of the subject")
+	cmd.Flags().String("time", "", "the time of the request in RFC3339 format, defaults to the current time")
 	cmd.Flags().Bool("verbose", false, "enables verbose output")
 
 	return cmd
@@ -56,6 +70,7 @@ func newAccessControlCheckCommand(ctx *CmdCtx) (cmd *cobra.Command) {
 
 func (ctx *CmdCtx) AccessControlCheckRunE(cmd *cobra.Command, _ []string) (err error) {
 	validator.ValidateAccessControl(ctx.config, ctx.cconfig.validator)
+	validator.ValidateRules(ctx.config, ctx.cconfig.validator)
 
 	if ctx.cconfig.validator.HasErrors() || ctx.cconfig.validator.HasWarnings() {
 		return errors.New("your configuration has errors")
@@ -63,6 +78,12 @@ func (ctx *CmdCtx) AccessControlCheckRunE(cmd *cobra.Command, _ []string) (err e
 
 	authorizer := authorization.NewAuthorizer(ctx.config)
 
+	if provider := authorizer.GeoIP(); provider != nil {
+		if err = provider.StartupCheck(); err != nil {
+			return err
+		}
+	}
+
 	subject, object, err := getSubjectAndObjectFromFlags(cmd)
 	if err != nil {
 		return err
@@ -86,6 +107,223 @@ func (ctx *CmdCtx) AccessControlCheckRunE(cmd *cobra.Command, _ []string) (err e
 	return nil
 }
 
+func newAccessControlTestCommand(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "test <file>",
+		Short:   cmdAutheliaAccessControlTestShort,
+		Long:    cmdAutheliaAccessControlTestLong,
+		Example: cmdAutheliaAccessControlTestExample,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: ctx.ChainRunE(
+			ctx.ConfigLoadRunE,
+		),
+		RunE: ctx.AccessControlTestRunE,
+
+		SilenceUsage:      true,
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().Bool("verbose", false, "enables verbose output")
+
+	return cmd
+}
+
+// AccessControlTestRunE is the RunE for the authelia access-control test command.
+func (ctx *CmdCtx) AccessControlTestRunE(cmd *cobra.Command, args []string) (err error) {
+	validator.ValidateAccessControl(ctx.config, ctx.cconfig.validator)
+	validator.ValidateRules(ctx.config, ctx.cconfig.validator)
+
+	if ctx.cconfig.validator.HasErrors() || ctx.cconfig.validator.HasWarnings() {
+		return errors.New("your configuration has errors")
+	}
+
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return err
+	}
+
+	var tests []accessControlTest
+
+	if tests, err = loadAccessControlTests(args[0]); err != nil {
+		return err
+	}
+
+	authorizer := authorization.NewAuthorizer(ctx.config)
+
+	if provider := authorizer.GeoIP(); provider != nil {
+		if err = provider.StartupCheck(); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+
+	for i, test := range tests {
+		descriptor := test.descriptor(i + 1)
+
+		subject, object, err := test.subjectObject()
+		if err != nil {
+			return fmt.Errorf("%s: %w", descriptor, err)
+		}
+
+		results := authorizer.GetRuleMatchResults(subject, object)
+
+		if reason := test.check(authorizer.GetRequirement(subject, object)); reason != "" {
+			failed++
+
+			fmt.Printf("FAIL: %s: %s\n\n", descriptor, reason)
+
+			accessControlCheckWriteOutput(object, subject, results, ctx.config.AccessControl.DefaultPolicy, verbose)
+
+			continue
+		}
+
+		fmt.Printf("PASS: %s\n", descriptor)
+
+		if verbose {
+			fmt.Println()
+
+			accessControlCheckWriteOutput(object, subject, results, ctx.config.AccessControl.DefaultPolicy, verbose)
+		}
+	}
+
+	fmt.Printf("\n%d tests, %d passed, %d failed\n", len(tests), len(tests)-failed, failed)
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d access control tests failed", failed, len(tests))
+	}
+
+	return nil
+}
+
+// accessControlTest represents an assertion about the policy applied to a request in an access control test file.
+type accessControlTest struct {
+	Name string `yaml:"name"`
+
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+	Time    string            `yaml:"time"`
+
+	Username   string              `yaml:"username"`
+	Groups     []string            `yaml:"groups"`
+	Emails     []string            `yaml:"emails"`
+	Attributes map[string][]string `yaml:"attributes"`
+	IP         string              `yaml:"ip"`
+	Level      string              `yaml:"level"`
+	AMR        []string            `yaml:"amr"`
+
+	ExpectedPolicy string `yaml:"expected_policy"`
+	ExpectedRule   *int   `yaml:"expected_rule"`
+}
+
+func loadAccessControlTests(path string) (tests []accessControlTest, err error) {
+	var data []byte
+
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("failed to read the access control tests file: %w", err)
+	}
+
+	if err = yaml.Unmarshal(data, &tests); err != nil {
+		return nil, fmt.Errorf("failed to parse the access control tests file: %w", err)
+	}
+
+	if len(tests) == 0 {
+		return nil, fmt.Errorf("the access control tests file '%s' does not contain any tests", path)
+	}
+
+	for i, test := range tests {
+		if test.ExpectedPolicy == "" && test.ExpectedRule == nil {
+			return nil, fmt.Errorf("%s: must have the 'expected_policy' or 'expected_rule' option configured", test.descriptor(i+1))
+		}
+
+		if test.ExpectedPolicy != "" && !validator.IsPolicyValid(test.ExpectedPolicy) {
+			return nil, fmt.Errorf("%s: option 'expected_policy' must be one of 'deny', 'two_factor', 'one_factor' or 'bypass' but it's configured as '%s'", test.descriptor(i+1), test.ExpectedPolicy)
+		}
+	}
+
+	return tests, nil
+}
+
+func (t accessControlTest) descriptor(position int) string {
+	if t.Name == "" {
+		return fmt.Sprintf("test #%d", position)
+	}
+
+	return fmt.Sprintf("test #%d (%s)", position, t.Name)
+}
+
+func (t accessControlTest) subjectObject() (subject authorization.Subject, object authorization.Object, err error) {
+	var parsedURL *url.URL
+
+	if parsedURL, err = url.ParseRequestURI(t.URL); err != nil {
+		return subject, object, fmt.Errorf("option 'url' could not be parsed: %w", err)
+	}
+
+	method := t.Method
+	if method == "" {
+		method = fasthttp.MethodGet
+	}
+
+	subject = authorization.Subject{
+		Username:   t.Username,
+		Groups:     t.Groups,
+		Emails:     t.Emails,
+		Attributes: t.Attributes,
+		IP:         net.ParseIP(t.IP),
+		AMR:        t.AMR,
+	}
+
+	if subject.AuthenticationLevel, err = authentication.ParseLevel(t.Level); err != nil {
+		return subject, object, fmt.Errorf("option 'level' %w", err)
+	}
+
+	object = authorization.NewObject(parsedURL, method)
+
+	for name, value := range t.Headers {
+		if object.Header == nil {
+			object.Header = http.Header{}
+		}
+
+		object.Header.Add(name, value)
+	}
+
+	if t.Time != "" {
+		if object.Time, err = time.Parse(time.RFC3339, t.Time); err != nil {
+			return subject, object, fmt.Errorf("option 'time' with value '%s' is not in the RFC3339 format: %w", t.Time, err)
+		}
+	}
+
+	return subject, object, nil
+}
+
+// check returns the reason the test failed or an empty string if it passed. The requirement must be the one the
+// authorizer applies to the request so the tests reflect the rule which is applied at runtime.
+func (t accessControlTest) check(requirement authorization.Requirement) (reason string) {
+	policy, position := requirement.Level.String(), requirement.Position
+
+	var applied string
+
+	if position == 0 {
+		applied = fmt.Sprintf("the policy '%s' from the default policy", policy)
+	} else {
+		applied = fmt.Sprintf("the policy '%s' from rule #%d", policy, position)
+	}
+
+	switch {
+	case t.ExpectedPolicy != "" && t.ExpectedPolicy != policy:
+		return fmt.Sprintf("expected the policy '%s' but %s was applied", t.ExpectedPolicy, applied)
+	case t.ExpectedRule != nil && *t.ExpectedRule != position:
+		if *t.ExpectedRule == 0 {
+			return fmt.Sprintf("expected the default policy but %s was applied", applied)
+		}
+
+		return fmt.Sprintf("expected rule #%d but %s was applied", *t.ExpectedRule, applied)
+	default:
+		return ""
+	}
+}
+
 func accessControlCheckWriteObjectSubject(object authorization.Object, subject authorization.Subject) {
 	output := strings.Builder{}
 
@@ -103,10 +341,18 @@ func accessControlCheckWriteObjectSubject(object authorization.Object, subject a
 		output.WriteString(fmt.Sprintf(" groups '%s'", strings.Join(subject.Groups, ",")))
 	}
 
+	if len(subject.Emails) != 0 {
+		output.WriteString(fmt.Sprintf(" emails '%s'", strings.Join(subject.Emails, ",")))
+	}
+
 	if subject.IP != nil {
 		output.WriteString(fmt.Sprintf(" from IP '%s'", subject.IP.String()))
 	}
 
+	if !object.Time.IsZero() {
+		output.WriteString(fmt.Sprintf(" at time '%s'", object.Time.Format(time.RFC3339)))
+	}
+
 	output.WriteString(".\n")
 
 	fmt.Println(output.String())
@@ -115,7 +361,7 @@ func accessControlCheckWriteObjectSubject(object authorization.Object, subject a
 func accessControlCheckWriteOutput(object authorization.Object, subject authorization.Subject, results []authorization.RuleMatchResult, defaultPolicy string, verbose bool) {
 	accessControlCheckWriteObjectSubject(object, subject)
 
-	fmt.Printf("  #\tDomain\tResource\tMethod\tNetwork\tSubject\n")
+	fmt.Printf("  #\tDomain\tResource\tHeaders\tMethod\tNetwork\tSchedule\tSubject\tCondition\n")
 
 	var (
 		appliedPos int
@@ -134,15 +380,15 @@ func accessControlCheckWriteOutput(object authorization.Object, subject authoriz
 		case result.IsMatch() && !result.Skipped:
 			appliedPos, applied = i+1, result
 
-			fmt.Printf("* %d\t%s\t%s\t\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact))
+			fmt.Printf("* %d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchHeaders), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSchedule), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact), hitMissMay(result.MatchCondition, result.MatchConditionExact))
 		case result.IsPotentialMatch() && !result.Skipped:
 			if potentialPos == 0 {
 				potentialPos, potential = i+1, result
 			}
 
-			fmt.Printf("~ %d\t%s\t%s\t\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact))
+			fmt.Printf("~ %d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchHeaders), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSchedule), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact), hitMissMay(result.MatchCondition, result.MatchConditionExact))
 		default:
-			fmt.Printf("  %d\t%s\t%s\t\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact))
+			fmt.Printf("  %d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchHeaders), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSchedule), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact), hitMissMay(result.MatchCondition, result.MatchConditionExact))
 		}
 	}
 
@@ -205,6 +451,31 @@ func getSubjectAndObjectFromFlags(cmd *cobra.Command) (subject authorization.Sub
 		return subject, object, err
 	}
 
+	emails, err := cmd.Flags().GetStringSlice("emails")
+	if err != nil {
+		return subject, object, err
+	}
+
+	attributeFlags, err := cmd.Flags().GetStringSlice("attributes")
+	if err != nil {
+		return subject, object, err
+	}
+
+	var attributes map[string][]string
+
+	for _, attribute := range attributeFlags {
+		name, value, ok := strings.Cut(attribute, "=")
+		if !ok {
+			return subject, object, fmt.Errorf("attribute '%s' is not in the format name=value", attribute)
+		}
+
+		if attributes == nil {
+			attributes = map[string][]string{}
+		}
+
+		attributes[name] = append(attributes[name], value)
+	}
+
 	remoteIP, err := cmd.Flags().GetString("ip")
 	if err != nil {
 		return subject, object, err
@@ -212,13 +483,62 @@ func getSubjectAndObjectFromFlags(cmd *cobra.Command) (subject authorization.Sub
 
 	parsedIP := net.ParseIP(remoteIP)
 
+	levelFlag, err := cmd.Flags().GetString("level")
+	if err != nil {
+		return subject, object, err
+	}
+
+	level, err := authentication.ParseLevel(levelFlag)
+	if err != nil {
+		return subject, object, fmt.Errorf("level %w", err)
+	}
+
+	amr, err := cmd.Flags().GetStringSlice("amr")
+	if err != nil {
+		return subject, object, err
+	}
+
 	subject = authorization.Subject{
-		Username: username,
-		Groups:   groups,
-		IP:       parsedIP,
+		Username:   username,
+		Groups:     groups,
+		Emails:     emails,
+		Attributes: attributes,
+		IP:         parsedIP,
+
+		AuthenticationLevel: level,
+		AMR:                 amr,
 	}
 
 	object = authorization.NewObject(parsedURL, method)
 
+	headerFlags, err := cmd.Flags().GetStringArray("header")
+	if err != nil {
+		return subject, object, err
+	}
+
+	for _, header := range headerFlags {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			return subject, object, fmt.Errorf("header '%s' is not in the format name:value", header)
+		}
+
+		if object.Header == nil {
+			object.Header = http.Header{}
+		}
+
+		object.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	requestTime, err := cmd.Flags().GetString("time")
+	if err != nil {
+		return subject, object, err
+	}
+
+	if requestTime != "" {
+		if object.Time, err = time.Parse(time.RFC3339, requestTime); err != nil {
+			return subject, object, fmt.Errorf("time '%s' is not in the RFC3339 format: %w", requestTime, err)
+		}
+	}
+
 	return subject, object, nil
 }
@@ -0,0 +1,263 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type AccessControlCmdSuite struct {
+	suite.Suite
+
+	ctx  *CmdCtx
+	path string
+}
+
+func (s *AccessControlCmdSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "acl-tests.yml")
+
+	s.ctx = NewCmdCtx()
+	s.ctx.cconfig = NewCmdCtxConfig()
+	s.ctx.config.AccessControl = schema.AccessControlConfiguration{
+		DefaultPolicy: "deny",
+		Rules: []schema.ACLRule{
+			{
+				Domains: []string{"public.example.com"},
+				Policy:  "bypass",
+			},
+			{
+				Domains:  []string{"admin.example.com"},
+				Policy:   "two_factor",
+				Subjects: [][]string{{"group:admins"}},
+			},
+			{
+				Domains:  []string{"admin.example.com"},
+				Policy:   "one_factor",
+				Networks: []string{"192.168.1.0/24"},
+			},
+		},
+	}
+}
+
+func (s *AccessControlCmdSuite) TestShouldPass() {
+	s.Require().NoError(os.WriteFile(s.path, []byte(`
+- name: 'Public'
+  url: 'https://public.example.com/'
+  expected_policy: 'bypass'
+  expected_rule: 1
+- name: 'Admins'
+  url: 'https://admin.example.com/'
+  username: 'john'
+  groups:
+    - 'admins'
+  expected_policy: 'two_factor'
+  expected_rule: 2
+- name: 'Anonymous'
+  url: 'https://admin.example.com/'
+  ip: '192.168.1.10'
+  expected_policy: 'two_factor'
+  expected_rule: 2
+- url: 'https://admin.example.com/'
+  method: 'POST'
+  username: 'harry'
+  ip: '192.168.1.10'
+  expected_rule: 3
+- url: 'https://admin.example.com/'
+  username: 'harry'
+  ip: '10.0.0.1'
+  expected_policy: 'deny'
+  expected_rule: 0
+`), 0600))
+
+	cmd := newAccessControlTestCommand(s.ctx)
+
+	s.Require().NoError(cmd.ParseFlags([]string{"--verbose"}))
+	s.NoError(s.ctx.AccessControlTestRunE(cmd, []string{s.path}))
+}
+
+func (s *AccessControlCmdSuite) TestShouldFail() {
+	s.Require().NoError(os.WriteFile(s.path, []byte(`
+- url: 'https://public.example.com/'
+  expected_policy: 'deny'
+- url: 'https://admin.example.com/'
+  username: 'john'
+  groups:
+    - 'admins'
+  expected_policy: 'two_factor'
+  expected_rule: 3
+- url: 'https://admin.example.com/'
+  ip: '192.168.1.10'
+  expected_policy: 'two_factor'
+`), 0600))
+
+	cmd := newAccessControlTestCommand(s.ctx)
+
+	s.EqualError(s.ctx.AccessControlTestRunE(cmd, []string{s.path}), "2 of 3 access control tests failed")
+}
+
+func (s *AccessControlCmdSuite) TestShouldValidateRules() {
+	s.Require().NoError(os.WriteFile(s.path, []byte(`
+- url: 'https://api.example.com/'
+  headers:
+    X-Api-Client: 'backup'
+  expected_policy: 'bypass'
+  expected_rule: 4
+`), 0600))
+
+	s.ctx.config.AccessControl.Rules = append(s.ctx.config.AccessControl.Rules, schema.ACLRule{
+		Domains: []string{"api.example.com"},
+		Policy:  "bypass",
+		Headers: [][]schema.ACLQueryRule{{{Key: "X-Api-Client", Value: "backup"}}},
+	})
+
+	s.NoError(s.ctx.AccessControlTestRunE(newAccessControlTestCommand(s.ctx), []string{s.path}))
+}
+
+func (s *AccessControlCmdSuite) TestShouldErrorInvalidConfiguration() {
+	s.Require().NoError(os.WriteFile(s.path, []byte("- url: 'https://public.example.com/'\n  expected_policy: 'bypass'"), 0600))
+
+	s.ctx.config.AccessControl.Rules[0].Subjects = [][]string{{"invalid"}}
+
+	s.EqualError(s.ctx.AccessControlTestRunE(newAccessControlTestCommand(s.ctx), []string{s.path}), "your configuration has errors")
+}
+
+func (s *AccessControlCmdSuite) TestShouldCheckResult() {
+	testCases := []struct {
+		name     string
+		test     accessControlTest
+		expected string
+	}{
+		{
+			"ShouldPassPolicy",
+			accessControlTest{URL: "https://public.example.com/", ExpectedPolicy: "bypass"},
+			"",
+		},
+		{
+			"ShouldFailPolicy",
+			accessControlTest{URL: "https://public.example.com/", ExpectedPolicy: "deny"},
+			"expected the policy 'deny' but the policy 'bypass' from rule #1 was applied",
+		},
+		{
+			"ShouldFailRule",
+			accessControlTest{URL: "https://admin.example.com/", Username: "harry", IP: "192.168.1.1", ExpectedRule: intPtr(2)},
+			"expected rule #2 but the policy 'one_factor' from rule #3 was applied",
+		},
+		{
+			"ShouldFailDefaultPolicy",
+			accessControlTest{URL: "https://admin.example.com/", Username: "harry", IP: "192.168.1.1", ExpectedRule: intPtr(0)},
+			"expected the default policy but the policy 'one_factor' from rule #3 was applied",
+		},
+		{
+			"ShouldApplySubjectRuleToAnonymous",
+			accessControlTest{URL: "https://admin.example.com/", IP: "192.168.1.1", ExpectedPolicy: "one_factor"},
+			"expected the policy 'one_factor' but the policy 'two_factor' from rule #2 was applied",
+		},
+		{
+			"ShouldPassQueryRule",
+			accessControlTest{URL: "https://query.example.com/?mode=read", ExpectedRule: intPtr(4)},
+			"",
+		},
+		{
+			"ShouldNotApplyQueryRuleWhenQueryDoesNotMatch",
+			accessControlTest{URL: "https://query.example.com/?mode=write", ExpectedRule: intPtr(4)},
+			"expected rule #4 but the policy 'one_factor' from rule #5 was applied",
+		},
+		{
+			"ShouldFailNotDefaultPolicy",
+			accessControlTest{URL: "https://example.com/", ExpectedPolicy: "bypass"},
+			"expected the policy 'bypass' but the policy 'deny' from the default policy was applied",
+		},
+	}
+
+	s.ctx.config.AccessControl.Rules = append(s.ctx.config.AccessControl.Rules,
+		schema.ACLRule{
+			Domains: []string{"query.example.com"},
+			Policy:  "bypass",
+			Query:   [][]schema.ACLQueryRule{{{Operator: "equal", Key: "mode", Value: "read"}}},
+		},
+		schema.ACLRule{
+			Domains: []string{"query.example.com"},
+			Policy:  "one_factor",
+		},
+	)
+
+	authorizer := authorization.NewAuthorizer(s.ctx.config)
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			subject, object, err := tc.test.subjectObject()
+
+			s.Require().NoError(err)
+
+			s.Equal(tc.expected, tc.test.check(authorizer.GetRequirement(subject, object)))
+		})
+	}
+}
+
+func (s *AccessControlCmdSuite) TestShouldErrorInvalidFile() {
+	testCases := []struct {
+		name     string
+		tests    string
+		expected string
+	}{
+		{
+			"ShouldErrorNoTests",
+			"",
+			"does not contain any tests",
+		},
+		{
+			"ShouldErrorNoExpectations",
+			"- url: 'https://public.example.com/'",
+			"test #1: must have the 'expected_policy' or 'expected_rule' option configured",
+		},
+		{
+			"ShouldErrorInvalidPolicy",
+			"- name: 'Bad'\n  url: 'https://public.example.com/'\n  expected_policy: 'allow'",
+			"test #1 (Bad): option 'expected_policy' must be one of 'deny', 'two_factor', 'one_factor' or 'bypass' but it's configured as 'allow'",
+		},
+		{
+			"ShouldErrorInvalidURL",
+			"- url: 'public.example.com'\n  expected_policy: 'bypass'",
+			"test #1: option 'url' could not be parsed",
+		},
+		{
+			"ShouldErrorInvalidTime",
+			"- url: 'https://public.example.com/'\n  time: 'now'\n  expected_policy: 'bypass'",
+			"test #1: option 'time' with value 'now' is not in the RFC3339 format",
+		},
+		{
+			"ShouldErrorInvalidLevel",
+			"- url: 'https://public.example.com/'\n  level: 'three_factor'\n  expected_policy: 'bypass'",
+			"test #1: option 'level' must be one of 'not_authenticated', 'one_factor' or 'two_factor' but it's configured as 'three_factor'",
+		},
+		{
+			"ShouldErrorInvalidYAML",
+			"url: 'https://public.example.com/'",
+			"failed to parse the access control tests file",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.Require().NoError(os.WriteFile(s.path, []byte(tc.tests), 0600))
+
+			err := s.ctx.AccessControlTestRunE(newAccessControlTestCommand(s.ctx), []string{s.path})
+
+			s.Require().Error(err)
+			s.Contains(err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestRunAccessControlCmdSuite(t *testing.T) {
+	suite.Run(t, new(AccessControlCmdSuite))
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -73,9 +73,172 @@ Notes:
 	cmdAutheliaAccessControlCheckPolicyExample = `authelia access-control check-policy --config config.yml --url https://example.com
 authelia access-control check-policy --config config.yml --url https://example.com --username john
 authelia access-control check-policy --config config.yml --url https://example.com --groups admin,public
+authelia access-control check-policy --config config.yml --url https://example.com --attributes department=engineering
 authelia access-control check-policy --config config.yml --url https://example.com --username john --method GET
 authelia access-control check-policy --config config.yml --url https://example.com --username john --method GET --verbose`
 
+	cmdAutheliaAccessControlTestShort = "Runs the assertions in a file against the access control rules"
+
+	cmdAutheliaAccessControlTestLong = `
+Runs the assertions in a file against the access control rules to ensure the expected policy is applied to each request.
+
+The file is a YAML list of tests. Each test describes a request and the policy and/or the position of the rule which
+is expected to be applied to it, where position 0 is the default policy. The command exits with a non-zero exit code
+if any of the tests fail, which makes it suitable to run in continuous integration whenever the configuration changes.
+
+The check-policy output is printed for each failed test, or for every test if the verbose flag is provided.
+
+Example file:
+
+	- name: 'Admins can access the admin portal with two factor'
+	  url: 'https://admin.example.com/'
+	  method: 'GET'
+	  username: 'john'
+	  groups:
+	    - 'admins'
+	  ip: '192.168.1.10'
+	  expected_policy: 'two_factor'
+	  expected_rule: 3
+	- name: 'Anonymous users are denied the admin portal'
+	  url: 'https://admin.example.com/'
+	  expected_policy: 'deny'
+
+Test options:
+
+	name             The name of the test used in the output.
+	url              The url of the request.
+	method           The HTTP method of the request, defaults to GET.
+	headers          The headers of the request as a map of names to values.
+	time             The time of the request in RFC3339 format, defaults to the current time.
+	username         The username of the subject.
+	groups           The groups of the subject.
+	emails           The emails of the subject.
+	attributes       The extra attributes of the subject as a map of names to lists of values.
+	ip               The ip of the subject.
+	level            The authentication level of the subject, one of not_authenticated, one_factor, or two_factor.
+	amr              The RFC8176 authentication method references of the subject.
+	expected_policy  The policy which is expected to be applied.
+	expected_rule    The position of the rule which is expected to be applied, 0 is the default policy.
+`
+
+	cmdAutheliaAccessControlTestExample = `authelia access-control test --config config.yml acl-tests.yml
+authelia access-control test --config config.yml acl-tests.yml --verbose`
+
+	cmdAutheliaUsersShort = "Manage the users in the file authentication backend"
+
+	cmdAutheliaUsersLong = `Manage the users in the file authentication backend.
+
+This subcommand has several methods to interact with the file authentication backend user database. The database is
+validated before every change is saved and the changes are written atomically, so a running instance of Authelia
+will only ever observe the complete database.
+
+The path to the database and the password hashing options are read from the configuration, and the path can be
+overridden with the --path flag.`
+
+	cmdAutheliaUsersExample = `authelia users --help`
+
+	cmdAutheliaUsersListShort = "List the users in the file authentication backend"
+
+	cmdAutheliaUsersListLong = `List the users in the file authentication backend.
+
+This subcommand lists the username, display name, email, groups, and disabled state of every user.`
+
+	cmdAutheliaUsersListExample = `authelia users list --config config.yml
+authelia users list --path users_database.yml`
+
+	cmdAutheliaUsersAddShort = "Add a user to the file authentication backend"
+
+	cmdAutheliaUsersAddLong = `Add a user to the file authentication backend.
+
+This subcommand adds a user, hashing the password with the password options from the configuration. The password is
+read from the terminal unless it's supplied via the --password flag or the --random flag is used.`
+
+	cmdAutheliaUsersAddExample = `authelia users add john --config config.yml --display-name "John Doe" --email john.doe@example.com --group admins --group dev
+authelia users add john --path users_database.yml --random`
+
+	cmdAutheliaUsersDeleteShort = "Delete a user from the file authentication backend"
+
+	cmdAutheliaUsersDeleteLong = `Delete a user from the file authentication backend.`
+
+	cmdAutheliaUsersDeleteExample = `authelia users delete john --config config.yml`
+
+	cmdAutheliaUsersPasswdShort = "Change the password of a user in the file authentication backend"
+
+	cmdAutheliaUsersPasswdLong = `Change the password of a user in the file authentication backend.
+
+This subcommand hashes the password with the password options from the configuration. The password is read from the
+terminal unless it's supplied via the --password flag or the --random flag is used.`
+
+	cmdAutheliaUsersPasswdExample = `authelia users passwd john --config config.yml
+authelia users passwd john --config config.yml --random`
+
+	cmdAutheliaUsersGroupsShort = "Manage the groups of a user in the file authentication backend"
+
+	cmdAutheliaUsersGroupsLong = `Manage the groups of a user in the file authentication backend.`
+
+	cmdAutheliaUsersGroupsExample = `authelia users groups --help`
+
+	cmdAutheliaUsersGroupsAddShort = "Add a user to one or more groups"
+
+	cmdAutheliaUsersGroupsAddLong = `Add a user to one or more groups.
+
+Groups the user is already a member of are ignored.`
+
+	cmdAutheliaUsersGroupsAddExample = `authelia users groups add john admins dev --config config.yml`
+
+	cmdAutheliaUsersGroupsRemoveShort = "Remove a user from one or more groups"
+
+	cmdAutheliaUsersGroupsRemoveLong = `Remove a user from one or more groups.
+
+Groups the user is not a member of are ignored.`
+
+	cmdAutheliaUsersGroupsRemoveExample = `authelia users groups remove john dev --config config.yml`
+
+	cmdAutheliaUsersDisableShort = "Disable a user in the file authentication backend"
+
+	cmdAutheliaUsersDisableLong = `Disable a user in the file authentication backend.
+
+Disabled users are unable to login.`
+
+	cmdAutheliaUsersDisableExample = `authelia users disable john --config config.yml`
+
+	cmdAutheliaUsersEnableShort = "Enable a user in the file authentication backend"
+
+	cmdAutheliaUsersEnableLong = `Enable a user in the file authentication backend.`
+
+	cmdAutheliaUsersEnableExample = `authelia users enable john --config config.yml`
+
+	cmdAutheliaSessionsShort = "Manage the sessions of users"
+
+	cmdAutheliaSessionsLong = `Manage the sessions of users.
+
+This subcommand has several methods to list and revoke the active sessions of a user. The sessions are read from the
+session provider in the configuration, so the redis session provider must be configured for the sessions of a running
+instance of Authelia to be visible to this subcommand. The subcommands which read the sessions fail when the memory
+session provider is configured.`
+
+	cmdAutheliaSessionsExample = `authelia sessions --help`
+
+	cmdAutheliaSessionsListShort = "List the active sessions of a user"
+
+	cmdAutheliaSessionsListLong = `List the active sessions of a user.
+
+This subcommand lists the id, domain, IP, user agent, creation time, last activity time, and authentication level of
+every active session of the user ordered by the most recent activity.`
+
+	cmdAutheliaSessionsListExample = `authelia sessions list john --config config.yml`
+
+	cmdAutheliaSessionsRevokeShort = "Revoke the active sessions of a user"
+
+	cmdAutheliaSessionsRevokeLong = `Revoke the active sessions of a user.
+
+This subcommand revokes the session with the id from the list subcommand when the --id flag is provided, otherwise
+it revokes every session of the user including the sessions which were not indexed by the session provider. The --id
+flag requires the redis session provider.`
+
+	cmdAutheliaSessionsRevokeExample = `authelia sessions revoke john --config config.yml
+authelia sessions revoke john --config config.yml --id 5f7a1d6c0e3b4a29b8c4d2e1f0a9b8c7`
+
 	cmdAutheliaStorageShort = "Manage the Authelia storage"
 
 	cmdAutheliaStorageLong = `Manage the Authelia storage.
@@ -122,6 +285,39 @@ This subcommand allows modifying and exporting user settings.`
 
 	cmdAutheliaStorageUserExample = `authelia storage user --help`
 
+	cmdAutheliaStorageUserAccountsShort = "Manage the users in the storage authentication backend"
+
+	cmdAutheliaStorageUserAccountsLong = `Manage the users in the storage authentication backend.
+
+This subcommand allows provisioning, listing, and deleting the users managed by the storage authentication backend. The
+passwords are hashed with the password options of the storage authentication backend from the configuration.`
+
+	cmdAutheliaStorageUserAccountsExample = `authelia storage user accounts --help`
+
+	cmdAutheliaStorageUserAccountsListShort = "List the users in the storage authentication backend"
+
+	cmdAutheliaStorageUserAccountsListLong = `List the users in the storage authentication backend.
+
+This subcommand lists the username, display name, email, groups, and disabled state of every user.`
+
+	cmdAutheliaStorageUserAccountsListExample = `authelia storage user accounts list --config config.yml`
+
+	cmdAutheliaStorageUserAccountsAddShort = "Add a user to the storage authentication backend"
+
+	cmdAutheliaStorageUserAccountsAddLong = `Add a user to the storage authentication backend.
+
+This subcommand adds a user, hashing the password with the password options from the configuration. The password is
+read from the terminal unless it's supplied via the --password flag or the --random flag is used.`
+
+	cmdAutheliaStorageUserAccountsAddExample = `authelia storage user accounts add john --config config.yml --display-name "John Doe" --email john.doe@example.com --group admins --group dev
+authelia storage user accounts add john --config config.yml --random`
+
+	cmdAutheliaStorageUserAccountsDeleteShort = "Delete a user from the storage authentication backend"
+
+	cmdAutheliaStorageUserAccountsDeleteLong = `Delete a user from the storage authentication backend.`
+
+	cmdAutheliaStorageUserAccountsDeleteExample = `authelia storage user accounts delete john --config config.yml`
+
 	cmdAutheliaStorageUserIdentifiersShort = "Manage user opaque identifiers"
 
 	cmdAutheliaStorageUserIdentifiersLong = `Manage user opaque identifiers.
@@ -573,6 +769,11 @@ const (
 	cmdFlagNamePath        = "path"
 	cmdFlagNameTarget      = "target"
 	cmdFlagNameDestroyData = "destroy-data"
+	cmdFlagNameDisplayName = "display-name"
+	cmdFlagNameEmail       = "email"
+	cmdFlagNameGroup       = "group"
+	cmdFlagNameDisabled    = "disabled"
+	cmdFlagNameID          = "id"
 
 	cmdFlagNameEncryptionKey      = "encryption-key"
 	cmdFlagNameSQLite3Path        = "sqlite.path"
@@ -608,6 +809,9 @@ const (
 	cmdUseRSA         = "rsa"
 	cmdUseECDSA       = "ecdsa"
 	cmdUseEd25519     = "ed25519"
+
+	cmdUseUsers    = "users"
+	cmdUseSessions = "sessions"
 )
 
 const (
@@ -620,6 +824,8 @@ const (
 var (
 	errStorageSchemaOutdated     = errors.New("storage schema outdated")
 	errStorageSchemaIncompatible = errors.New("storage schema incompatible")
+
+	errSessionsMemoryProvider = errors.New("the sessions of the running instances are only visible when the redis session provider is configured")
 )
 
 const (
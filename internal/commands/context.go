@@ -11,7 +11,6 @@ import (
 	"golang.org/x/net/context"
 	"golang.org/x/sync/errgroup"
 
-	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -73,10 +72,42 @@ func NewCmdCtxConfig() *CmdCtxConfig {
 type CmdCtxConfig struct {
 	defaults  configuration.Source
 	sources   []configuration.Source
+	files     []string
+	filters   []configuration.FileFilter
 	keys      []string
 	validator *schema.StructValidator
 }
 
+// LoadAccessControl loads the access control configuration from the same sources as the configuration, or from the
+// access_control key of the file at the provided path if it's not empty.
+func (c *CmdCtxConfig) LoadAccessControl(val *schema.StructValidator, path string) (config schema.AccessControlConfiguration, err error) {
+	var sources []configuration.Source
+
+	if path == "" {
+		sources = configuration.NewDefaultSourcesWithDefaults(
+			c.files,
+			c.filters,
+			configuration.DefaultEnvPrefix,
+			configuration.DefaultEnvDelimiter,
+			c.defaults,
+			c.sources...)
+	} else {
+		sources = []configuration.Source{configuration.NewFilteredFileSource(path, c.filters...)}
+	}
+
+	var keys []string
+
+	if keys, err = configuration.LoadAdvanced(val, "access_control", &config, sources...); err != nil {
+		return config, err
+	}
+
+	if path != "" {
+		validator.ValidateAccessControlPathKeys(path, keys, configuration.DefaultEnvPrefix, val)
+	}
+
+	return config, nil
+}
+
 // CobraRunECmd describes a function that can be used as a *cobra.Command RunE, PreRunE, or PostRunE.
 type CobraRunECmd func(cmd *cobra.Command, args []string) (err error)
 
@@ -125,7 +156,7 @@ func (ctx *CmdCtx) LoadProviders() (warns, errs []error) {
 	providers := middlewares.Providers{
 		Authorizer:      authorization.NewAuthorizer(ctx.config),
 		NTP:             ntp.NewProvider(&ctx.config.NTP),
-		PasswordPolicy:  middlewares.NewPasswordPolicyProvider(ctx.config.PasswordPolicy),
+		PasswordPolicy:  middlewares.NewPasswordPolicyProvider(ctx.config.PasswordPolicy, ctx.trusted),
 		Regulator:       regulation.NewRegulator(ctx.config.Regulation, storage, utils.RealClock{}),
 		SessionProvider: session.NewProvider(ctx.config.Session, ctx.trusted),
 		StorageProvider: storage,
@@ -134,11 +165,8 @@ func (ctx *CmdCtx) LoadProviders() (warns, errs []error) {
 
 	var err error
 
-	switch {
-	case ctx.config.AuthenticationBackend.File != nil:
-		providers.UserProvider = authentication.NewFileUserProvider(ctx.config.AuthenticationBackend.File)
-	case ctx.config.AuthenticationBackend.LDAP != nil:
-		providers.UserProvider = authentication.NewLDAPUserProvider(ctx.config.AuthenticationBackend, ctx.trusted)
+	if providers.PasswordHistory, err = middlewares.NewPasswordHistoryProvider(ctx.config.PasswordPolicy.History, getPasswordConfig(&ctx.config.AuthenticationBackend), storage); err != nil {
+		errs = append(errs, err)
 	}
 
 	if providers.Templates, err = templates.New(templates.Config{EmailTemplatesPath: ctx.config.Notifier.TemplatePath}); err != nil {
@@ -158,8 +186,16 @@ func (ctx *CmdCtx) LoadProviders() (warns, errs []error) {
 
 	if ctx.config.Telemetry.Metrics.Enabled {
 		providers.Metrics = metrics.NewPrometheus()
+
+		providers.Authorizer.SetMetricsRecorder(providers.Metrics)
+
+		if breached, ok := providers.PasswordPolicy.(*middlewares.BreachedPasswordPolicyProvider); ok {
+			breached.SetMetricsRecorder(providers.Metrics)
+		}
 	}
 
+	providers.UserProvider = getUserProvider(ctx, storage, providers.Metrics)
+
 	ctx.providers = providers
 
 	return warns, errs
@@ -335,5 +371,27 @@ func (ctx *CmdCtx) ConfigLoadRunE(cmd *cobra.Command, _ []string) (err error) {
 		return err
 	}
 
+	ctx.cconfig.files, ctx.cconfig.filters = configs, filters
+
+	if ctx.config.AccessControl.Path != "" {
+		return ctx.configLoadAccessControlPath()
+	}
+
+	return nil
+}
+
+func (ctx *CmdCtx) configLoadAccessControlPath() (err error) {
+	path, watch := ctx.config.AccessControl.Path, ctx.config.AccessControl.Watch
+
+	if !validator.ValidateAccessControlPath(&ctx.config.AccessControl, ctx.cconfig.validator) {
+		return nil
+	}
+
+	if ctx.config.AccessControl, err = ctx.cconfig.LoadAccessControl(ctx.cconfig.validator, path); err != nil {
+		return err
+	}
+
+	ctx.config.AccessControl.Path, ctx.config.AccessControl.Watch = path, watch
+
 	return nil
 }
@@ -7,7 +7,9 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/metrics"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/storage"
 )
@@ -25,6 +27,72 @@ func getStorageProvider(ctx *CmdCtx) (provider storage.Provider) {
 	}
 }
 
+func getUserProvider(ctx *CmdCtx, storage storage.Provider, recorder metrics.Provider) (provider authentication.UserProvider) {
+	if provider = getUserProviderUncached(ctx, storage, recorder); provider == nil || !ctx.config.AuthenticationBackend.Cache.Enable {
+		return provider
+	}
+
+	return authentication.NewCachingUserProvider(ctx.config.AuthenticationBackend.Cache, provider)
+}
+
+func getUserProviderUncached(ctx *CmdCtx, storage storage.Provider, recorder metrics.Provider) (provider authentication.UserProvider) {
+	if len(ctx.config.AuthenticationBackend.Chain) != 0 {
+		chain := authentication.NewChainUserProvider()
+
+		for _, name := range ctx.config.AuthenticationBackend.Chain {
+			chain.Add(name, getUserProviderByName(ctx, name, storage, recorder))
+		}
+
+		return chain
+	}
+
+	switch {
+	case ctx.config.AuthenticationBackend.File != nil:
+		return getUserProviderByName(ctx, schema.AuthenticationBackendFile, storage, recorder)
+	case ctx.config.AuthenticationBackend.LDAP != nil:
+		return getUserProviderByName(ctx, schema.AuthenticationBackendLDAP, storage, recorder)
+	case ctx.config.AuthenticationBackend.Storage != nil:
+		return getUserProviderByName(ctx, schema.AuthenticationBackendStorage, storage, recorder)
+	default:
+		return nil
+	}
+}
+
+func getUserProviderByName(ctx *CmdCtx, name string, storage storage.Provider, recorder metrics.Provider) (provider authentication.UserProvider) {
+	switch name {
+	case schema.AuthenticationBackendFile:
+		return authentication.NewFileUserProvider(ctx.config.AuthenticationBackend.File)
+	case schema.AuthenticationBackendLDAP:
+		ldap := authentication.NewLDAPUserProvider(ctx.config.AuthenticationBackend, ctx.trusted)
+
+		if recorder != nil {
+			ldap.SetMetricsRecorder(recorder)
+		}
+
+		return ldap
+	case schema.AuthenticationBackendStorage:
+		return authentication.NewStorageUserProvider(ctx.config.AuthenticationBackend.Storage, storage)
+	default:
+		return nil
+	}
+}
+
+// getFileUserProvider returns the FileUserProvider if one is configured either directly or as part of a chain.
+func getFileUserProvider(provider authentication.UserProvider) (fileProvider *authentication.FileUserProvider, ok bool) {
+	switch p := provider.(type) {
+	case *authentication.FileUserProvider:
+		return p, true
+	case *authentication.ChainUserProvider:
+		if provider, ok = p.Get(schema.AuthenticationBackendFile); ok {
+			return getFileUserProvider(provider)
+		}
+	case *authentication.CachingUserProvider:
+		return getFileUserProvider(p.Provider())
+	}
+
+	return nil, false
+}
+
 func containsIdentifier(identifier model.UserOpaqueIdentifier, identifiers []model.UserOpaqueIdentifier) bool {
 	for i := 0; i < len(identifiers); i++ {
 		if identifier.Service == identifiers[i].Service && identifier.SectorID == identifiers[i].SectorID && identifier.Username == identifiers[i].Username {
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -14,6 +15,8 @@ import (
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/server"
@@ -53,7 +56,9 @@ func NewRootCmd() (cmd *cobra.Command) {
 		newAccessControlCommand(ctx),
 		newBuildInfoCmd(ctx),
 		newCryptoCmd(ctx),
+		newSessionsCmd(ctx),
 		newStorageCmd(ctx),
+		newUsersCmd(ctx),
 		newValidateConfigCmd(ctx),
 
 		newHelpTopic("filters", "Help for the config filters", helpTopicConfigFilters),
@@ -91,15 +96,21 @@ func (ctx *CmdCtx) RootRunE(_ *cobra.Command, _ []string) (err error) {
 
 	doStartupChecks(ctx)
 
+	var acl *accessControlReload
+
+	if ctx.config.AccessControl.Watch {
+		acl = &accessControlReload{ctx: ctx, cconfig: ctx.cconfig}
+	}
+
 	ctx.cconfig = nil
 
-	runServices(ctx)
+	runServices(ctx, acl)
 
 	return nil
 }
 
 //nolint:gocyclo // Complexity is required in this function.
-func runServices(ctx *CmdCtx) {
+func runServices(ctx *CmdCtx, acl *accessControlReload) {
 	defer ctx.cancel()
 
 	quit := make(chan os.Signal, 1)
@@ -158,14 +169,41 @@ func runServices(ctx *CmdCtx) {
 	})
 
 	if ctx.config.AuthenticationBackend.File != nil && ctx.config.AuthenticationBackend.File.Watch {
-		provider := ctx.providers.UserProvider.(*authentication.FileUserProvider)
-		if watcher, err := runServiceFileWatcher(ctx, ctx.config.AuthenticationBackend.File.Path, provider); err != nil {
+		var reload ProviderReload
+
+		reload, _ = getFileUserProvider(ctx.providers.UserProvider)
+
+		if caching, ok := ctx.providers.UserProvider.(*authentication.CachingUserProvider); ok {
+			reload = &cachingProviderReload{reload: reload, caching: caching}
+		}
+
+		if watcher, err := runServiceFileWatcher(ctx, ctx.config.AuthenticationBackend.File.Path, reload); err != nil {
 			ctx.log.WithError(err).Errorf("Error opening file watcher")
 		} else {
 			defer watcher.Close()
 		}
 	}
 
+	if provider := ctx.providers.Authorizer.GeoIP(); provider != nil {
+		for _, path := range provider.Paths() {
+			if watcher, err := runServiceFileWatcher(ctx, path, provider); err != nil {
+				ctx.log.WithError(err).Errorf("Error opening file watcher")
+			} else {
+				defer watcher.Close()
+			}
+		}
+	}
+
+	if acl != nil {
+		for _, path := range acl.Paths() {
+			if watcher, err := runServiceFileWatcher(ctx, path, acl); err != nil {
+				ctx.log.WithError(err).Errorf("Error opening file watcher")
+			} else {
+				defer watcher.Close()
+			}
+		}
+	}
+
 	select {
 	case s := <-quit:
 		switch s {
@@ -196,6 +234,10 @@ func runServices(ctx *CmdCtx) {
 		}
 	}
 
+	if closer, ok := ctx.providers.UserProvider.(authentication.UserProviderCloser); ok {
+		closer.Close()
+	}
+
 	if err = ctx.providers.StorageProvider.Close(); err != nil {
 		ctx.log.WithError(err).Errorf("Error occurred closing the database connection")
 	}
@@ -211,6 +253,77 @@ type ProviderReload interface {
 	Reload() (reloaded bool, err error)
 }
 
+// cachingProviderReload is a ProviderReload which purges the user details cache when the provider is reloaded.
+type cachingProviderReload struct {
+	reload  ProviderReload
+	caching *authentication.CachingUserProvider
+}
+
+// Reload the provider and purge the cache if the provider was reloaded.
+func (r *cachingProviderReload) Reload() (reloaded bool, err error) {
+	if reloaded, err = r.reload.Reload(); reloaded {
+		r.caching.Purge()
+	}
+
+	return reloaded, err
+}
+
+// accessControlReload is a ProviderReload which reloads the access control configuration and replaces the rules of the
+// authorizer if the reloaded configuration is valid.
+type accessControlReload struct {
+	ctx     *CmdCtx
+	cconfig *CmdCtxConfig
+}
+
+// Paths returns the paths of the files which contain the access control configuration.
+func (r *accessControlReload) Paths() (paths []string) {
+	if r.ctx.config.AccessControl.Path != "" {
+		return []string{r.ctx.config.AccessControl.Path}
+	}
+
+	for _, path := range r.cconfig.files {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			r.ctx.log.WithField("directory", path).Warn("Access control changes in configuration directories are not watched, use a configuration file instead")
+
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+// Reload the access control configuration. The existing rules are kept if the reloaded configuration is invalid.
+func (r *accessControlReload) Reload() (reloaded bool, err error) {
+	val := schema.NewStructValidator()
+
+	config := *r.ctx.config
+
+	if config.AccessControl, err = r.cconfig.LoadAccessControl(val, r.ctx.config.AccessControl.Path); err != nil {
+		return false, err
+	}
+
+	validator.ValidateAccessControl(&config, val)
+	validator.ValidateRules(&config, val)
+
+	if errs := val.Errors(); len(errs) != 0 {
+		for _, err = range errs {
+			r.ctx.log.WithError(err).Error("Error occurred validating the access control configuration")
+		}
+
+		return false, errors.New("the access control configuration is invalid so the existing rules have been kept")
+	}
+
+	for _, warn := range val.Warnings() {
+		r.ctx.log.Warnf("Access Control: %+v", warn)
+	}
+
+	r.ctx.providers.Authorizer.Update(config.AccessControl)
+
+	return true, nil
+}
+
 func runServiceFileWatcher(ctx *CmdCtx, path string, reload ProviderReload) (watcher *fsnotify.Watcher, err error) {
 	if watcher, err = fsnotify.NewWatcher(); err != nil {
 		return nil, err
@@ -298,6 +411,14 @@ func doStartupChecks(ctx *CmdCtx) {
 		failures = append(failures, "notification")
 	}
 
+	if provider := ctx.providers.Authorizer.GeoIP(); provider != nil {
+		if err = doStartupCheck(ctx, "geoip", provider, false); err != nil {
+			ctx.log.Errorf("Failure running the geoip provider startup check: %+v", err)
+
+			failures = append(failures, "geoip")
+		}
+	}
+
 	if !ctx.config.NTP.DisableStartupCheck && !ctx.providers.Authorizer.IsSecondFactorEnabled() {
 		ctx.log.Debug("The NTP startup check was skipped due to there being no configured 2FA access control rules")
 	} else if err = doStartupCheck(ctx, "ntp", ctx.providers.NTP, ctx.config.NTP.DisableStartupCheck); err != nil {
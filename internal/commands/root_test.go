@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestAccessControlReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acl.yml")
+
+	ctx := NewCmdCtx()
+
+	ctx.config.AccessControl = schema.AccessControlConfiguration{
+		Path:          path,
+		Watch:         true,
+		DefaultPolicy: "deny",
+	}
+
+	ctx.providers.Authorizer = authorization.NewAuthorizer(ctx.config)
+
+	reload := &accessControlReload{ctx: ctx, cconfig: NewCmdCtxConfig()}
+
+	assert.Equal(t, []string{path}, reload.Paths())
+
+	object := authorization.NewObject(&url.URL{Scheme: "https", Host: "app.example.com", Path: "/"}, "GET")
+
+	_, level := ctx.providers.Authorizer.GetRequiredLevel(authorization.Subject{}, object)
+	assert.Equal(t, authorization.Denied, level)
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+access_control:
+  default_policy: deny
+  rules:
+    - domain: app.example.com
+      policy: one_factor
+`), 0600))
+
+	reloaded, err := reload.Reload()
+
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+
+	_, level = ctx.providers.Authorizer.GetRequiredLevel(authorization.Subject{}, object)
+	assert.Equal(t, authorization.OneFactor, level)
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+access_control:
+  default_policy: deny
+  rules:
+    - domain: app.example.com
+      policy: invalid
+`), 0600))
+
+	reloaded, err = reload.Reload()
+
+	assert.EqualError(t, err, "the access control configuration is invalid so the existing rules have been kept")
+	assert.False(t, reloaded)
+
+	_, level = ctx.providers.Authorizer.GetRequiredLevel(authorization.Subject{}, object)
+	assert.Equal(t, authorization.OneFactor, level)
+}
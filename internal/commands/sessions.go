@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func newSessionsCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     cmdUseSessions,
+		Short:   cmdAutheliaSessionsShort,
+		Long:    cmdAutheliaSessionsLong,
+		Example: cmdAutheliaSessionsExample,
+		Args:    cobra.NoArgs,
+		PersistentPreRunE: ctx.ChainRunE(
+			ctx.ConfigLoadRunE,
+			ctx.ConfigValidateSessionsPersistentPreRunE,
+			ctx.LoadProvidersStorageRunE,
+			ctx.LoadProvidersSessionRunE,
+		),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newSessionsListCmd(ctx),
+		newSessionsRevokeCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newSessionsListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list <username>",
+		Short:   cmdAutheliaSessionsListShort,
+		Long:    cmdAutheliaSessionsListLong,
+		Example: cmdAutheliaSessionsListExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.SessionsListRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newSessionsRevokeCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "revoke <username>",
+		Short:   cmdAutheliaSessionsRevokeShort,
+		Long:    cmdAutheliaSessionsRevokeLong,
+		Example: cmdAutheliaSessionsRevokeExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.SessionsRevokeRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameID, "", "the id of the session to revoke as shown by the list subcommand, revokes all sessions if not provided")
+
+	return cmd
+}
+
+// ConfigValidateSessionsPersistentPreRunE validates the session and storage config before running commands using it.
+func (ctx *CmdCtx) ConfigValidateSessionsPersistentPreRunE(_ *cobra.Command, _ []string) (err error) {
+	validator.ValidateStorage(ctx.config.Storage, ctx.cconfig.validator)
+
+	validator.ValidateSession(&ctx.config.Session, ctx.cconfig.validator)
+
+	if errs := ctx.cconfig.validator.Errors(); len(errs) != 0 {
+		var (
+			i int
+			e error
+		)
+
+		for i, e = range errs {
+			if i == 0 {
+				err = e
+				continue
+			}
+
+			err = fmt.Errorf("%w, %v", err, e)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// LoadProvidersSessionRunE is a special PreRunE that loads the session provider into the CmdCtx. It must be run
+// after the trusted certificates are loaded.
+func (ctx *CmdCtx) LoadProvidersSessionRunE(_ *cobra.Command, _ []string) (err error) {
+	ctx.providers.SessionProvider = session.NewProvider(ctx.config.Session, ctx.trusted)
+
+	return nil
+}
+
+// SessionsListRunE is the RunE for the authelia sessions list command.
+func (ctx *CmdCtx) SessionsListRunE(_ *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if ctx.config.Session.Redis == nil {
+		return errSessionsMemoryProvider
+	}
+
+	username := args[0]
+
+	var sessions []session.Metadata
+
+	if sessions, err = ctx.providers.SessionProvider.ListUserSessions(username); err != nil {
+		return fmt.Errorf("failed to list the sessions of user '%s': %w", username, err)
+	}
+
+	fmt.Printf("Sessions for user '%s':\n\nID\tDomain\tIP\tUser Agent\tCreated At\tLast Activity At\tAuthentication Level\n", username)
+
+	for _, s := range sessions {
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.ID, s.Domain, s.IP, s.UserAgent,
+			s.CreatedAt.Format(time.RFC3339), s.LastActivityAt.Format(time.RFC3339), s.AuthenticationLevel)
+	}
+
+	return nil
+}
+
+// SessionsRevokeRunE is the RunE for the authelia sessions revoke command.
+func (ctx *CmdCtx) SessionsRevokeRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	username := args[0]
+
+	var id string
+
+	if id, err = cmd.Flags().GetString(cmdFlagNameID); err != nil {
+		return err
+	}
+
+	if id != "" {
+		if ctx.config.Session.Redis == nil {
+			return errSessionsMemoryProvider
+		}
+
+		if err = ctx.providers.SessionProvider.RevokeUserSession(username, id); err != nil {
+			return fmt.Errorf("failed to revoke the session '%s' of user '%s': %w", id, username, err)
+		}
+
+		fmt.Printf("Revoked the session '%s' of user '%s'\n", id, username)
+
+		return nil
+	}
+
+	if err = ctx.CheckSchemaVersion(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	if err = ctx.providers.StorageProvider.SaveUserSessionRevocation(ctx, username, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke the sessions of user '%s': %w", username, err)
+	}
+
+	// The sessions are only destroyed when they are stored in redis, the running instances reject the sessions which
+	// were authenticated before the revocation either way.
+	if ctx.config.Session.Redis == nil {
+		fmt.Printf("Revoked all sessions of user '%s'\n", username)
+
+		return nil
+	}
+
+	var revoked int
+
+	if revoked, err = ctx.providers.SessionProvider.RevokeUserSessions(username, ""); err != nil {
+		return fmt.Errorf("failed to destroy the sessions of user '%s': %w", username, err)
+	}
+
+	fmt.Printf("Revoked all sessions of user '%s', %d indexed sessions were destroyed\n", username, revoked)
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestSessionsShouldFailWithMemoryProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mocks.NewMockStorage(ctrl)
+
+	storage.EXPECT().Close().Return(nil).Times(2)
+
+	ctx := NewCmdCtx()
+	ctx.providers.StorageProvider = storage
+
+	assert.EqualError(t, ctx.SessionsListRunE(newSessionsListCmd(ctx), []string{"john"}), "the sessions of the running instances are only visible when the redis session provider is configured")
+
+	cmd := newSessionsRevokeCmd(ctx)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--id", "abc"}))
+	assert.EqualError(t, ctx.SessionsRevokeRunE(cmd, []string{"john"}), "the sessions of the running instances are only visible when the redis session provider is configured")
+}
@@ -118,6 +118,7 @@ func newStorageUserCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	}
 
 	cmd.AddCommand(
+		newStorageUserAccountsCmd(ctx),
 		newStorageUserIdentifiersCmd(ctx),
 		newStorageUserTOTPCmd(ctx),
 		newStorageUserWebAuthnCmd(ctx),
@@ -126,6 +127,78 @@ func newStorageUserCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	return cmd
 }
 
+func newStorageUserAccountsCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "accounts",
+		Short:   cmdAutheliaStorageUserAccountsShort,
+		Long:    cmdAutheliaStorageUserAccountsLong,
+		Example: cmdAutheliaStorageUserAccountsExample,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newStorageUserAccountsListCmd(ctx),
+		newStorageUserAccountsAddCmd(ctx),
+		newStorageUserAccountsDeleteCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newStorageUserAccountsListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list",
+		Short:   cmdAutheliaStorageUserAccountsListShort,
+		Long:    cmdAutheliaStorageUserAccountsListLong,
+		Example: cmdAutheliaStorageUserAccountsListExample,
+		Args:    cobra.NoArgs,
+		RunE:    ctx.StorageUserAccountsListRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newStorageUserAccountsAddCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "add <username>",
+		Short:   cmdAutheliaStorageUserAccountsAddShort,
+		Long:    cmdAutheliaStorageUserAccountsAddLong,
+		Example: cmdAutheliaStorageUserAccountsAddExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.StorageUserAccountsAddRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameDisplayName, "", "the display name of the user, defaults to the username")
+	cmd.Flags().String(cmdFlagNameEmail, "", "the email address of the user")
+	cmd.Flags().StringSlice(cmdFlagNameGroup, nil, "a group the user is a member of, can be specified multiple times")
+	cmd.Flags().Bool(cmdFlagNameDisabled, false, "adds the user in a disabled state")
+
+	cmdFlagPassword(cmd, true)
+	cmdFlagRandomPassword(cmd)
+
+	return cmd
+}
+
+func newStorageUserAccountsDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "delete <username>",
+		Short:   cmdAutheliaStorageUserAccountsDeleteShort,
+		Long:    cmdAutheliaStorageUserAccountsDeleteLong,
+		Example: cmdAutheliaStorageUserAccountsDeleteExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.StorageUserAccountsDeleteRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
 func newStorageUserIdentifiersCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:     "identifiers",
@@ -11,10 +11,13 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/go-crypt/crypt/algorithm"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/configuration/validator"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/storage"
@@ -938,3 +941,167 @@ func (ctx *CmdCtx) StorageUserIdentifiersAddRunE(cmd *cobra.Command, args []stri
 
 	return nil
 }
+
+// StorageUserAccountsListRunE is the RunE for the authelia storage user accounts list command.
+func (ctx *CmdCtx) StorageUserAccountsListRunE(_ *cobra.Command, _ []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchemaVersion(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var users []model.User
+
+	limit := 100
+
+	fmt.Printf("Users:\n\nUsername\tDisplay Name\tEmail\tGroups\tDisabled\n")
+
+	for page := 0; true; page++ {
+		if users, err = ctx.providers.StorageProvider.LoadUsers(ctx, limit, page); err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+
+		for _, user := range users {
+			fmt.Printf("%s\t%s\t%s\t%s\t%t\n", user.Username, user.DisplayName, user.Email, strings.Join(user.Groups, ","), user.Disabled)
+		}
+
+		if len(users) < limit {
+			break
+		}
+	}
+
+	return nil
+}
+
+// StorageUserAccountsAddRunE is the RunE for the authelia storage user accounts add command.
+func (ctx *CmdCtx) StorageUserAccountsAddRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	var (
+		user     model.User
+		digest   algorithm.Digest
+		password string
+		random   bool
+	)
+
+	if err = usersValidateUsername(args[0]); err != nil {
+		return err
+	}
+
+	if user.DisplayName, err = cmd.Flags().GetString(cmdFlagNameDisplayName); err != nil {
+		return err
+	}
+
+	if user.Email, err = cmd.Flags().GetString(cmdFlagNameEmail); err != nil {
+		return err
+	}
+
+	if user.Groups, err = cmd.Flags().GetStringSlice(cmdFlagNameGroup); err != nil {
+		return err
+	}
+
+	if user.Disabled, err = cmd.Flags().GetBool(cmdFlagNameDisabled); err != nil {
+		return err
+	}
+
+	if user.Email != "" {
+		if err = usersValidateEmail(user.Email); err != nil {
+			return err
+		}
+	}
+
+	if user.DisplayName == "" {
+		user.DisplayName = args[0]
+	}
+
+	user.Username = args[0]
+	user.Groups = usersGroupsAdd(nil, user.Groups)
+
+	if err = ctx.CheckSchemaVersion(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	switch _, err = ctx.providers.StorageProvider.LoadUser(ctx, user.Username); {
+	case err == nil:
+		return fmt.Errorf("user '%s' already exists", user.Username)
+	case !errors.Is(err, storage.ErrNoUser):
+		return fmt.Errorf("failed to check if user '%s' exists: %w", user.Username, err)
+	}
+
+	if password, random, err = cmdCryptoHashGetPassword(cmd, nil, false, true); err != nil {
+		return err
+	}
+
+	if digest, err = ctx.storageUserAccountsHashPassword(password); err != nil {
+		return err
+	}
+
+	user.Password = digest.Encode()
+
+	if err = ctx.providers.StorageProvider.SaveUser(ctx, user); err != nil {
+		return fmt.Errorf("failed to add user '%s': %w", user.Username, err)
+	}
+
+	if random {
+		fmt.Printf("Random Password: %s\n", password)
+	}
+
+	fmt.Printf("Added user '%s'.\n", user.Username)
+
+	return nil
+}
+
+// StorageUserAccountsDeleteRunE is the RunE for the authelia storage user accounts delete command.
+func (ctx *CmdCtx) StorageUserAccountsDeleteRunE(_ *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchemaVersion(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	if _, err = ctx.providers.StorageProvider.LoadUser(ctx, args[0]); err != nil {
+		return fmt.Errorf("error looking up user '%s': %w", args[0], err)
+	}
+
+	if err = ctx.providers.StorageProvider.DeleteUser(ctx, args[0]); err != nil {
+		return fmt.Errorf("failed to delete user '%s': %w", args[0], err)
+	}
+
+	fmt.Printf("Deleted user '%s'.\n", args[0])
+
+	return nil
+}
+
+func (ctx *CmdCtx) storageUserAccountsHashPassword(password string) (digest algorithm.Digest, err error) {
+	if len(password) == 0 {
+		return nil, fmt.Errorf("no password provided")
+	}
+
+	config := schema.DefaultPasswordConfig
+
+	if ctx.config.AuthenticationBackend.Storage != nil {
+		config = ctx.config.AuthenticationBackend.Storage.Password
+	}
+
+	val := &schema.StructValidator{}
+
+	validator.ValidatePasswordConfiguration(&config, val)
+
+	if errs := val.Errors(); len(errs) != 0 {
+		return nil, fmt.Errorf("errors occurred validating the password configuration: %w", errs[0])
+	}
+
+	var hash algorithm.Hash
+
+	if hash, err = authentication.NewFileCryptoHashFromConfig(config); err != nil {
+		return nil, err
+	}
+
+	return hash.Hash(password)
+}
@@ -0,0 +1,536 @@
+package commands
+
+import (
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-crypt/crypt/algorithm"
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func newUsersCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	defaults := map[string]any{
+		prefixFilePassword + ".algorithm":             schema.DefaultPasswordConfig.Algorithm,
+		prefixFilePassword + ".argon2.variant":        schema.DefaultPasswordConfig.Argon2.Variant,
+		prefixFilePassword + ".argon2.iterations":     schema.DefaultPasswordConfig.Argon2.Iterations,
+		prefixFilePassword + ".argon2.memory":         schema.DefaultPasswordConfig.Argon2.Memory,
+		prefixFilePassword + ".argon2.parallelism":    schema.DefaultPasswordConfig.Argon2.Parallelism,
+		prefixFilePassword + ".argon2.key_length":     schema.DefaultPasswordConfig.Argon2.KeyLength,
+		prefixFilePassword + ".argon2.salt_length":    schema.DefaultPasswordConfig.Argon2.SaltLength,
+		prefixFilePassword + ".sha2crypt.variant":     schema.DefaultPasswordConfig.SHA2Crypt.Variant,
+		prefixFilePassword + ".sha2crypt.iterations":  schema.DefaultPasswordConfig.SHA2Crypt.Iterations,
+		prefixFilePassword + ".sha2crypt.salt_length": schema.DefaultPasswordConfig.SHA2Crypt.SaltLength,
+		prefixFilePassword + ".pbkdf2.variant":        schema.DefaultPasswordConfig.PBKDF2.Variant,
+		prefixFilePassword + ".pbkdf2.iterations":     schema.DefaultPasswordConfig.PBKDF2.Iterations,
+		prefixFilePassword + ".pbkdf2.salt_length":    schema.DefaultPasswordConfig.PBKDF2.SaltLength,
+		prefixFilePassword + ".bcrypt.variant":        schema.DefaultPasswordConfig.BCrypt.Variant,
+		prefixFilePassword + ".bcrypt.cost":           schema.DefaultPasswordConfig.BCrypt.Cost,
+		prefixFilePassword + ".scrypt.iterations":     schema.DefaultPasswordConfig.SCrypt.Iterations,
+		prefixFilePassword + ".scrypt.block_size":     schema.DefaultPasswordConfig.SCrypt.BlockSize,
+		prefixFilePassword + ".scrypt.parallelism":    schema.DefaultPasswordConfig.SCrypt.Parallelism,
+		prefixFilePassword + ".scrypt.key_length":     schema.DefaultPasswordConfig.SCrypt.KeyLength,
+		prefixFilePassword + ".scrypt.salt_length":    schema.DefaultPasswordConfig.SCrypt.SaltLength,
+	}
+
+	cmd = &cobra.Command{
+		Use:     cmdUseUsers,
+		Short:   cmdAutheliaUsersShort,
+		Long:    cmdAutheliaUsersLong,
+		Example: cmdAutheliaUsersExample,
+		Args:    cobra.NoArgs,
+		PersistentPreRunE: ctx.ChainRunE(
+			ctx.ConfigSetDefaultsRunE(defaults),
+			ctx.UsersMapFlagsPersistentPreRunE,
+			ctx.ConfigLoadRunE,
+			ctx.ConfigValidateSectionPasswordRunE,
+		),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.PersistentFlags().String(cmdFlagNamePath, "", "the path to the file authentication backend user database")
+
+	cmd.AddCommand(
+		newUsersListCmd(ctx),
+		newUsersAddCmd(ctx),
+		newUsersDeleteCmd(ctx),
+		newUsersPasswdCmd(ctx),
+		newUsersGroupsCmd(ctx),
+		newUsersDisableCmd(ctx),
+		newUsersEnableCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newUsersListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list",
+		Short:   cmdAutheliaUsersListShort,
+		Long:    cmdAutheliaUsersListLong,
+		Example: cmdAutheliaUsersListExample,
+		Args:    cobra.NoArgs,
+		RunE:    ctx.UsersListRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newUsersAddCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "add <username>",
+		Short:   cmdAutheliaUsersAddShort,
+		Long:    cmdAutheliaUsersAddLong,
+		Example: cmdAutheliaUsersAddExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.UsersAddRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameDisplayName, "", "the display name of the user, defaults to the username")
+	cmd.Flags().String(cmdFlagNameEmail, "", "the email address of the user")
+	cmd.Flags().StringSlice(cmdFlagNameGroup, nil, "a group the user is a member of, can be specified multiple times")
+	cmd.Flags().Bool(cmdFlagNameDisabled, false, "adds the user in a disabled state")
+
+	cmdFlagPassword(cmd, true)
+	cmdFlagRandomPassword(cmd)
+
+	return cmd
+}
+
+func newUsersDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "delete <username>",
+		Short:   cmdAutheliaUsersDeleteShort,
+		Long:    cmdAutheliaUsersDeleteLong,
+		Example: cmdAutheliaUsersDeleteExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.UsersDeleteRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newUsersPasswdCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "passwd <username>",
+		Short:   cmdAutheliaUsersPasswdShort,
+		Long:    cmdAutheliaUsersPasswdLong,
+		Example: cmdAutheliaUsersPasswdExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.UsersPasswdRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagPassword(cmd, true)
+	cmdFlagRandomPassword(cmd)
+
+	return cmd
+}
+
+func newUsersGroupsCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "groups",
+		Short:   cmdAutheliaUsersGroupsShort,
+		Long:    cmdAutheliaUsersGroupsLong,
+		Example: cmdAutheliaUsersGroupsExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:     "add <username> <group>...",
+			Short:   cmdAutheliaUsersGroupsAddShort,
+			Long:    cmdAutheliaUsersGroupsAddLong,
+			Example: cmdAutheliaUsersGroupsAddExample,
+			Args:    cobra.MinimumNArgs(2),
+			RunE:    ctx.UsersGroupsAddRunE,
+
+			DisableAutoGenTag: true,
+		},
+		&cobra.Command{
+			Use:     "remove <username> <group>...",
+			Short:   cmdAutheliaUsersGroupsRemoveShort,
+			Long:    cmdAutheliaUsersGroupsRemoveLong,
+			Example: cmdAutheliaUsersGroupsRemoveExample,
+			Args:    cobra.MinimumNArgs(2),
+			RunE:    ctx.UsersGroupsRemoveRunE,
+
+			DisableAutoGenTag: true,
+		},
+	)
+
+	return cmd
+}
+
+func newUsersDisableCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "disable <username>",
+		Short:   cmdAutheliaUsersDisableShort,
+		Long:    cmdAutheliaUsersDisableLong,
+		Example: cmdAutheliaUsersDisableExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.UsersDisableRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newUsersEnableCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "enable <username>",
+		Short:   cmdAutheliaUsersEnableShort,
+		Long:    cmdAutheliaUsersEnableLong,
+		Example: cmdAutheliaUsersEnableExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    ctx.UsersEnableRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+// UsersMapFlagsPersistentPreRunE is the RunE which configures the flags map configuration source for the
+// authelia users commands.
+func (ctx *CmdCtx) UsersMapFlagsPersistentPreRunE(cmd *cobra.Command, _ []string) (err error) {
+	flagsMap := map[string]string{
+		cmdFlagNamePath: "authentication_backend.file.path",
+	}
+
+	return ctx.ConfigSetFlagsMapRunE(cmd.Flags(), flagsMap, false, false)
+}
+
+// UsersListRunE is the RunE for the authelia users list command.
+func (ctx *CmdCtx) UsersListRunE(_ *cobra.Command, _ []string) (err error) {
+	var database *authentication.FileUserDatabase
+
+	if database, err = ctx.usersLoadDatabase(); err != nil {
+		return err
+	}
+
+	usernames := make([]string, 0, len(database.Users))
+
+	for username := range database.Users {
+		usernames = append(usernames, username)
+	}
+
+	sort.Strings(usernames)
+
+	fmt.Printf("Users:\n\nUsername\tDisplay Name\tEmail\tGroups\tDisabled\n")
+
+	for _, username := range usernames {
+		details := database.Users[username]
+
+		fmt.Printf("%s\t%s\t%s\t%s\t%t\n", username, details.DisplayName, details.Email, strings.Join(details.Groups, ","), details.Disabled)
+	}
+
+	return nil
+}
+
+// UsersAddRunE is the RunE for the authelia users add command.
+func (ctx *CmdCtx) UsersAddRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		database *authentication.FileUserDatabase
+		details  authentication.DatabaseUserDetails
+		password string
+		random   bool
+	)
+
+	if err = usersValidateUsername(args[0]); err != nil {
+		return err
+	}
+
+	if details.DisplayName, err = cmd.Flags().GetString(cmdFlagNameDisplayName); err != nil {
+		return err
+	}
+
+	if details.Email, err = cmd.Flags().GetString(cmdFlagNameEmail); err != nil {
+		return err
+	}
+
+	if details.Groups, err = cmd.Flags().GetStringSlice(cmdFlagNameGroup); err != nil {
+		return err
+	}
+
+	if details.Disabled, err = cmd.Flags().GetBool(cmdFlagNameDisabled); err != nil {
+		return err
+	}
+
+	if details.Email != "" {
+		if err = usersValidateEmail(details.Email); err != nil {
+			return err
+		}
+	}
+
+	if details.DisplayName == "" {
+		details.DisplayName = args[0]
+	}
+
+	details.Username = args[0]
+	details.Groups = usersGroupsAdd(nil, details.Groups)
+
+	if database, err = ctx.usersLoadDatabase(); err != nil {
+		return err
+	}
+
+	if _, ok := database.Users[details.Username]; ok {
+		return fmt.Errorf("user '%s' already exists", details.Username)
+	}
+
+	if password, random, err = cmdCryptoHashGetPassword(cmd, nil, false, true); err != nil {
+		return err
+	}
+
+	if details.Digest, err = ctx.usersHashPassword(password); err != nil {
+		return err
+	}
+
+	database.SetUserDetails(details.Username, &details)
+
+	if err = usersSaveDatabase(database); err != nil {
+		return err
+	}
+
+	if random {
+		fmt.Printf("Random Password: %s\n", password)
+	}
+
+	fmt.Printf("Added user '%s'.\n", details.Username)
+
+	return nil
+}
+
+// UsersDeleteRunE is the RunE for the authelia users delete command.
+func (ctx *CmdCtx) UsersDeleteRunE(_ *cobra.Command, args []string) (err error) {
+	var (
+		database *authentication.FileUserDatabase
+		details  authentication.DatabaseUserDetails
+	)
+
+	if database, details, err = ctx.usersLoadUser(args[0]); err != nil {
+		return err
+	}
+
+	database.DeleteUserDetails(details.Username)
+
+	if err = usersSaveDatabase(database); err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted user '%s'.\n", details.Username)
+
+	return nil
+}
+
+// UsersPasswdRunE is the RunE for the authelia users passwd command.
+func (ctx *CmdCtx) UsersPasswdRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		database *authentication.FileUserDatabase
+		details  authentication.DatabaseUserDetails
+		password string
+		random   bool
+	)
+
+	if database, details, err = ctx.usersLoadUser(args[0]); err != nil {
+		return err
+	}
+
+	if password, random, err = cmdCryptoHashGetPassword(cmd, nil, false, true); err != nil {
+		return err
+	}
+
+	if details.Digest, err = ctx.usersHashPassword(password); err != nil {
+		return err
+	}
+
+	database.SetUserDetails(details.Username, &details)
+
+	if err = usersSaveDatabase(database); err != nil {
+		return err
+	}
+
+	if random {
+		fmt.Printf("Random Password: %s\n", password)
+	}
+
+	fmt.Printf("Updated the password for user '%s'.\n", details.Username)
+
+	return nil
+}
+
+// UsersGroupsAddRunE is the RunE for the authelia users groups add command.
+func (ctx *CmdCtx) UsersGroupsAddRunE(_ *cobra.Command, args []string) (err error) {
+	return ctx.usersUpdateUser(args[0], func(details *authentication.DatabaseUserDetails) {
+		details.Groups = usersGroupsAdd(details.Groups, args[1:])
+	})
+}
+
+// UsersGroupsRemoveRunE is the RunE for the authelia users groups remove command.
+func (ctx *CmdCtx) UsersGroupsRemoveRunE(_ *cobra.Command, args []string) (err error) {
+	return ctx.usersUpdateUser(args[0], func(details *authentication.DatabaseUserDetails) {
+		details.Groups = usersGroupsRemove(details.Groups, args[1:])
+	})
+}
+
+// UsersDisableRunE is the RunE for the authelia users disable command.
+func (ctx *CmdCtx) UsersDisableRunE(_ *cobra.Command, args []string) (err error) {
+	return ctx.usersUpdateUser(args[0], func(details *authentication.DatabaseUserDetails) {
+		details.Disabled = true
+	})
+}
+
+// UsersEnableRunE is the RunE for the authelia users enable command.
+func (ctx *CmdCtx) UsersEnableRunE(_ *cobra.Command, args []string) (err error) {
+	return ctx.usersUpdateUser(args[0], func(details *authentication.DatabaseUserDetails) {
+		details.Disabled = false
+	})
+}
+
+func (ctx *CmdCtx) usersUpdateUser(username string, update func(details *authentication.DatabaseUserDetails)) (err error) {
+	var (
+		database *authentication.FileUserDatabase
+		details  authentication.DatabaseUserDetails
+	)
+
+	if database, details, err = ctx.usersLoadUser(username); err != nil {
+		return err
+	}
+
+	update(&details)
+
+	database.SetUserDetails(details.Username, &details)
+
+	if err = usersSaveDatabase(database); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated user '%s'.\n", details.Username)
+
+	return nil
+}
+
+func (ctx *CmdCtx) usersLoadUser(username string) (database *authentication.FileUserDatabase, details authentication.DatabaseUserDetails, err error) {
+	if database, err = ctx.usersLoadDatabase(); err != nil {
+		return nil, details, err
+	}
+
+	if details, err = database.GetUserDetails(username); err != nil {
+		return nil, details, fmt.Errorf("error looking up user '%s': %w", username, err)
+	}
+
+	return database, details, nil
+}
+
+func (ctx *CmdCtx) usersLoadDatabase() (database *authentication.FileUserDatabase, err error) {
+	config := ctx.config.AuthenticationBackend.File
+
+	if config == nil || config.Path == "" {
+		return nil, fmt.Errorf("the file authentication backend path must be configured either via the configuration or the --%s flag", cmdFlagNamePath)
+	}
+
+	database = authentication.NewFileUserDatabase(config.Path, config.Search.Email, config.Search.CaseInsensitive)
+
+	if err = database.Load(); err != nil {
+		return nil, err
+	}
+
+	return database, nil
+}
+
+func (ctx *CmdCtx) usersHashPassword(password string) (digest algorithm.Digest, err error) {
+	var hash algorithm.Hash
+
+	if len(password) == 0 {
+		return nil, fmt.Errorf("no password provided")
+	}
+
+	if hash, err = authentication.NewFileCryptoHashFromConfig(ctx.config.AuthenticationBackend.File.Password); err != nil {
+		return nil, err
+	}
+
+	return hash.Hash(password)
+}
+
+// usersSaveDatabase validates the database in the same manner as it's validated when loaded before saving it, ensuring
+// a change never results in a database which Authelia is unable to load.
+func usersSaveDatabase(database *authentication.FileUserDatabase) (err error) {
+	database.Lock()
+
+	err = database.LoadAliases()
+
+	database.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("error validating the changes to the authentication database: %w", err)
+	}
+
+	if err = database.Save(); err != nil {
+		return fmt.Errorf("error saving the authentication database: %w", err)
+	}
+
+	return nil
+}
+
+func usersValidateUsername(username string) (err error) {
+	if username == "" {
+		return fmt.Errorf("the username must not be empty")
+	}
+
+	if strings.IndexFunc(username, unicode.IsSpace) != -1 {
+		return fmt.Errorf("the username '%s' must not contain whitespace", username)
+	}
+
+	return nil
+}
+
+func usersValidateEmail(email string) (err error) {
+	var address *mail.Address
+
+	if address, err = mail.ParseAddress(email); err != nil || address.Address != email {
+		return fmt.Errorf("the email '%s' is not a valid email address", email)
+	}
+
+	return nil
+}
+
+func usersGroupsAdd(groups, add []string) []string {
+	for _, group := range add {
+		if group = strings.TrimSpace(group); group == "" || utils.IsStringInSlice(group, groups) {
+			continue
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+func usersGroupsRemove(groups, remove []string) (result []string) {
+	for _, group := range groups {
+		if utils.IsStringInSlice(group, remove) {
+			continue
+		}
+
+		result = append(result, group)
+	}
+
+	return result
+}
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type UsersCmdSuite struct {
+	suite.Suite
+
+	ctx  *CmdCtx
+	path string
+}
+
+func (s *UsersCmdSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "users_database.yml")
+
+	s.Require().NoError(os.WriteFile(s.path, []byte(`
+users:
+  john:
+    displayname: "John Doe"
+    password: "$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM"
+    email: john.doe@authelia.com
+    groups:
+      - admins
+      - dev
+`), 0600))
+
+	s.ctx = NewCmdCtx()
+	s.ctx.config.AuthenticationBackend.File = &schema.FileAuthenticationBackend{
+		Path:     s.path,
+		Password: schema.DefaultCIPasswordConfig,
+		Search: schema.FileSearchAuthenticationBackend{
+			Email: true,
+		},
+	}
+}
+
+func (s *UsersCmdSuite) TestShouldAddAndDeleteUsers() {
+	cmd := newUsersAddCmd(s.ctx)
+
+	s.Require().NoError(cmd.ParseFlags([]string{"--password", "pass", "--email", "harry@authelia.com", "--group", "dev", "--group", "dev", "--disabled"}))
+	s.Require().NoError(s.ctx.UsersAddRunE(cmd, []string{"harry"}))
+
+	database := authentication.NewFileUserDatabase(s.path, false, false)
+
+	s.Require().NoError(database.Load())
+
+	details, err := database.GetUserDetails("harry")
+
+	s.Require().NoError(err)
+	s.Equal("harry", details.DisplayName)
+	s.Equal("harry@authelia.com", details.Email)
+	s.Equal([]string{"dev"}, details.Groups)
+	s.True(details.Disabled)
+
+	valid, err := details.Digest.MatchAdvanced("pass")
+
+	s.Require().NoError(err)
+	s.True(valid)
+
+	s.EqualError(s.ctx.UsersAddRunE(cmd, []string{"harry"}), "user 'harry' already exists")
+
+	s.Require().NoError(s.ctx.UsersDeleteRunE(nil, []string{"harry"}))
+
+	database = authentication.NewFileUserDatabase(s.path, false, false)
+
+	s.Require().NoError(database.Load())
+
+	_, err = database.GetUserDetails("harry")
+	s.ErrorIs(err, authentication.ErrUserNotFound)
+
+	_, err = database.GetUserDetails("john")
+	s.NoError(err)
+}
+
+func (s *UsersCmdSuite) TestShouldNotSaveInvalidDatabase() {
+	before, err := os.ReadFile(s.path)
+
+	s.Require().NoError(err)
+
+	cmd := newUsersAddCmd(s.ctx)
+
+	s.Require().NoError(cmd.ParseFlags([]string{"--password", "pass", "--email", "john.doe@authelia.com"}))
+
+	err = s.ctx.UsersAddRunE(cmd, []string{"harry"})
+
+	s.Require().Error(err)
+	s.Regexp(`^error validating the changes to the authentication database: error loading authentication database: email 'john.doe@authelia.com' is configured for for more than one user`, err.Error())
+
+	after, err := os.ReadFile(s.path)
+
+	s.Require().NoError(err)
+	s.Equal(before, after)
+}
+
+func (s *UsersCmdSuite) TestShouldUpdateUsers() {
+	s.Require().NoError(s.ctx.UsersGroupsAddRunE(nil, []string{"john.doe@authelia.com", "admins", "ops"}))
+	s.Require().NoError(s.ctx.UsersGroupsRemoveRunE(nil, []string{"john", "dev", "missing"}))
+	s.Require().NoError(s.ctx.UsersDisableRunE(nil, []string{"john"}))
+
+	database := authentication.NewFileUserDatabase(s.path, false, false)
+
+	s.Require().NoError(database.Load())
+
+	details, err := database.GetUserDetails("john")
+
+	s.Require().NoError(err)
+	s.Equal([]string{"admins", "ops"}, details.Groups)
+	s.True(details.Disabled)
+
+	s.Require().NoError(s.ctx.UsersEnableRunE(nil, []string{"john"}))
+
+	s.Require().NoError(database.Load())
+
+	details, err = database.GetUserDetails("john")
+
+	s.Require().NoError(err)
+	s.False(details.Disabled)
+
+	s.EqualError(s.ctx.UsersDisableRunE(nil, []string{"fred"}), "error looking up user 'fred': user not found")
+}
+
+func TestRunUsersCmdSuite(t *testing.T) {
+	suite.Run(t, new(UsersCmdSuite))
+}
+
+func TestUsersShouldErrorWithoutPath(t *testing.T) {
+	ctx := NewCmdCtx()
+
+	assert.EqualError(t, ctx.UsersListRunE(nil, nil), "the file authentication backend path must be configured either via the configuration or the --path flag")
+}
+
+func TestUsersValidate(t *testing.T) {
+	assert.NoError(t, usersValidateUsername("john"))
+	assert.EqualError(t, usersValidateUsername(""), "the username must not be empty")
+	assert.EqualError(t, usersValidateUsername("john doe"), "the username 'john doe' must not contain whitespace")
+
+	assert.NoError(t, usersValidateEmail("john@example.com"))
+	assert.EqualError(t, usersValidateEmail("john"), "the email 'john' is not a valid email address")
+	assert.EqualError(t, usersValidateEmail("John <john@example.com>"), "the email 'John <john@example.com>' is not a valid email address")
+}
@@ -14,6 +14,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -359,3 +360,16 @@ func newHelpTopic(topic, short, body string) (cmd *cobra.Command) {
 
 	return cmd
 }
+
+// getPasswordConfig returns the password config of the authentication backend which hashes passwords, preferring the
+// file backend, then the storage backend, and otherwise the default password config.
+func getPasswordConfig(config *schema.AuthenticationBackend) schema.Password {
+	switch {
+	case config.File != nil:
+		return config.File.Password
+	case config.Storage != nil:
+		return config.Storage.Password
+	default:
+		return schema.DefaultPasswordConfig
+	}
+}
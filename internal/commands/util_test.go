@@ -10,6 +10,8 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
 
 func TestLoadXEnvCLIStringSliceValue(t *testing.T) {
@@ -181,3 +183,27 @@ func TestLoadXNormalizedPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPasswordConfig(t *testing.T) {
+	file := schema.DefaultCIPasswordConfig
+	file.Algorithm = "sha2crypt"
+
+	storage := schema.DefaultCIPasswordConfig
+	storage.Algorithm = "pbkdf2"
+
+	testCases := []struct {
+		name     string
+		have     schema.AuthenticationBackend
+		expected string
+	}{
+		{"ShouldUseFile", schema.AuthenticationBackend{File: &schema.FileAuthenticationBackend{Password: file}, Storage: &schema.StorageAuthenticationBackend{Password: storage}}, "sha2crypt"},
+		{"ShouldUseStorage", schema.AuthenticationBackend{LDAP: &schema.LDAPAuthenticationBackend{}, Storage: &schema.StorageAuthenticationBackend{Password: storage}}, "pbkdf2"},
+		{"ShouldUseDefault", schema.AuthenticationBackend{LDAP: &schema.LDAPAuthenticationBackend{}}, schema.DefaultPasswordConfig.Algorithm},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getPasswordConfig(&tc.have).Algorithm)
+		})
+	}
+}
@@ -2,10 +2,14 @@ package schema
 
 import (
 	"regexp"
+	"time"
 )
 
 // AccessControlConfiguration represents the configuration related to ACLs.
 type AccessControlConfiguration struct {
+	Path  string `koanf:"path"`
+	Watch bool   `koanf:"watch"`
+
 	DefaultPolicy string       `koanf:"default_policy"`
 	Networks      []ACLNetwork `koanf:"networks"`
 	Rules         []ACLRule    `koanf:"rules"`
@@ -19,17 +23,37 @@ type ACLNetwork struct {
 
 // ACLRule represents one ACL rule entry.
 type ACLRule struct {
-	Domains      []string         `koanf:"domain"`
-	DomainsRegex []regexp.Regexp  `koanf:"domain_regex"`
-	Policy       string           `koanf:"policy"`
-	Subjects     [][]string       `koanf:"subject"`
-	Networks     []string         `koanf:"networks"`
-	Resources    []regexp.Regexp  `koanf:"resources"`
-	Methods      []string         `koanf:"methods"`
-	Query        [][]ACLQueryRule `koanf:"query"`
+	Domains             []string         `koanf:"domain"`
+	DomainsRegex        []regexp.Regexp  `koanf:"domain_regex"`
+	Policy              string           `koanf:"policy"`
+	SecondFactorMethods []string         `koanf:"second_factor_methods"`
+	Subjects            [][]string       `koanf:"subject"`
+	Networks            []string         `koanf:"networks"`
+	Resources           []regexp.Regexp  `koanf:"resources"`
+	Methods             []string         `koanf:"methods"`
+	Query               [][]ACLQueryRule `koanf:"query"`
+	Headers             [][]ACLQueryRule `koanf:"headers"`
+	Schedule            ACLSchedule      `koanf:"schedule"`
+	Condition           string           `koanf:"condition"`
+
+	MaxAuthenticationAge ACLMaxAuthenticationAge `koanf:"max_authentication_age"`
+}
+
+// ACLMaxAuthenticationAge represents the maximum age of each authentication factor for an ACL rule.
+type ACLMaxAuthenticationAge struct {
+	FirstFactor  time.Duration `koanf:"first_factor"`
+	SecondFactor time.Duration `koanf:"second_factor"`
+}
+
+// ACLSchedule represents the ACL schedule criteria.
+type ACLSchedule struct {
+	Timezone string   `koanf:"timezone"`
+	Days     []string `koanf:"days"`
+	Times    []string `koanf:"times"`
+	Dates    []string `koanf:"dates"`
 }
 
-// ACLQueryRule represents the ACL query criteria.
+// ACLQueryRule represents the ACL query and headers criteria.
 type ACLQueryRule struct {
 	Operator string `koanf:"operator"`
 	Key      string `koanf:"key"`
@@ -8,12 +8,38 @@ import (
 
 // AuthenticationBackend represents the configuration related to the authentication backend.
 type AuthenticationBackend struct {
-	PasswordReset PasswordResetAuthenticationBackend `koanf:"password_reset"`
+	PasswordReset  PasswordResetAuthenticationBackend  `koanf:"password_reset"`
+	PasswordChange PasswordChangeAuthenticationBackend `koanf:"password_change"`
 
 	RefreshInterval string `koanf:"refresh_interval"`
 
-	File *FileAuthenticationBackend `koanf:"file"`
-	LDAP *LDAPAuthenticationBackend `koanf:"ldap"`
+	Chain []string `koanf:"chain"`
+
+	ExtraAttributes []AuthenticationBackendExtraAttribute `koanf:"extra_attributes"`
+
+	Cache AuthenticationBackendCache `koanf:"cache"`
+
+	File    *FileAuthenticationBackend    `koanf:"file"`
+	LDAP    *LDAPAuthenticationBackend    `koanf:"ldap"`
+	Storage *StorageAuthenticationBackend `koanf:"storage"`
+}
+
+// AuthenticationBackendExtraAttribute represents an additional user attribute retrieved from the authentication
+// backends which is carried into the session.
+type AuthenticationBackendExtraAttribute struct {
+	Name          string `koanf:"name"`
+	LDAPAttribute string `koanf:"ldap_attribute"`
+	Header        string `koanf:"header"`
+	Claim         string `koanf:"claim"`
+}
+
+// AuthenticationBackendCache represents the configuration related to caching the user details retrieved from the
+// authentication backends.
+type AuthenticationBackendCache struct {
+	Enable      bool          `koanf:"enable"`
+	TTL         time.Duration `koanf:"ttl"`
+	NegativeTTL time.Duration `koanf:"negative_ttl"`
+	MaxEntries  int           `koanf:"max_entries"`
 }
 
 // PasswordResetAuthenticationBackend represents the configuration related to password reset functionality.
@@ -22,6 +48,13 @@ type PasswordResetAuthenticationBackend struct {
 	CustomURL url.URL `koanf:"custom_url"`
 }
 
+// PasswordChangeAuthenticationBackend represents the configuration related to the change password functionality.
+type PasswordChangeAuthenticationBackend struct {
+	Disable             bool          `koanf:"disable"`
+	RequireSecondFactor bool          `koanf:"require_second_factor"`
+	SecondFactorMaxAge  time.Duration `koanf:"second_factor_max_age"`
+}
+
 // FileAuthenticationBackend represents the configuration related to file-based backend.
 type FileAuthenticationBackend struct {
 	Path     string   `koanf:"path"`
@@ -37,6 +70,18 @@ type FileSearchAuthenticationBackend struct {
 	CaseInsensitive bool `koanf:"case_insensitive"`
 }
 
+// StorageAuthenticationBackend represents the configuration related to the storage-based backend.
+type StorageAuthenticationBackend struct {
+	Password Password `koanf:"password"`
+
+	Search StorageSearchAuthenticationBackend `koanf:"search"`
+}
+
+// StorageSearchAuthenticationBackend represents the configuration related to storage-based backend searching.
+type StorageSearchAuthenticationBackend struct {
+	Email bool `koanf:"email"`
+}
+
 // Password represents the configuration related to password hashing.
 type Password struct {
 	Algorithm string `koanf:"algorithm"`
@@ -101,6 +146,8 @@ type LDAPAuthenticationBackend struct {
 	StartTLS       bool          `koanf:"start_tls"`
 	TLS            *TLSConfig    `koanf:"tls"`
 
+	Pooling LDAPAuthenticationBackendPooling `koanf:"pooling"`
+
 	BaseDN string `koanf:"base_dn"`
 
 	AdditionalUsersDN string `koanf:"additional_users_dn"`
@@ -109,6 +156,8 @@ type LDAPAuthenticationBackend struct {
 	AdditionalGroupsDN string `koanf:"additional_groups_dn"`
 	GroupsFilter       string `koanf:"groups_filter"`
 
+	NestedGroups LDAPAuthenticationBackendNestedGroups `koanf:"nested_groups"`
+
 	GroupNameAttribute   string `koanf:"group_name_attribute"`
 	UsernameAttribute    string `koanf:"username_attribute"`
 	MailAttribute        string `koanf:"mail_attribute"`
@@ -122,6 +171,23 @@ type LDAPAuthenticationBackend struct {
 	Password string `koanf:"password"`
 }
 
+// LDAPAuthenticationBackendPooling represents the configuration related to LDAP server connection pooling.
+type LDAPAuthenticationBackendPooling struct {
+	Enable              bool          `koanf:"enable"`
+	Count               int           `koanf:"count"`
+	Timeout             time.Duration `koanf:"timeout"`
+	IdleTimeout         time.Duration `koanf:"idle_timeout"`
+	MaxLifetime         time.Duration `koanf:"max_lifetime"`
+	HealthCheckInterval time.Duration `koanf:"health_check_interval"`
+}
+
+// LDAPAuthenticationBackendNestedGroups represents the configuration related to LDAP nested group resolution.
+type LDAPAuthenticationBackendNestedGroups struct {
+	Enable   bool   `koanf:"enable"`
+	Method   string `koanf:"method"`
+	MaxDepth int    `koanf:"max_depth"`
+}
+
 // DefaultPasswordConfig represents the default configuration related to Argon2id hashing.
 var DefaultPasswordConfig = Password{
 	Algorithm: argon2,
@@ -173,6 +239,33 @@ var DefaultCIPasswordConfig = Password{
 	},
 }
 
+// DefaultPasswordChangeAuthenticationBackend represents the default change password config.
+var DefaultPasswordChangeAuthenticationBackend = PasswordChangeAuthenticationBackend{
+	SecondFactorMaxAge: time.Minute * 5,
+}
+
+// DefaultAuthenticationBackendCache represents the default user details cache config.
+var DefaultAuthenticationBackendCache = AuthenticationBackendCache{
+	TTL:         time.Minute,
+	NegativeTTL: time.Second * 30,
+	MaxEntries:  1000,
+}
+
+// DefaultLDAPAuthenticationBackendPooling represents the default LDAP connection pooling config.
+var DefaultLDAPAuthenticationBackendPooling = LDAPAuthenticationBackendPooling{
+	Count:               5,
+	Timeout:             time.Second * 10,
+	IdleTimeout:         time.Minute * 5,
+	MaxLifetime:         time.Hour,
+	HealthCheckInterval: time.Second * 30,
+}
+
+// DefaultLDAPAuthenticationBackendNestedGroups represents the default LDAP nested group resolution config.
+var DefaultLDAPAuthenticationBackendNestedGroups = LDAPAuthenticationBackendNestedGroups{
+	Method:   LDAPNestedGroupsMethodMember,
+	MaxDepth: 5,
+}
+
 // DefaultLDAPAuthenticationBackendConfigurationImplementationCustom represents the default LDAP config.
 var DefaultLDAPAuthenticationBackendConfigurationImplementationCustom = LDAPAuthenticationBackend{
 	UsernameAttribute:    ldapAttrUserID,
@@ -191,7 +284,7 @@ var DefaultLDAPAuthenticationBackendConfigurationImplementationActiveDirectory =
 	UsernameAttribute:    "sAMAccountName",
 	MailAttribute:        ldapAttrMail,
 	DisplayNameAttribute: ldapAttrDisplayName,
-	GroupsFilter:         "(&(member={dn})(|(sAMAccountType=268435456)(sAMAccountType=536870912)))",
+	GroupsFilter:         "(&(member{in_chain}={dn})(|(sAMAccountType=268435456)(sAMAccountType=536870912)))",
 	GroupNameAttribute:   ldapAttrCommonName,
 	Timeout:              time.Second * 5,
 	TLS: &TLSConfig{
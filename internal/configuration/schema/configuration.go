@@ -15,6 +15,7 @@ type Configuration struct {
 	TOTP                  TOTPConfiguration              `koanf:"totp"`
 	DuoAPI                DuoAPIConfiguration            `koanf:"duo_api"`
 	AccessControl         AccessControlConfiguration     `koanf:"access_control"`
+	GeoIP                 GeoIPConfiguration             `koanf:"geoip"`
 	NTP                   NTPConfiguration               `koanf:"ntp"`
 	Regulation            RegulationConfiguration        `koanf:"regulation"`
 	Storage               StorageConfiguration           `koanf:"storage"`
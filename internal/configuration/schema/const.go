@@ -58,6 +58,17 @@ const (
 	RefreshIntervalAlways = 0 * time.Millisecond
 )
 
+const (
+	// AuthenticationBackendFile is the string for the file authentication backend.
+	AuthenticationBackendFile = "file"
+
+	// AuthenticationBackendLDAP is the string for the LDAP authentication backend.
+	AuthenticationBackendLDAP = "ldap"
+
+	// AuthenticationBackendStorage is the string for the storage authentication backend.
+	AuthenticationBackendStorage = "storage"
+)
+
 const (
 	// LDAPImplementationCustom is the string for the custom LDAP implementation.
 	LDAPImplementationCustom = "custom"
@@ -75,6 +86,20 @@ const (
 	LDAPImplementationGLAuth = "glauth"
 )
 
+const (
+	// LDAPNestedGroupsMethodInChain is the string for the nested groups method which uses the
+	// LDAP_MATCHING_RULE_IN_CHAIN matching rule supported by Active Directory.
+	LDAPNestedGroupsMethodInChain = "in_chain"
+
+	// LDAPNestedGroupsMethodMember is the string for the nested groups method which iteratively searches for the
+	// groups which have each group as a member using the groups filter.
+	LDAPNestedGroupsMethodMember = "member"
+
+	// LDAPNestedGroupsMethodMemberOf is the string for the nested groups method which iteratively follows the memberOf
+	// attribute of each group.
+	LDAPNestedGroupsMethodMemberOf = "member_of"
+)
+
 // TOTP Algorithm.
 const (
 	TOTPAlgorithmSHA1   = "SHA1"
@@ -0,0 +1,7 @@
+package schema
+
+// GeoIPConfiguration represents the configuration related to the GeoIP databases.
+type GeoIPConfiguration struct {
+	CountryDatabase string `koanf:"country_database"`
+	ASNDatabase     string `koanf:"asn_database"`
+}
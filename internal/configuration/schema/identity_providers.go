@@ -59,7 +59,8 @@ type OpenIDConnectClientConfiguration struct {
 
 	UserinfoSigningAlgorithm string `koanf:"userinfo_signing_algorithm"`
 
-	Policy string `koanf:"authorization_policy"`
+	Policy              string   `koanf:"authorization_policy"`
+	SecondFactorMethods []string `koanf:"second_factor_methods"`
 
 	ConsentMode                  string         `koanf:"consent_mode"`
 	ConsentPreConfiguredDuration *time.Duration `koanf:"pre_configured_consent_duration"`
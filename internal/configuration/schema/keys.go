@@ -45,11 +45,25 @@ var Keys = []string{
 	"identity_providers.oidc.clients[].response_modes",
 	"identity_providers.oidc.clients[].userinfo_signing_algorithm",
 	"identity_providers.oidc.clients[].authorization_policy",
+	"identity_providers.oidc.clients[].second_factor_methods",
 	"identity_providers.oidc.clients[].consent_mode",
 	"identity_providers.oidc.clients[].pre_configured_consent_duration",
 	"authentication_backend.password_reset.disable",
 	"authentication_backend.password_reset.custom_url",
+	"authentication_backend.password_change.disable",
+	"authentication_backend.password_change.require_second_factor",
+	"authentication_backend.password_change.second_factor_max_age",
 	"authentication_backend.refresh_interval",
+	"authentication_backend.chain",
+	"authentication_backend.extra_attributes",
+	"authentication_backend.extra_attributes[].name",
+	"authentication_backend.extra_attributes[].ldap_attribute",
+	"authentication_backend.extra_attributes[].header",
+	"authentication_backend.extra_attributes[].claim",
+	"authentication_backend.cache.enable",
+	"authentication_backend.cache.ttl",
+	"authentication_backend.cache.negative_ttl",
+	"authentication_backend.cache.max_entries",
 	"authentication_backend.file.path",
 	"authentication_backend.file.watch",
 	"authentication_backend.file.password.algorithm",
@@ -89,11 +103,20 @@ var Keys = []string{
 	"authentication_backend.ldap.tls.server_name",
 	"authentication_backend.ldap.tls.private_key",
 	"authentication_backend.ldap.tls.certificate_chain",
+	"authentication_backend.ldap.pooling.enable",
+	"authentication_backend.ldap.pooling.count",
+	"authentication_backend.ldap.pooling.timeout",
+	"authentication_backend.ldap.pooling.idle_timeout",
+	"authentication_backend.ldap.pooling.max_lifetime",
+	"authentication_backend.ldap.pooling.health_check_interval",
 	"authentication_backend.ldap.base_dn",
 	"authentication_backend.ldap.additional_users_dn",
 	"authentication_backend.ldap.users_filter",
 	"authentication_backend.ldap.additional_groups_dn",
 	"authentication_backend.ldap.groups_filter",
+	"authentication_backend.ldap.nested_groups.enable",
+	"authentication_backend.ldap.nested_groups.method",
+	"authentication_backend.ldap.nested_groups.max_depth",
 	"authentication_backend.ldap.group_name_attribute",
 	"authentication_backend.ldap.username_attribute",
 	"authentication_backend.ldap.mail_attribute",
@@ -103,6 +126,32 @@ var Keys = []string{
 	"authentication_backend.ldap.permit_feature_detection_failure",
 	"authentication_backend.ldap.user",
 	"authentication_backend.ldap.password",
+	"authentication_backend.storage.password.algorithm",
+	"authentication_backend.storage.password.argon2.variant",
+	"authentication_backend.storage.password.argon2.iterations",
+	"authentication_backend.storage.password.argon2.memory",
+	"authentication_backend.storage.password.argon2.parallelism",
+	"authentication_backend.storage.password.argon2.key_length",
+	"authentication_backend.storage.password.argon2.salt_length",
+	"authentication_backend.storage.password.sha2crypt.variant",
+	"authentication_backend.storage.password.sha2crypt.iterations",
+	"authentication_backend.storage.password.sha2crypt.salt_length",
+	"authentication_backend.storage.password.pbkdf2.variant",
+	"authentication_backend.storage.password.pbkdf2.iterations",
+	"authentication_backend.storage.password.pbkdf2.salt_length",
+	"authentication_backend.storage.password.bcrypt.variant",
+	"authentication_backend.storage.password.bcrypt.cost",
+	"authentication_backend.storage.password.scrypt.iterations",
+	"authentication_backend.storage.password.scrypt.block_size",
+	"authentication_backend.storage.password.scrypt.parallelism",
+	"authentication_backend.storage.password.scrypt.key_length",
+	"authentication_backend.storage.password.scrypt.salt_length",
+	"authentication_backend.storage.password.iterations",
+	"authentication_backend.storage.password.memory",
+	"authentication_backend.storage.password.parallelism",
+	"authentication_backend.storage.password.key_length",
+	"authentication_backend.storage.password.salt_length",
+	"authentication_backend.storage.search.email",
 	"session.name",
 	"session.domain",
 	"session.same_site",
@@ -110,6 +159,14 @@ var Keys = []string{
 	"session.expiration",
 	"session.inactivity",
 	"session.remember_me_duration",
+	"session.cookies",
+	"session.cookies[].domain",
+	"session.cookies[].authelia_url",
+	"session.cookies[].name",
+	"session.cookies[].same_site",
+	"session.cookies[].expiration",
+	"session.cookies[].inactivity",
+	"session.cookies[].remember_me_duration",
 	"session.redis.host",
 	"session.redis.port",
 	"session.redis.username",
@@ -143,6 +200,8 @@ var Keys = []string{
 	"duo_api.integration_key",
 	"duo_api.secret_key",
 	"duo_api.enable_self_enrollment",
+	"access_control.path",
+	"access_control.watch",
 	"access_control.default_policy",
 	"access_control.networks",
 	"access_control.networks[].name",
@@ -151,6 +210,7 @@ var Keys = []string{
 	"access_control.rules[].domain",
 	"access_control.rules[].domain_regex",
 	"access_control.rules[].policy",
+	"access_control.rules[].second_factor_methods",
 	"access_control.rules[].subject",
 	"access_control.rules[].networks",
 	"access_control.rules[].resources",
@@ -159,6 +219,19 @@ var Keys = []string{
 	"access_control.rules[].query[][].key",
 	"access_control.rules[].query[][].value",
 	"access_control.rules[].query",
+	"access_control.rules[].headers[][].operator",
+	"access_control.rules[].headers[][].key",
+	"access_control.rules[].headers[][].value",
+	"access_control.rules[].headers",
+	"access_control.rules[].schedule.timezone",
+	"access_control.rules[].schedule.days",
+	"access_control.rules[].schedule.times",
+	"access_control.rules[].schedule.dates",
+	"access_control.rules[].condition",
+	"access_control.rules[].max_authentication_age.first_factor",
+	"access_control.rules[].max_authentication_age.second_factor",
+	"geoip.country_database",
+	"geoip.asn_database",
 	"ntp.address",
 	"ntp.version",
 	"ntp.max_desync",
@@ -235,6 +308,10 @@ var Keys = []string{
 	"server.timeouts.read",
 	"server.timeouts.write",
 	"server.timeouts.idle",
+	"server.endpoints.authz_decide.clients",
+	"server.endpoints.authz_decide.clients[].id",
+	"server.endpoints.authz_decide.clients[].secret",
+	"server.endpoints.authz_decide.clients[].resolve_users",
 	"telemetry.metrics.enabled",
 	"telemetry.metrics.address",
 	"telemetry.metrics.buffers.read",
@@ -256,4 +333,11 @@ var Keys = []string{
 	"password_policy.standard.require_special",
 	"password_policy.zxcvbn.enabled",
 	"password_policy.zxcvbn.min_score",
+	"password_policy.history.enabled",
+	"password_policy.history.count",
+	"password_policy.breached.enabled",
+	"password_policy.breached.path",
+	"password_policy.breached.url",
+	"password_policy.breached.timeout",
+	"password_policy.breached.threshold",
 }
@@ -1,5 +1,10 @@
 package schema
 
+import (
+	"net/url"
+	"time"
+)
+
 // PasswordPolicyStandardParams represents the configuration related to standard parameters of password policy.
 type PasswordPolicyStandardParams struct {
 	Enabled          bool `koanf:"enabled"`
@@ -17,10 +22,28 @@ type PasswordPolicyZXCVBNParams struct {
 	MinScore int  `koanf:"min_score"`
 }
 
+// PasswordPolicyHistoryParams represents the configuration related to the password history of the password policy.
+type PasswordPolicyHistoryParams struct {
+	Enabled bool `koanf:"enabled"`
+	Count   int  `koanf:"count"`
+}
+
+// PasswordPolicyBreachedParams represents the configuration related to the breached password check of the password
+// policy.
+type PasswordPolicyBreachedParams struct {
+	Enabled   bool          `koanf:"enabled"`
+	Path      string        `koanf:"path"`
+	URL       url.URL       `koanf:"url"`
+	Timeout   time.Duration `koanf:"timeout"`
+	Threshold int           `koanf:"threshold"`
+}
+
 // PasswordPolicyConfiguration represents the configuration related to password policy.
 type PasswordPolicyConfiguration struct {
 	Standard PasswordPolicyStandardParams `koanf:"standard"`
 	ZXCVBN   PasswordPolicyZXCVBNParams   `koanf:"zxcvbn"`
+	History  PasswordPolicyHistoryParams  `koanf:"history"`
+	Breached PasswordPolicyBreachedParams `koanf:"breached"`
 }
 
 // DefaultPasswordPolicyConfiguration is the default password policy configuration.
@@ -34,4 +57,14 @@ var DefaultPasswordPolicyConfiguration = PasswordPolicyConfiguration{
 		Enabled:  false,
 		MinScore: 3,
 	},
+	History: PasswordPolicyHistoryParams{
+		Enabled: false,
+		Count:   5,
+	},
+	Breached: PasswordPolicyBreachedParams{
+		Enabled:   false,
+		URL:       url.URL{Scheme: "https", Host: "api.pwnedpasswords.com"},
+		Timeout:   time.Second * 5,
+		Threshold: 1,
+	},
 }
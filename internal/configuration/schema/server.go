@@ -19,6 +19,26 @@ type ServerConfiguration struct {
 
 	Buffers  ServerBuffers  `koanf:"buffers"`
 	Timeouts ServerTimeouts `koanf:"timeouts"`
+
+	Endpoints ServerEndpoints `koanf:"endpoints"`
+}
+
+// ServerEndpoints represents the configuration of the optional http server endpoints.
+type ServerEndpoints struct {
+	AuthzDecide ServerEndpointsAuthzDecide `koanf:"authz_decide"`
+}
+
+// ServerEndpointsAuthzDecide represents the configuration of the policy decision endpoint. The endpoint is only
+// enabled when at least one client is configured.
+type ServerEndpointsAuthzDecide struct {
+	Clients []ServerEndpointsAuthzDecideClient `koanf:"clients"`
+}
+
+// ServerEndpointsAuthzDecideClient represents a client which is permitted to use the policy decision endpoint.
+type ServerEndpointsAuthzDecideClient struct {
+	ID           string          `koanf:"id"`
+	Secret       *PasswordDigest `koanf:"secret"`
+	ResolveUsers bool            `koanf:"resolve_users"`
 }
 
 // ServerTLSConfiguration represents the configuration of the http servers TLS options.
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"crypto/tls"
+	"net/url"
 	"time"
 )
 
@@ -44,9 +45,24 @@ type SessionConfiguration struct {
 	Inactivity         time.Duration `koanf:"inactivity"`
 	RememberMeDuration time.Duration `koanf:"remember_me_duration"`
 
+	Cookies []SessionCookieConfiguration `koanf:"cookies"`
+
 	Redis *RedisSessionConfiguration `koanf:"redis"`
 }
 
+// SessionCookieConfiguration represents the configuration of the session cookie for a single protected domain. The
+// name, same site, expiration, inactivity, and remember me duration options default to the values of the
+// SessionConfiguration.
+type SessionCookieConfiguration struct {
+	Domain             string        `koanf:"domain"`
+	AutheliaURL        url.URL       `koanf:"authelia_url"`
+	Name               string        `koanf:"name"`
+	SameSite           string        `koanf:"same_site"`
+	Expiration         time.Duration `koanf:"expiration"`
+	Inactivity         time.Duration `koanf:"inactivity"`
+	RememberMeDuration time.Duration `koanf:"remember_me_duration"`
+}
+
 // DefaultSessionConfiguration is the default session configuration.
 var DefaultSessionConfiguration = SessionConfiguration{
 	Name:               "authelia_session",
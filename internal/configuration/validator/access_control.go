@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
@@ -18,7 +19,33 @@ func IsPolicyValid(policy string) (isValid bool) {
 
 // IsSubjectValid check if a subject is valid.
 func IsSubjectValid(subject string) (isValid bool) {
-	return subject == "" || strings.HasPrefix(subject, "user:") || strings.HasPrefix(subject, "group:")
+	if subject == "" {
+		return true
+	}
+
+	for _, prefix := range validACLRuleSubjectPrefixes {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSubjectAttributeValid check if an attribute subject is valid given the configured extra attributes.
+func IsSubjectAttributeValid(config schema.AuthenticationBackend, subject string) (isValid bool) {
+	name, _, ok := strings.Cut(strings.TrimPrefix(subject, "attribute:"), "=")
+	if !ok {
+		return false
+	}
+
+	for _, attribute := range config.ExtraAttributes {
+		if attribute.Name == strings.TrimSpace(name) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // IsNetworkGroupValid check if a network group is valid.
@@ -65,7 +92,14 @@ func ValidateAccessControl(config *schema.Configuration, validator *schema.Struc
 	if config.AccessControl.Networks != nil {
 		for _, n := range config.AccessControl.Networks {
 			for _, networks := range n.Networks {
-				if !IsNetworkValid(networks) {
+				switch geo, err := authorization.NewAccessControlGeoNetwork(networks, nil); {
+				case err != nil:
+					validator.Push(fmt.Errorf(errFmtAccessControlNetworkGroupGeoIPInvalid, n.Name, networks, err))
+				case geo != nil:
+					if option := geoIPDatabaseOptionMissing(config, geo); option != "" {
+						validator.Push(fmt.Errorf(errFmtAccessControlNetworkGroupGeoIPDatabase, n.Name, networks, option))
+					}
+				case !IsNetworkValid(networks):
 					validator.Push(fmt.Errorf(errFmtAccessControlNetworkGroupIPCIDRInvalid, n.Name, networks))
 				}
 			}
@@ -73,6 +107,36 @@ func ValidateAccessControl(config *schema.Configuration, validator *schema.Struc
 	}
 }
 
+// ValidateAccessControlPath validates the access control configuration can be loaded from the file at the path option,
+// returning false if it can't.
+func ValidateAccessControlPath(config *schema.AccessControlConfiguration, validator *schema.StructValidator) (ok bool) {
+	if config.DefaultPolicy != "" || len(config.Networks) != 0 || len(config.Rules) != 0 {
+		validator.Push(errors.New(errAccessControlPathWithOptions))
+
+		return false
+	}
+
+	return true
+}
+
+// ValidateAccessControlPathKeys validates the keys loaded from the file at the path option. The file must only contain
+// the access_control key and the keys within it are validated in the same way as the configuration.
+func ValidateAccessControlPathKeys(path string, keys []string, prefix string, validator *schema.StructValidator) {
+	var expected []string
+
+	for _, key := range keys {
+		if key != "access_control" && !strings.HasPrefix(key, "access_control.") {
+			validator.Push(fmt.Errorf(errFmtAccessControlPathKeyNotExpected, path, key))
+
+			continue
+		}
+
+		expected = append(expected, key)
+	}
+
+	ValidateKeys(expected, prefix, validator)
+}
+
 // ValidateRules validates an ACL Rule configuration.
 func ValidateRules(config *schema.Configuration, validator *schema.StructValidator) {
 	if config.AccessControl.Rules == nil || len(config.AccessControl.Rules) == 0 {
@@ -96,14 +160,24 @@ func ValidateRules(config *schema.Configuration, validator *schema.StructValidat
 			validator.Push(fmt.Errorf(errFmtAccessControlRuleInvalidPolicy, ruleDescriptor(rulePosition, rule), rule.Policy))
 		}
 
-		validateNetworks(rulePosition, rule, config.AccessControl, validator)
+		validateNetworks(rulePosition, rule, config, validator)
 
-		validateSubjects(rulePosition, rule, validator)
+		validateSubjects(rulePosition, rule, config, validator)
 
 		validateMethods(rulePosition, rule, validator)
 
 		validateQuery(i, rule, config, validator)
 
+		validateHeaders(i, rule, config, validator)
+
+		validateSchedule(rulePosition, rule, validator)
+
+		validateCondition(rulePosition, rule, validator)
+
+		validateSecondFactorMethods(rulePosition, rule, validator)
+
+		validateMaxAuthenticationAge(rulePosition, rule, validator)
+
 		if rule.Policy == policyBypass {
 			validateBypass(rulePosition, rule, validator)
 		}
@@ -135,21 +209,53 @@ func validateDomains(rulePosition int, rule schema.ACLRule, validator *schema.St
 	}
 }
 
-func validateNetworks(rulePosition int, rule schema.ACLRule, config schema.AccessControlConfiguration, validator *schema.StructValidator) {
+func validateNetworks(rulePosition int, rule schema.ACLRule, config *schema.Configuration, validator *schema.StructValidator) {
 	for _, network := range rule.Networks {
-		if !IsNetworkValid(network) {
-			if !IsNetworkGroupValid(config, network) {
-				validator.Push(fmt.Errorf(errFmtAccessControlRuleNetworksInvalid, ruleDescriptor(rulePosition, rule), network))
+		switch geo, err := authorization.NewAccessControlGeoNetwork(network, nil); {
+		case err != nil:
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleNetworksGeoIPInvalid, ruleDescriptor(rulePosition, rule), network, err))
+		case geo != nil:
+			if option := geoIPDatabaseOptionMissing(config, geo); option != "" {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleNetworksGeoIPDatabase, ruleDescriptor(rulePosition, rule), network, option))
 			}
+		case !IsNetworkValid(network) && !IsNetworkGroupValid(config.AccessControl, network):
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleNetworksInvalid, ruleDescriptor(rulePosition, rule), network))
 		}
 	}
 }
 
-func validateSubjects(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+// geoIPDatabaseOptionMissing returns the name of the geoip option required by the network if it's not configured.
+func geoIPDatabaseOptionMissing(config *schema.Configuration, network *authorization.AccessControlGeoNetwork) (option string) {
+	switch {
+	case network.Country != "" && config.GeoIP.CountryDatabase == "":
+		return "country_database"
+	case network.ASN != 0 && config.GeoIP.ASNDatabase == "":
+		return "asn_database"
+	default:
+		return ""
+	}
+}
+
+func validateSubjects(rulePosition int, rule schema.ACLRule, config *schema.Configuration, validator *schema.StructValidator) {
 	for _, subjectRule := range rule.Subjects {
 		for _, subject := range subjectRule {
-			if !IsSubjectValid(subject) {
+			switch {
+			case !IsSubjectValid(subject):
 				validator.Push(fmt.Errorf(errFmtAccessControlRuleSubjectInvalid, ruleDescriptor(rulePosition, rule), subject))
+			case strings.HasPrefix(subject, "attribute:"):
+				if !IsSubjectAttributeValid(config.AuthenticationBackend, subject) {
+					validator.Push(fmt.Errorf(errFmtAccessControlRuleSubjectAttributeInvalid, ruleDescriptor(rulePosition, rule), subject))
+				}
+			case strings.HasPrefix(subject, "user-regex:"), strings.HasPrefix(subject, "group-regex:"), strings.HasPrefix(subject, "email-regex:"):
+				_, value, _ := strings.Cut(subject, ":")
+
+				if _, err := authorization.NewSubjectPattern(strings.TrimSpace(value), false); err != nil {
+					validator.Push(fmt.Errorf(errFmtAccessControlRuleSubjectPatternInvalid, ruleDescriptor(rulePosition, rule), subject, err))
+				}
+			case strings.HasPrefix(subject, "user:"), strings.HasPrefix(subject, "group:"), strings.HasPrefix(subject, "email:"):
+				if strings.ContainsAny(subject, "*?") {
+					validator.Push(fmt.Errorf(errFmtAccessControlRuleSubjectWildcard, ruleDescriptor(rulePosition, rule), subject))
+				}
 			}
 		}
 	}
@@ -163,43 +269,114 @@ func validateMethods(rulePosition int, rule schema.ACLRule, validator *schema.St
 	}
 }
 
-//nolint:gocyclo
+func validateSchedule(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	if _, err := authorization.NewAccessControlSchedule(rule.Schedule); err != nil {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleScheduleInvalid, ruleDescriptor(rulePosition, rule), err))
+	}
+}
+
+func validateCondition(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	if rule.Condition == "" {
+		return
+	}
+
+	condition, err := authorization.NewAccessControlCondition(rule.Condition)
+	if err != nil {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleConditionInvalid, ruleDescriptor(rulePosition, rule), err))
+
+		return
+	}
+
+	if rule.Policy == policyBypass && (condition.HasSubjects || condition.HasAuthentication) {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleConditionBypassSubjects, ruleDescriptor(rulePosition, rule)))
+	}
+}
+
+func validateSecondFactorMethods(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	if len(rule.SecondFactorMethods) == 0 {
+		return
+	}
+
+	if rule.Policy != policyTwoFactor {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleSecondFactorMethodsPolicy, ruleDescriptor(rulePosition, rule), rule.Policy))
+	}
+
+	for _, method := range rule.SecondFactorMethods {
+		if !utils.IsStringInSlice(method, validDefault2FAMethods) {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleSecondFactorMethodsInvalid, ruleDescriptor(rulePosition, rule), method, strings.Join(validDefault2FAMethods, "', '")))
+		}
+	}
+}
+
+func validateMaxAuthenticationAge(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	switch {
+	case rule.MaxAuthenticationAge.FirstFactor < 0:
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleMaxAuthenticationAgeNegative, ruleDescriptor(rulePosition, rule), "first_factor", rule.MaxAuthenticationAge.FirstFactor))
+	case rule.MaxAuthenticationAge.FirstFactor > 0 && rule.Policy != policyOneFactor && rule.Policy != policyTwoFactor:
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleMaxAuthenticationAgePolicy, ruleDescriptor(rulePosition, rule), "first_factor", "'one_factor' or 'two_factor'", rule.Policy))
+	}
+
+	switch {
+	case rule.MaxAuthenticationAge.SecondFactor < 0:
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleMaxAuthenticationAgeNegative, ruleDescriptor(rulePosition, rule), "second_factor", rule.MaxAuthenticationAge.SecondFactor))
+	case rule.MaxAuthenticationAge.SecondFactor > 0 && rule.Policy != policyTwoFactor:
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleMaxAuthenticationAgePolicy, ruleDescriptor(rulePosition, rule), "second_factor", "'two_factor'", rule.Policy))
+	}
+}
+
 func validateQuery(i int, rule schema.ACLRule, config *schema.Configuration, validator *schema.StructValidator) {
-	for j := 0; j < len(config.AccessControl.Rules[i].Query); j++ {
-		for k := 0; k < len(config.AccessControl.Rules[i].Query[j]); k++ {
-			if config.AccessControl.Rules[i].Query[j][k].Operator == "" {
-				if config.AccessControl.Rules[i].Query[j][k].Key != "" {
-					switch config.AccessControl.Rules[i].Query[j][k].Value {
+	validateOperatorRules(i+1, rule, "query", config.AccessControl.Rules[i].Query, validator)
+}
+
+func validateHeaders(i int, rule schema.ACLRule, config *schema.Configuration, validator *schema.StructValidator) {
+	for _, headers := range rule.Headers {
+		for _, header := range headers {
+			if utils.IsStringInSliceFold(header.Key, authorization.SensitiveHeaders) {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleHeadersSensitive, ruleDescriptor(i+1, rule), header.Key))
+			}
+		}
+	}
+
+	validateOperatorRules(i+1, rule, "headers", config.AccessControl.Rules[i].Headers, validator)
+}
+
+//nolint:gocyclo
+func validateOperatorRules(rulePosition int, rule schema.ACLRule, name string, rules [][]schema.ACLQueryRule, validator *schema.StructValidator) {
+	for j := 0; j < len(rules); j++ {
+		for k := 0; k < len(rules[j]); k++ {
+			if rules[j][k].Operator == "" {
+				if rules[j][k].Key != "" {
+					switch rules[j][k].Value {
 					case "", nil:
-						config.AccessControl.Rules[i].Query[j][k].Operator = operatorPresent
+						rules[j][k].Operator = operatorPresent
 					default:
-						config.AccessControl.Rules[i].Query[j][k].Operator = operatorEqual
+						rules[j][k].Operator = operatorEqual
 					}
 				}
-			} else if !utils.IsStringInSliceFold(config.AccessControl.Rules[i].Query[j][k].Operator, validACLRuleOperators) {
-				validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalid, ruleDescriptor(i+1, rule), config.AccessControl.Rules[i].Query[j][k].Operator, strings.Join(validACLRuleOperators, "', '")))
+			} else if !utils.IsStringInSliceFold(rules[j][k].Operator, validACLRuleOperators) {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalid, ruleDescriptor(rulePosition, rule), name, rules[j][k].Operator, strings.Join(validACLRuleOperators, "', '")))
 			}
 
-			if config.AccessControl.Rules[i].Query[j][k].Key == "" {
-				validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidNoValue, ruleDescriptor(i+1, rule), "key"))
+			if rules[j][k].Key == "" {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidNoValue, ruleDescriptor(rulePosition, rule), name, "key"))
 			}
 
-			op := config.AccessControl.Rules[i].Query[j][k].Operator
+			op := rules[j][k].Operator
 
 			if op == "" {
 				continue
 			}
 
-			switch v := config.AccessControl.Rules[i].Query[j][k].Value.(type) {
+			switch v := rules[j][k].Value.(type) {
 			case nil:
 				if op != operatorAbsent && op != operatorPresent {
-					validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidNoValueOperator, ruleDescriptor(i+1, rule), "value", op))
+					validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidNoValueOperator, ruleDescriptor(rulePosition, rule), name, "value", op))
 				}
 			case string:
 				switch op {
 				case operatorPresent, operatorAbsent:
 					if v != "" {
-						validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidValue, ruleDescriptor(i+1, rule), "value", op))
+						validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidValue, ruleDescriptor(rulePosition, rule), name, "value", op))
 					}
 				case operatorPattern, operatorNotPattern:
 					var (
@@ -208,13 +385,13 @@ func validateQuery(i int, rule schema.ACLRule, config *schema.Configuration, val
 					)
 
 					if pattern, err = regexp.Compile(v); err != nil {
-						validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidValueParse, ruleDescriptor(i+1, rule), "value", err))
+						validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidValueParse, ruleDescriptor(rulePosition, rule), name, "value", err))
 					} else {
-						config.AccessControl.Rules[i].Query[j][k].Value = pattern
+						rules[j][k].Value = pattern
 					}
 				}
 			default:
-				validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidValueType, ruleDescriptor(i+1, rule), v))
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryInvalidValueType, ruleDescriptor(rulePosition, rule), name, v))
 			}
 		}
 	}
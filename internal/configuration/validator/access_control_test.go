@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -88,6 +89,25 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidNetworkGroupNetwork() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: networks: network group 'internal' is invalid: the network 'abc.def.ghi.jkl' is not a valid IP or CIDR notation")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidNetworkGroupGeoIPNetwork() {
+	suite.config.AccessControl.Networks = []schema.ACLNetwork{
+		{
+			Name:     "eu",
+			Networks: []string{"country:DE", "country:EUR", "asn:13335"},
+		},
+	}
+
+	suite.config.GeoIP.CountryDatabase = "/config/GeoLite2-Country.mmdb"
+
+	ValidateAccessControl(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: networks: network group 'eu' is invalid: the network 'country:EUR' is invalid: the country 'EUR' is not a valid ISO 3166-1 alpha-2 country code")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: networks: network group 'eu' is invalid: the network 'asn:13335' requires the 'geoip' option 'asn_database' to be configured")
+}
+
 func (suite *AccessControl) TestShouldRaiseWarningOnBadDomain() {
 	suite.config.AccessControl.Rules = []schema.ACLRule{
 		{
@@ -180,6 +200,26 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidNetwork() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): the network 'abc.def.ghi.jkl/32' is not a valid Group Name, IP, or CIDR notation")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidGeoIPNetwork() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:  []string{"public.example.com"},
+			Policy:   "bypass",
+			Networks: []string{"country:DE", "asn:AS13335", "asn:abc"},
+		},
+	}
+
+	suite.config.GeoIP.ASNDatabase = "/config/GeoLite2-ASN.mmdb"
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): the network 'country:DE' requires the 'geoip' option 'country_database' to be configured")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): the network 'asn:abc' is invalid: the autonomous system number 'abc' is not a valid number")
+}
+
 func (suite *AccessControl) TestShouldRaiseErrorInvalidMethod() {
 	suite.config.AccessControl.Rules = []schema.ACLRule{
 		{
@@ -213,10 +253,83 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidSubject() {
 	suite.Require().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 2)
 
-	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'subject' option 'invalid' is invalid: must start with 'user:' or 'group:'")
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'subject' option 'invalid' is invalid: must start with 'user:', 'user-regex:', 'user-glob:', 'group:', 'group-regex:', 'group-glob:', 'email:', 'email-regex:', 'email-glob:', or 'attribute:'")
 	suite.Assert().EqualError(suite.validator.Errors()[1], fmt.Sprintf(errAccessControlRuleBypassPolicyInvalidWithSubjects, ruleDescriptor(1, suite.config.AccessControl.Rules[0])))
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidPatternSubject() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "one_factor",
+			Subjects: [][]string{
+				{"user-regex:^svc-"},
+				{"group-regex:^team-.*-admins$"},
+				{"email-regex:@contractor\\.example$"},
+				{"email:john@example.com"},
+				{"group:^team-(admins$"},
+				{"group-regex:^team-(admins$"},
+				{"user-glob:svc-*"},
+				{"group-glob:team-?-admins"},
+				{"email-glob:*@contractor.example"},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'subject' option 'group-regex:^team-(admins$' is invalid: pattern '^team-(admins$' is invalid: error parsing regexp: missing closing ): `^team-(admins$`")
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorWildcardExactSubject() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "deny",
+			Subjects: [][]string{
+				{"user:svc-*"},
+				{"group:team-?-admins"},
+				{"email:*@contractor.example"},
+				{"user:john"},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 3)
+
+	for i, subject := range []string{"user:svc-*", "group:team-?-admins", "email:*@contractor.example"} {
+		suite.Assert().EqualError(suite.validator.Errors()[i], fmt.Sprintf(errFmtAccessControlRuleSubjectWildcard, ruleDescriptor(1, suite.config.AccessControl.Rules[0]), subject))
+	}
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidAttributeSubject() {
+	suite.config.AuthenticationBackend.ExtraAttributes = []schema.AuthenticationBackendExtraAttribute{
+		{Name: "department"},
+	}
+
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:  []string{"public.example.com"},
+			Policy:   "one_factor",
+			Subjects: [][]string{{"attribute:department=engineering"}, {"attribute:department"}, {"attribute:phone=555-0100"}},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'subject' option 'attribute:department' is invalid: must be in the format 'attribute:<name>=<value>' where the name is one of the configured authentication_backend extra_attributes")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): 'subject' option 'attribute:phone=555-0100' is invalid: must be in the format 'attribute:<name>=<value>' where the name is one of the configured authentication_backend extra_attributes")
+}
+
 func (suite *AccessControl) TestShouldRaiseErrorBypassWithSubjectDomainRegexGroup() {
 	suite.config.AccessControl.Rules = []schema.ACLRule{
 		{
@@ -376,6 +489,282 @@ func (suite *AccessControl) TestShouldErrorOnInvalidRulesQuery() {
 	suite.Assert().EqualError(suite.validator.Errors()[6], "access control: rule #9 (domain 'public.example.com'): 'query' option 'value' is invalid: expected type was string but got int")
 }
 
+func (suite *AccessControl) TestShouldErrorOnInvalidRulesHeaders() {
+	domains := []string{"public.example.com"}
+
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: domains,
+			Policy:  "bypass",
+			Headers: [][]schema.ACLQueryRule{
+				{
+					{Key: "X-Api-Client", Value: "backup"},
+					{Key: "User-Agent"},
+					{Operator: "pattern", Key: "Content-Type", Value: "^multipart/"},
+				},
+			},
+		},
+		{
+			Domains: domains,
+			Policy:  "bypass",
+			Headers: [][]schema.ACLQueryRule{
+				{
+					{Operator: "equal", Key: "X-Api-Client"},
+				},
+			},
+		},
+		{
+			Domains: domains,
+			Policy:  "bypass",
+			Headers: [][]schema.ACLQueryRule{
+				{
+					{Operator: "pattern", Key: "User-Agent", Value: "(bad pattern"},
+				},
+			},
+		},
+		{
+			Domains: domains,
+			Policy:  "bypass",
+			Headers: [][]schema.ACLQueryRule{
+				{
+					{Key: "authorization", Value: "Basic am9objpwYXNzd29yZA=="},
+				},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 3)
+
+	suite.Assert().Equal("equal", suite.config.AccessControl.Rules[0].Headers[0][0].Operator)
+	suite.Assert().Equal("present", suite.config.AccessControl.Rules[0].Headers[0][1].Operator)
+	suite.Assert().IsType(&regexp.Regexp{}, suite.config.AccessControl.Rules[0].Headers[0][2].Value)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #2 (domain 'public.example.com'): 'headers' option 'value' is invalid: must have a value when the operator is 'equal'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #3 (domain 'public.example.com'): 'headers' option 'value' is invalid: error parsing regexp: missing closing ): `(bad pattern`")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #4 (domain 'public.example.com'): 'headers' option 'key' is invalid: the header 'authorization' is sensitive and is never matched")
+}
+
+func (suite *AccessControl) TestShouldErrorOnInvalidRulesSchedule() {
+	domains := []string{"public.example.com"}
+
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: domains,
+			Policy:  "one_factor",
+			Schedule: schema.ACLSchedule{
+				Timezone: "Europe/Berlin",
+				Days:     []string{"monday-friday", "sun"},
+				Times:    []string{"09:00-17:00", "22:00-02:00"},
+				Dates:    []string{"2023-01-01", "2023-02-01/2023-02-14"},
+			},
+		},
+		{
+			Domains: domains,
+			Policy:  "one_factor",
+			Schedule: schema.ACLSchedule{
+				Timezone: "Not/AZone",
+				Days:     []string{"monday"},
+			},
+		},
+		{
+			Domains: domains,
+			Policy:  "one_factor",
+			Schedule: schema.ACLSchedule{
+				Days: []string{"someday"},
+			},
+		},
+		{
+			Domains: domains,
+			Policy:  "one_factor",
+			Schedule: schema.ACLSchedule{
+				Times: []string{"09:00"},
+			},
+		},
+		{
+			Domains: domains,
+			Policy:  "one_factor",
+			Schedule: schema.ACLSchedule{
+				Times: []string{"09:00-25:00"},
+			},
+		},
+		{
+			Domains: domains,
+			Policy:  "one_factor",
+			Schedule: schema.ACLSchedule{
+				Dates: []string{"2023-02-14/2023-02-01"},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 5)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #2 (domain 'public.example.com'): 'schedule' option is invalid: timezone 'Not/AZone' is invalid: unknown time zone Not/AZone")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #3 (domain 'public.example.com'): 'schedule' option is invalid: day 'someday' is not a valid day of the week")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #4 (domain 'public.example.com'): 'schedule' option is invalid: time range '09:00' is not in the format HH:MM-HH:MM")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #5 (domain 'public.example.com'): 'schedule' option is invalid: time '25:00' is not in the format HH:MM")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "access control: rule #6 (domain 'public.example.com'): 'schedule' option is invalid: date range '2023-02-14/2023-02-01' is invalid: the end must not be before the start")
+}
+
+func (suite *AccessControl) TestShouldErrorOnInvalidRulesCondition() {
+	domains := []string{"public.example.com"}
+
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:   domains,
+			Policy:    "one_factor",
+			Condition: "'admins' in subject.groups && object.method == 'GET'",
+		},
+		{
+			Domains:   domains,
+			Policy:    "bypass",
+			Condition: "object.path.startsWith('/public/')",
+		},
+		{
+			Domains:   domains,
+			Policy:    "one_factor",
+			Condition: "subject.username",
+		},
+		{
+			Domains:   domains,
+			Policy:    "one_factor",
+			Condition: "object.path.matches('^(')",
+		},
+		{
+			Domains:   domains,
+			Policy:    "bypass",
+			Condition: "subject.username == 'john'",
+		},
+		{
+			Domains:   domains,
+			Policy:    "bypass",
+			Condition: "subject.level == 'two_factor'",
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #3 (domain 'public.example.com'): 'condition' option is invalid: the expression must evaluate to a bool but it evaluates to a string")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #4 (domain 'public.example.com'): 'condition' option is invalid: error parsing regexp: missing closing ): `^(`")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #5 (domain 'public.example.com'): 'policy' option 'bypass' is not supported when the 'condition' option refers to the identity or authentication of the subject")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #6 (domain 'public.example.com'): 'policy' option 'bypass' is not supported when the 'condition' option refers to the identity or authentication of the subject")
+}
+
+func (suite *AccessControl) TestShouldErrorOnInvalidRulesSecondFactorMethods() {
+	domains := []string{"public.example.com"}
+
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:             domains,
+			Policy:              "two_factor",
+			SecondFactorMethods: []string{"webauthn", "mobile_push"},
+		},
+		{
+			Domains:             domains,
+			Policy:              "one_factor",
+			SecondFactorMethods: []string{"webauthn"},
+		},
+		{
+			Domains:             domains,
+			Policy:              "two_factor",
+			SecondFactorMethods: []string{"sms"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #2 (domain 'public.example.com'): 'second_factor_methods' option is invalid: the 'policy' option must be 'two_factor' but it is configured as 'one_factor'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #3 (domain 'public.example.com'): 'second_factor_methods' option 'sms' is invalid: must be one of 'totp', 'webauthn', 'mobile_push'")
+}
+
+func (suite *AccessControl) TestShouldErrorOnInvalidRulesMaxAuthenticationAge() {
+	domains := []string{"public.example.com"}
+
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:              domains,
+			Policy:               "two_factor",
+			MaxAuthenticationAge: schema.ACLMaxAuthenticationAge{FirstFactor: time.Hour, SecondFactor: time.Minute * 10},
+		},
+		{
+			Domains:              domains,
+			Policy:               "one_factor",
+			MaxAuthenticationAge: schema.ACLMaxAuthenticationAge{FirstFactor: time.Hour, SecondFactor: time.Minute * 10},
+		},
+		{
+			Domains:              domains,
+			Policy:               "bypass",
+			MaxAuthenticationAge: schema.ACLMaxAuthenticationAge{FirstFactor: time.Hour},
+		},
+		{
+			Domains:              domains,
+			Policy:               "two_factor",
+			MaxAuthenticationAge: schema.ACLMaxAuthenticationAge{FirstFactor: -time.Hour, SecondFactor: -time.Minute},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #2 (domain 'public.example.com'): 'max_authentication_age' option 'second_factor' is invalid: the 'policy' option must be 'two_factor' but it is configured as 'one_factor'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #3 (domain 'public.example.com'): 'max_authentication_age' option 'first_factor' is invalid: the 'policy' option must be 'one_factor' or 'two_factor' but it is configured as 'bypass'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #4 (domain 'public.example.com'): 'max_authentication_age' option 'first_factor' is invalid: must be greater than or equal to 0 but it is configured as '-1h0m0s'")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #4 (domain 'public.example.com'): 'max_authentication_age' option 'second_factor' is invalid: must be greater than or equal to 0 but it is configured as '-1m0s'")
+}
+
+func (suite *AccessControl) TestShouldErrorOnPathWithOptions() {
+	suite.config.AccessControl.Path = "/config/access_control.yml"
+
+	suite.Assert().False(ValidateAccessControlPath(&suite.config.AccessControl, suite.validator))
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: option 'path' must not be configured when any of the options 'default_policy', 'networks', or 'rules' are also configured")
+}
+
+func (suite *AccessControl) TestShouldValidatePathWithoutOptions() {
+	suite.config.AccessControl = schema.AccessControlConfiguration{Path: "/config/access_control.yml"}
+
+	suite.Assert().True(ValidateAccessControlPath(&suite.config.AccessControl, suite.validator))
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
+func (suite *AccessControl) TestShouldValidatePathKeys() {
+	keys := []string{
+		"access_control.default_policy",
+		"access_control.rules[0].domain",
+		"access_control.rules[0].policy",
+		"access_control.rules[0].not_a_key",
+		"server.port",
+		"AUTHELIA_ACCESS_CONTROL_EXAMPLE",
+	}
+
+	ValidateAccessControlPathKeys("/config/access_control.yml", keys, "AUTHELIA_", suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 3)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: option 'path': the file '/config/access_control.yml' must only contain the 'access_control' key but it contains the key 'server.port'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: option 'path': the file '/config/access_control.yml' must only contain the 'access_control' key but it contains the key 'AUTHELIA_ACCESS_CONTROL_EXAMPLE'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "configuration key not expected: access_control.rules[0].not_a_key")
+}
+
 func TestAccessControl(t *testing.T) {
 	suite.Run(t, new(AccessControl))
 }
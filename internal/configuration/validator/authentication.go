@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-crypt/crypt/algorithm/argon2"
 	"github.com/go-crypt/crypt/algorithm/bcrypt"
@@ -17,7 +18,7 @@ import (
 
 // ValidateAuthenticationBackend validates and updates the authentication backend configuration.
 func ValidateAuthenticationBackend(config *schema.AuthenticationBackend, validator *schema.StructValidator) {
-	if config.LDAP == nil && config.File == nil {
+	if config.LDAP == nil && config.File == nil && config.Storage == nil {
 		validator.Push(fmt.Errorf(errFmtAuthBackendNotConfigured))
 	}
 
@@ -39,19 +40,65 @@ func ValidateAuthenticationBackend(config *schema.AuthenticationBackend, validat
 		}
 	}
 
-	if config.LDAP != nil && config.File != nil {
-		validator.Push(fmt.Errorf(errFmtAuthBackendMultipleConfigured))
-	}
+	validatePasswordChangeAuthenticationBackend(config, validator)
+
+	validateAuthenticationBackendCache(config, validator)
+
+	validateAuthenticationBackendChain(config, validator)
+
+	validateAuthenticationBackendExtraAttributes(config, validator)
 
 	if config.File != nil {
 		validateFileAuthenticationBackend(config.File, validator)
 	}
 
+	if config.Storage != nil {
+		validateStorageAuthenticationBackend(config.Storage, validator)
+	}
+
 	if config.LDAP != nil {
 		validateLDAPAuthenticationBackend(config, validator)
 	}
 }
 
+// validatePasswordChangeAuthenticationBackend validates and updates the change password configuration.
+func validatePasswordChangeAuthenticationBackend(config *schema.AuthenticationBackend, validator *schema.StructValidator) {
+	switch {
+	case config.PasswordChange.SecondFactorMaxAge < 0:
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordChangeSecondFactorMaxAge, config.PasswordChange.SecondFactorMaxAge))
+	case config.PasswordChange.SecondFactorMaxAge == 0:
+		config.PasswordChange.SecondFactorMaxAge = schema.DefaultPasswordChangeAuthenticationBackend.SecondFactorMaxAge
+	}
+}
+
+// validateAuthenticationBackendCache validates and updates the user details cache configuration.
+func validateAuthenticationBackendCache(config *schema.AuthenticationBackend, validator *schema.StructValidator) {
+	if !config.Cache.Enable {
+		return
+	}
+
+	switch {
+	case config.Cache.MaxEntries == 0:
+		config.Cache.MaxEntries = schema.DefaultAuthenticationBackendCache.MaxEntries
+	case config.Cache.MaxEntries < 0:
+		validator.Push(fmt.Errorf(errFmtAuthBackendCacheOptionNegative, "max_entries", config.Cache.MaxEntries))
+	}
+
+	switch {
+	case config.Cache.TTL == 0:
+		config.Cache.TTL = schema.DefaultAuthenticationBackendCache.TTL
+	case config.Cache.TTL < 0:
+		validator.Push(fmt.Errorf(errFmtAuthBackendCacheOptionNegative, "ttl", config.Cache.TTL))
+	}
+
+	switch {
+	case config.Cache.NegativeTTL == 0:
+		config.Cache.NegativeTTL = schema.DefaultAuthenticationBackendCache.NegativeTTL
+	case config.Cache.NegativeTTL < 0:
+		validator.Push(fmt.Errorf(errFmtAuthBackendCacheOptionNegative, "negative_ttl", config.Cache.NegativeTTL))
+	}
+}
+
 // validateFileAuthenticationBackend validates and updates the file authentication backend configuration.
 func validateFileAuthenticationBackend(config *schema.FileAuthenticationBackend, validator *schema.StructValidator) {
 	if config.Path == "" {
@@ -61,6 +108,110 @@ func validateFileAuthenticationBackend(config *schema.FileAuthenticationBackend,
 	ValidatePasswordConfiguration(&config.Password, validator)
 }
 
+// validateStorageAuthenticationBackend validates and updates the storage authentication backend configuration.
+func validateStorageAuthenticationBackend(config *schema.StorageAuthenticationBackend, validator *schema.StructValidator) {
+	ValidatePasswordConfiguration(&config.Password, validator)
+}
+
+// validateAuthenticationBackendChain validates the chain of authentication backends.
+func validateAuthenticationBackendChain(config *schema.AuthenticationBackend, validator *schema.StructValidator) {
+	configured := configuredAuthenticationBackends(config)
+
+	if len(config.Chain) == 0 {
+		if len(configured) > 1 {
+			validator.Push(fmt.Errorf(errFmtAuthBackendMultipleConfigured))
+		}
+
+		return
+	}
+
+	seen := make([]string, 0, len(config.Chain))
+
+	for _, name := range config.Chain {
+		switch {
+		case !utils.IsStringInSlice(name, validAuthBackendChainNames):
+			validator.Push(fmt.Errorf(errFmtAuthBackendChainInvalid, strings.Join(validAuthBackendChainNames, "', '"), name))
+		case utils.IsStringInSlice(name, seen):
+			validator.Push(fmt.Errorf(errFmtAuthBackendChainDuplicate, name))
+		case !utils.IsStringInSlice(name, configured):
+			validator.Push(fmt.Errorf(errFmtAuthBackendChainNotConfigured, name))
+		}
+
+		seen = append(seen, name)
+	}
+
+	for _, name := range configured {
+		if !utils.IsStringInSlice(name, config.Chain) {
+			validator.Push(fmt.Errorf(errFmtAuthBackendChainMissing, name))
+		}
+	}
+}
+
+// validateAuthenticationBackendExtraAttributes validates and updates the extra attributes configuration.
+func validateAuthenticationBackendExtraAttributes(config *schema.AuthenticationBackend, validator *schema.StructValidator) {
+	var names, headers, claims []string
+
+	for i := range config.ExtraAttributes {
+		attribute := &config.ExtraAttributes[i]
+
+		if !reAuthBackendExtraAttributeName.MatchString(attribute.Name) {
+			validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeName, i+1, attribute.Name))
+
+			continue
+		}
+
+		if utils.IsStringInSlice(attribute.Name, names) {
+			validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeDuplicate, attribute.Name, "name", attribute.Name))
+		}
+
+		names = append(names, attribute.Name)
+
+		if attribute.LDAPAttribute == "" {
+			attribute.LDAPAttribute = attribute.Name
+		}
+
+		if attribute.Header != "" {
+			switch {
+			case !reAuthBackendExtraAttributeHeader.MatchString(attribute.Header):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeHeader, attribute.Name, attribute.Header))
+			case utils.IsStringInSliceFold(attribute.Header, reservedAuthBackendExtraAttributeHeaders):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeHeaderReserved, attribute.Name, attribute.Header))
+			case utils.IsStringInSliceFold(attribute.Header, headers):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeDuplicate, attribute.Name, "header", attribute.Header))
+			}
+
+			headers = append(headers, attribute.Header)
+		}
+
+		if attribute.Claim != "" {
+			switch {
+			case utils.IsStringInSlice(attribute.Claim, reservedAuthBackendExtraAttributeClaims):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeClaimReserved, attribute.Name, attribute.Claim))
+			case utils.IsStringInSlice(attribute.Claim, claims):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeDuplicate, attribute.Name, "claim", attribute.Claim))
+			}
+
+			claims = append(claims, attribute.Claim)
+		}
+	}
+}
+
+func configuredAuthenticationBackends(config *schema.AuthenticationBackend) (names []string) {
+	if config.File != nil {
+		names = append(names, schema.AuthenticationBackendFile)
+	}
+
+	if config.LDAP != nil {
+		names = append(names, schema.AuthenticationBackendLDAP)
+	}
+
+	if config.Storage != nil {
+		names = append(names, schema.AuthenticationBackendStorage)
+	}
+
+	return names
+}
+
 // ValidatePasswordConfiguration validates the file auth backend password configuration.
 func ValidatePasswordConfiguration(config *schema.Password, validator *schema.StructValidator) {
 	validateFileAuthenticationBackendPasswordConfigLegacy(config)
@@ -379,9 +530,80 @@ func validateLDAPAuthenticationBackend(config *schema.AuthenticationBackend, val
 		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendFilterReplacedPlaceholders, "groups_filter", "{1}", "{username}"))
 	}
 
+	validateLDAPAuthenticationBackendPooling(config.LDAP, validator)
+	validateLDAPAuthenticationBackendNestedGroups(config.LDAP, validator)
 	validateLDAPRequiredParameters(config, validator)
 }
 
+func validateLDAPAuthenticationBackendNestedGroups(config *schema.LDAPAuthenticationBackend, validator *schema.StructValidator) {
+	if !config.NestedGroups.Enable {
+		return
+	}
+
+	if config.NestedGroups.Method == "" {
+		if config.Implementation == schema.LDAPImplementationActiveDirectory {
+			config.NestedGroups.Method = schema.LDAPNestedGroupsMethodInChain
+		} else {
+			config.NestedGroups.Method = schema.DefaultLDAPAuthenticationBackendNestedGroups.Method
+		}
+	}
+
+	switch config.NestedGroups.Method {
+	case schema.LDAPNestedGroupsMethodInChain:
+		if !strings.Contains(config.GroupsFilter, ldapPlaceholderInChain) {
+			validator.Push(fmt.Errorf(errFmtLDAPAuthBackendNestedGroupsFilter, config.NestedGroups.Method, ldapPlaceholderInChain))
+		}
+	case schema.LDAPNestedGroupsMethodMember:
+		if !strings.Contains(config.GroupsFilter, ldapPlaceholderDN) {
+			validator.Push(fmt.Errorf(errFmtLDAPAuthBackendNestedGroupsFilter, config.NestedGroups.Method, ldapPlaceholderDN))
+		}
+	case schema.LDAPNestedGroupsMethodMemberOf:
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendNestedGroupsMethod, config.NestedGroups.Method, strings.Join(validLDAPNestedGroupsMethods, "', '")))
+	}
+
+	switch {
+	case config.NestedGroups.MaxDepth == 0:
+		config.NestedGroups.MaxDepth = schema.DefaultLDAPAuthenticationBackendNestedGroups.MaxDepth
+	case config.NestedGroups.MaxDepth < 0:
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendNestedGroupsMaxDepth, config.NestedGroups.MaxDepth))
+	}
+}
+
+func validateLDAPAuthenticationBackendPooling(config *schema.LDAPAuthenticationBackend, validator *schema.StructValidator) {
+	if !config.Pooling.Enable {
+		return
+	}
+
+	switch {
+	case config.Pooling.Count == 0:
+		config.Pooling.Count = schema.DefaultLDAPAuthenticationBackendPooling.Count
+	case config.Pooling.Count < 0:
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendPoolingOptionNegative, "count", config.Pooling.Count))
+	}
+
+	durations := []struct {
+		name     string
+		value    *time.Duration
+		fallback time.Duration
+	}{
+		{"timeout", &config.Pooling.Timeout, schema.DefaultLDAPAuthenticationBackendPooling.Timeout},
+		{"idle_timeout", &config.Pooling.IdleTimeout, schema.DefaultLDAPAuthenticationBackendPooling.IdleTimeout},
+		{"max_lifetime", &config.Pooling.MaxLifetime, schema.DefaultLDAPAuthenticationBackendPooling.MaxLifetime},
+		{"health_check_interval", &config.Pooling.HealthCheckInterval, schema.DefaultLDAPAuthenticationBackendPooling.HealthCheckInterval},
+	}
+
+	for _, d := range durations {
+		switch {
+		case *d.value == 0:
+			*d.value = d.fallback
+		case *d.value < 0:
+			validator.Push(fmt.Errorf(errFmtLDAPAuthBackendPoolingOptionNegative, d.name, *d.value))
+		}
+	}
+}
+
 func ldapImplementationShouldSetStr(config, implementation string) bool {
 	return config == "" && implementation != ""
 }
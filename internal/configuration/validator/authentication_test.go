@@ -25,7 +25,7 @@ func TestShouldRaiseErrorWhenBothBackendsProvided(t *testing.T) {
 	ValidateAuthenticationBackend(&backendConfig, validator)
 
 	require.Len(t, validator.Errors(), 7)
-	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file' or 'ldap' backend is configured")
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file', 'ldap', or 'storage' backend is configured or the 'chain' option is configured")
 	assert.EqualError(t, validator.Errors()[1], "authentication_backend: ldap: option 'url' is required")
 	assert.EqualError(t, validator.Errors()[2], "authentication_backend: ldap: option 'user' is required")
 	assert.EqualError(t, validator.Errors()[3], "authentication_backend: ldap: option 'password' is required")
@@ -41,7 +41,137 @@ func TestShouldRaiseErrorWhenNoBackendProvided(t *testing.T) {
 	ValidateAuthenticationBackend(&backendConfig, validator)
 
 	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "authentication_backend: you must ensure either the 'file' or 'ldap' authentication backend is configured")
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: you must ensure either the 'file', 'ldap', or 'storage' authentication backend is configured")
+}
+
+func TestShouldRaiseErrorWhenStorageAndFileBackendsProvided(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackend{}
+
+	backendConfig.Storage = &schema.StorageAuthenticationBackend{}
+	backendConfig.File = &schema.FileAuthenticationBackend{
+		Path: "/tmp",
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file', 'ldap', or 'storage' backend is configured or the 'chain' option is configured")
+}
+
+func TestShouldValidateAuthenticationBackendChain(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackend{
+		Chain:   []string{"file", "storage"},
+		Storage: &schema.StorageAuthenticationBackend{},
+		File: &schema.FileAuthenticationBackend{
+			Path: "/tmp",
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldRaiseErrorsWhenAuthenticationBackendChainInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackend{
+		Chain:   []string{"file", "file", "ldap", "bad"},
+		Storage: &schema.StorageAuthenticationBackend{},
+		File: &schema.FileAuthenticationBackend{
+			Path: "/tmp",
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 4)
+
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: option 'chain' must not contain duplicate values but contains 'file' more than once")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: option 'chain' contains 'ldap' but this backend is not configured")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: option 'chain' must only contain values from 'file', 'ldap', 'storage' but contains 'bad'")
+	assert.EqualError(t, validator.Errors()[3], "authentication_backend: option 'chain' must contain all configured backends but 'storage' is configured and missing from it")
+}
+
+func TestShouldValidateAuthenticationBackendExtraAttributes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackend{
+		ExtraAttributes: []schema.AuthenticationBackendExtraAttribute{
+			{Name: "department", Header: "Remote-Department", Claim: "department"},
+			{Name: "employee_number", LDAPAttribute: "employeeNumber"},
+		},
+		Storage: &schema.StorageAuthenticationBackend{},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, "department", backendConfig.ExtraAttributes[0].LDAPAttribute)
+	assert.Equal(t, "employeeNumber", backendConfig.ExtraAttributes[1].LDAPAttribute)
+}
+
+func TestShouldRaiseErrorsWhenAuthenticationBackendExtraAttributesInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackend{
+		ExtraAttributes: []schema.AuthenticationBackendExtraAttribute{
+			{Name: "department", Header: "Remote-Department", Claim: "department"},
+			{Name: "department", Header: "remote-department", Claim: "department"},
+			{Name: "bad=name"},
+			{Name: "phone", Header: "X-Phone"},
+			{Name: "user", Header: "Remote-User", Claim: "sub"},
+		},
+		Storage: &schema.StorageAuthenticationBackend{},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 7)
+
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: extra_attributes: attribute 'department': option 'name' must be unique but 'department' is configured more than once")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: extra_attributes: attribute 'department': option 'header' must be unique but 'remote-department' is configured more than once")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: extra_attributes: attribute 'department': option 'claim' must be unique but 'department' is configured more than once")
+	assert.EqualError(t, validator.Errors()[3], "authentication_backend: extra_attributes: attribute #3: option 'name' must only contain alphanumeric characters and underscores and must start with a letter but it's configured as 'bad=name'")
+	assert.EqualError(t, validator.Errors()[4], "authentication_backend: extra_attributes: attribute 'phone': option 'header' must be a valid header name starting with 'Remote-' but it's configured as 'X-Phone'")
+	assert.EqualError(t, validator.Errors()[5], "authentication_backend: extra_attributes: attribute 'user': option 'header' is configured as 'Remote-User' but this header is reserved")
+	assert.EqualError(t, validator.Errors()[6], "authentication_backend: extra_attributes: attribute 'user': option 'claim' is configured as 'sub' but this claim is reserved")
+}
+
+func TestShouldValidateStorageBackend(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackend{
+		Storage: &schema.StorageAuthenticationBackend{},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, schema.DefaultPasswordConfig.Algorithm, backendConfig.Storage.Password.Algorithm)
+	assert.Equal(t, schema.DefaultPasswordConfig.Argon2.Variant, backendConfig.Storage.Password.Argon2.Variant)
+	assert.Equal(t, schema.DefaultPasswordConfig.Argon2.Memory, backendConfig.Storage.Password.Argon2.Memory)
+	assert.Equal(t, schema.RefreshIntervalDefault, backendConfig.RefreshInterval)
+}
+
+func TestShouldRaiseErrorWhenStorageBackendHasBadAlgorithm(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackend{
+		Storage: &schema.StorageAuthenticationBackend{
+			Password: schema.Password{Algorithm: "bogus"},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: file: password: option 'algorithm' is configured as 'bogus' but must be one of the following values: 'sha2crypt', 'pbkdf2', 'scrypt', 'bcrypt', 'argon2'")
 }
 
 type FileBasedAuthenticationBackend struct {
@@ -558,6 +688,57 @@ func (suite *FileBasedAuthenticationBackend) TestShouldConfigureDisableResetPass
 	suite.Assert().False(suite.config.PasswordReset.Disable)
 }
 
+func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultPasswordChangeSecondFactorMaxAge() {
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultPasswordChangeAuthenticationBackend.SecondFactorMaxAge, suite.config.PasswordChange.SecondFactorMaxAge)
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenPasswordChangeSecondFactorMaxAgeIsNegative() {
+	suite.config.PasswordChange.SecondFactorMaxAge = -time.Minute
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: password_change: option 'second_factor_max_age' must be greater than 0 but it's configured as '-1m0s'")
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultCacheOptions() {
+	suite.config.Cache.Enable = true
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultAuthenticationBackendCache.TTL, suite.config.Cache.TTL)
+	suite.Assert().Equal(schema.DefaultAuthenticationBackendCache.NegativeTTL, suite.config.Cache.NegativeTTL)
+	suite.Assert().Equal(schema.DefaultAuthenticationBackendCache.MaxEntries, suite.config.Cache.MaxEntries)
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenCacheOptionsAreNegative() {
+	suite.config.Cache = schema.AuthenticationBackendCache{
+		Enable:      true,
+		TTL:         -time.Minute,
+		NegativeTTL: -time.Second,
+		MaxEntries:  -1,
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 3)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: cache: option 'max_entries' must not be negative but it's configured as '-1'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: cache: option 'ttl' must not be negative but it's configured as '-1m0s'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "authentication_backend: cache: option 'negative_ttl' must not be negative but it's configured as '-1s'")
+}
+
 func TestFileBasedAuthenticationBackend(t *testing.T) {
 	suite.Run(t, new(FileBasedAuthenticationBackend))
 }
@@ -601,6 +782,118 @@ func (suite *LDAPAuthenticationBackendSuite) TestShouldValidateDefaultImplementa
 	suite.Assert().Len(suite.validator.Errors(), 0)
 }
 
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultPoolingOptions() {
+	suite.config.LDAP.Pooling.Enable = true
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultLDAPAuthenticationBackendPooling.Count, suite.config.LDAP.Pooling.Count)
+	suite.Assert().Equal(schema.DefaultLDAPAuthenticationBackendPooling.Timeout, suite.config.LDAP.Pooling.Timeout)
+	suite.Assert().Equal(schema.DefaultLDAPAuthenticationBackendPooling.IdleTimeout, suite.config.LDAP.Pooling.IdleTimeout)
+	suite.Assert().Equal(schema.DefaultLDAPAuthenticationBackendPooling.MaxLifetime, suite.config.LDAP.Pooling.MaxLifetime)
+	suite.Assert().Equal(schema.DefaultLDAPAuthenticationBackendPooling.HealthCheckInterval, suite.config.LDAP.Pooling.HealthCheckInterval)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldNotSetDefaultPoolingOptionsWhenDisabled() {
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.LDAPAuthenticationBackendPooling{}, suite.config.LDAP.Pooling)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorOnNegativePoolingOptions() {
+	suite.config.LDAP.Pooling = schema.LDAPAuthenticationBackendPooling{
+		Enable:              true,
+		Count:               -1,
+		Timeout:             -time.Second,
+		IdleTimeout:         -time.Second,
+		MaxLifetime:         -time.Second,
+		HealthCheckInterval: -time.Second,
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 5)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: pooling: option 'count' must not be negative but it's configured as '-1'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: ldap: pooling: option 'timeout' must not be negative but it's configured as '-1s'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "authentication_backend: ldap: pooling: option 'idle_timeout' must not be negative but it's configured as '-1s'")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "authentication_backend: ldap: pooling: option 'max_lifetime' must not be negative but it's configured as '-1s'")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "authentication_backend: ldap: pooling: option 'health_check_interval' must not be negative but it's configured as '-1s'")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultNestedGroupsOptions() {
+	suite.config.LDAP.GroupsFilter = "(&(member={dn})(objectClass=groupOfNames))"
+	suite.config.LDAP.NestedGroups.Enable = true
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.LDAPNestedGroupsMethodMember, suite.config.LDAP.NestedGroups.Method)
+	suite.Assert().Equal(schema.DefaultLDAPAuthenticationBackendNestedGroups.MaxDepth, suite.config.LDAP.NestedGroups.MaxDepth)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultNestedGroupsMethodActiveDirectory() {
+	suite.config.LDAP.Implementation = schema.LDAPImplementationActiveDirectory
+	suite.config.LDAP.GroupsFilter = ""
+	suite.config.LDAP.NestedGroups.Enable = true
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.LDAPNestedGroupsMethodInChain, suite.config.LDAP.NestedGroups.Method)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorOnInvalidNestedGroupsOptions() {
+	suite.config.LDAP.NestedGroups = schema.LDAPAuthenticationBackendNestedGroups{
+		Enable:   true,
+		Method:   "recursive",
+		MaxDepth: -1,
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: nested_groups: option 'method' is configured as 'recursive' but must be one of the following values: 'in_chain', 'member', 'member_of'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: ldap: nested_groups: option 'max_depth' must not be negative but it's configured as '-1'")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorWhenNestedGroupsFilterIncompatible() {
+	suite.config.LDAP.NestedGroups = schema.LDAPAuthenticationBackendNestedGroups{
+		Enable: true,
+		Method: schema.LDAPNestedGroupsMethodInChain,
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: nested_groups: option 'method' is configured as 'in_chain' which requires the 'groups_filter' option to contain '{in_chain}'")
+
+	suite.validator.Clear()
+
+	suite.config.LDAP.NestedGroups.Method = schema.LDAPNestedGroupsMethodMember
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: nested_groups: option 'method' is configured as 'member' which requires the 'groups_filter' option to contain '{dn}'")
+}
+
 func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorWhenImplementationIsInvalidMSAD() {
 	suite.config.LDAP.Implementation = "masd"
 
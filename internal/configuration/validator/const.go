@@ -15,6 +15,10 @@ const (
 	oauth2InstalledApp = "urn:ietf:wg:oauth:2.0:oob"
 )
 
+// passwordPolicyHistoryMaxCount is the maximum number of digests in the password history, each of which may be matched
+// against a password when it's checked.
+const passwordPolicyHistoryMaxCount = 24
+
 // Policy constants.
 const (
 	policyBypass    = "bypass"
@@ -51,6 +55,12 @@ const (
 	schemeHTTPS = "https"
 )
 
+// LDAP filter constants.
+const (
+	ldapPlaceholderDN      = "{dn}"
+	ldapPlaceholderInChain = "{in_chain}"
+)
+
 // Notifier Error constants.
 const (
 	errFmtNotifierMultipleConfigured = "notifier: please ensure only one of the 'smtp' or 'filesystem' notifier is configured"
@@ -73,14 +83,36 @@ const (
 
 // Authentication Backend Error constants.
 const (
-	errFmtAuthBackendNotConfigured = "authentication_backend: you must ensure either the 'file' or 'ldap' " +
+	errFmtAuthBackendNotConfigured = "authentication_backend: you must ensure either the 'file', 'ldap', or 'storage' " +
 		"authentication backend is configured"
-	errFmtAuthBackendMultipleConfigured = "authentication_backend: please ensure only one of the 'file' or 'ldap' " +
-		"backend is configured"
+	errFmtAuthBackendMultipleConfigured = "authentication_backend: please ensure only one of the 'file', 'ldap', or 'storage' " +
+		"backend is configured or the 'chain' option is configured"
+	errFmtAuthBackendChainInvalid = "authentication_backend: option 'chain' must only contain values from '%s' but " +
+		"contains '%s'"
+	errFmtAuthBackendChainDuplicate = "authentication_backend: option 'chain' must not contain duplicate values but " +
+		"contains '%s' more than once"
+	errFmtAuthBackendChainNotConfigured = "authentication_backend: option 'chain' contains '%s' but this backend is " +
+		"not configured"
+	errFmtAuthBackendChainMissing = "authentication_backend: option 'chain' must contain all configured backends but " +
+		"'%s' is configured and missing from it"
+	errFmtAuthBackendExtraAttributeName = "authentication_backend: extra_attributes: attribute #%d: option 'name' " +
+		"must only contain alphanumeric characters and underscores and must start with a letter but it's configured as '%s'"
+	errFmtAuthBackendExtraAttributeDuplicate = "authentication_backend: extra_attributes: attribute '%s': option '%s' " +
+		"must be unique but '%s' is configured more than once"
+	errFmtAuthBackendExtraAttributeHeader = "authentication_backend: extra_attributes: attribute '%s': option " +
+		"'header' must be a valid header name starting with 'Remote-' but it's configured as '%s'"
+	errFmtAuthBackendExtraAttributeHeaderReserved = "authentication_backend: extra_attributes: attribute '%s': option " +
+		"'header' is configured as '%s' but this header is reserved"
+	errFmtAuthBackendExtraAttributeClaimReserved = "authentication_backend: extra_attributes: attribute '%s': option " +
+		"'claim' is configured as '%s' but this claim is reserved"
 	errFmtAuthBackendRefreshInterval = "authentication_backend: option 'refresh_interval' is configured to '%s' but " +
 		"it must be either a duration notation or one of 'disable', or 'always': %w"
 	errFmtAuthBackendPasswordResetCustomURLScheme = "authentication_backend: password_reset: option 'custom_url' is" +
 		" configured to '%s' which has the scheme '%s' but the scheme must be either 'http' or 'https'"
+	errFmtAuthBackendPasswordChangeSecondFactorMaxAge = "authentication_backend: password_change: option " +
+		"'second_factor_max_age' must be greater than 0 but it's configured as '%s'"
+	errFmtAuthBackendCacheOptionNegative = "authentication_backend: cache: option '%s' must not be " +
+		"negative but it's configured as '%v'"
 
 	errFmtFileAuthBackendPathNotConfigured  = "authentication_backend: file: option 'path' is required"
 	errFmtFileAuthBackendPasswordUnknownAlg = "authentication_backend: file: password: option 'algorithm' " +
@@ -101,6 +133,17 @@ const (
 	errFmtLDAPAuthBackendTLSConfigInvalid = "authentication_backend: ldap: tls: %w"
 	errFmtLDAPAuthBackendImplementation   = "authentication_backend: ldap: option 'implementation' " +
 		errSuffixMustBeOneOf
+
+	errFmtLDAPAuthBackendPoolingOptionNegative = "authentication_backend: ldap: pooling: option '%s' must not be " +
+		"negative but it's configured as '%v'"
+
+	errFmtLDAPAuthBackendNestedGroupsMethod = "authentication_backend: ldap: nested_groups: option 'method' " +
+		errSuffixMustBeOneOf
+	errFmtLDAPAuthBackendNestedGroupsFilter = "authentication_backend: ldap: nested_groups: option 'method' is " +
+		"configured as '%s' which requires the 'groups_filter' option to contain '%s'"
+	errFmtLDAPAuthBackendNestedGroupsMaxDepth = "authentication_backend: ldap: nested_groups: option 'max_depth' " +
+		"must not be negative but it's configured as '%d'"
+
 	errFmtLDAPAuthBackendFilterReplacedPlaceholders = "authentication_backend: ldap: option " +
 		"'%s' has an invalid placeholder: '%s' has been removed, please use '%s' instead"
 	errFmtLDAPAuthBackendURLNotParsable = "authentication_backend: ldap: option " +
@@ -172,6 +215,10 @@ const (
 		"invalid value: redirect uri '%s' must have the scheme but it is absent"
 	errFmtOIDCClientInvalidPolicy = "identity_providers: oidc: client '%s': option 'policy' must be 'one_factor' " +
 		"or 'two_factor' but it is configured as '%s'"
+	errFmtOIDCClientInvalidSecondFactorMethod = "identity_providers: oidc: client '%s': option " +
+		"'second_factor_methods' must only have the values '%s' but one option is configured as '%s'"
+	errFmtOIDCClientInvalidSecondFactorMethodPolicy = "identity_providers: oidc: client '%s': option " +
+		"'second_factor_methods' is invalid: the 'authorization_policy' option must be 'two_factor' but it is configured as '%s'"
 	errFmtOIDCClientInvalidConsentMode = "identity_providers: oidc: client '%s': consent: option 'mode' must be one of " +
 		"'%s' but it is configured as '%s'"
 	errFmtOIDCClientInvalidEntry = "identity_providers: oidc: client '%s': option '%s' must only have the values " +
@@ -196,12 +243,20 @@ const (
 
 // Access Control error constants.
 const (
+	errAccessControlPathWithOptions = "access control: option 'path' must not be configured when any of the " +
+		"options 'default_policy', 'networks', or 'rules' are also configured"
+	errFmtAccessControlPathKeyNotExpected = "access control: option 'path': the file '%s' must only contain the " +
+		"'access_control' key but it contains the key '%s'"
 	errFmtAccessControlDefaultPolicyValue = "access control: option 'default_policy' must be one of '%s' but it is " +
 		"configured as '%s'"
 	errFmtAccessControlDefaultPolicyWithoutRules = "access control: 'default_policy' option '%s' is invalid: when " +
 		"no rules are specified it must be 'two_factor' or 'one_factor'"
 	errFmtAccessControlNetworkGroupIPCIDRInvalid = "access control: networks: network group '%s' is invalid: the " +
 		"network '%s' is not a valid IP or CIDR notation"
+	errFmtAccessControlNetworkGroupGeoIPInvalid = "access control: networks: network group '%s' is invalid: the " +
+		"network '%s' is invalid: %w"
+	errFmtAccessControlNetworkGroupGeoIPDatabase = "access control: networks: network group '%s' is invalid: the " +
+		"network '%s' requires the 'geoip' option '%s' to be configured"
 	errFmtAccessControlWarnNoRulesDefaultPolicy = "access control: no rules have been specified so the " +
 		"'default_policy' of '%s' is going to be applied to all requests"
 	errFmtAccessControlRuleNoDomains = "access control: rule %s: rule is invalid: must have the option " +
@@ -216,22 +271,48 @@ const (
 		"https://www.authelia.com/c/acl-match-concept-2"
 	errFmtAccessControlRuleNetworksInvalid = "access control: rule %s: the network '%s' is not a " +
 		"valid Group Name, IP, or CIDR notation"
+	errFmtAccessControlRuleNetworksGeoIPInvalid  = "access control: rule %s: the network '%s' is invalid: %w"
+	errFmtAccessControlRuleNetworksGeoIPDatabase = "access control: rule %s: the network '%s' requires the " +
+		"'geoip' option '%s' to be configured"
 	errFmtAccessControlRuleSubjectInvalid = "access control: rule %s: 'subject' option '%s' is " +
-		"invalid: must start with 'user:' or 'group:'"
+		"invalid: must start with 'user:', 'user-regex:', 'user-glob:', 'group:', 'group-regex:', 'group-glob:', " +
+		"'email:', 'email-regex:', 'email-glob:', or 'attribute:'"
+	errFmtAccessControlRuleSubjectWildcard = "access control: rule %s: 'subject' option '%s' is " +
+		"invalid: the 'user:', 'group:', and 'email:' subjects only match the exact value and must not contain the " +
+		"wildcard characters '*' or '?', use the 'user-glob:', 'group-glob:', or 'email-glob:' subjects instead"
+	errFmtAccessControlRuleSubjectPatternInvalid = "access control: rule %s: 'subject' option '%s' is " +
+		"invalid: %w"
+	errFmtAccessControlRuleSubjectAttributeInvalid = "access control: rule %s: 'subject' option '%s' is " +
+		"invalid: must be in the format 'attribute:<name>=<value>' where the name is one of the configured " +
+		"authentication_backend extra_attributes"
 	errFmtAccessControlRuleMethodInvalid = "access control: rule %s: 'methods' option '%s' is " +
 		"invalid: must be one of '%s'"
-	errFmtAccessControlRuleQueryInvalid = "access control: rule %s: 'query' option 'operator' with value '%s' is " +
+	errFmtAccessControlRuleQueryInvalid = "access control: rule %s: '%s' option 'operator' with value '%s' is " +
 		"invalid: must be one of '%s'"
-	errFmtAccessControlRuleQueryInvalidNoValue = "access control: rule %s: 'query' option '%s' is " +
+	errFmtAccessControlRuleQueryInvalidNoValue = "access control: rule %s: '%s' option '%s' is " +
 		"invalid: must have a value"
-	errFmtAccessControlRuleQueryInvalidNoValueOperator = "access control: rule %s: 'query' option '%s' is " +
+	errFmtAccessControlRuleQueryInvalidNoValueOperator = "access control: rule %s: '%s' option '%s' is " +
 		"invalid: must have a value when the operator is '%s'"
-	errFmtAccessControlRuleQueryInvalidValue = "access control: rule %s: 'query' option '%s' is " +
+	errFmtAccessControlRuleQueryInvalidValue = "access control: rule %s: '%s' option '%s' is " +
 		"invalid: must not have a value when the operator is '%s'"
-	errFmtAccessControlRuleQueryInvalidValueParse = "access control: rule %s: 'query' option '%s' is " +
+	errFmtAccessControlRuleQueryInvalidValueParse = "access control: rule %s: '%s' option '%s' is " +
 		"invalid: %w"
-	errFmtAccessControlRuleQueryInvalidValueType = "access control: rule %s: 'query' option 'value' is " +
+	errFmtAccessControlRuleQueryInvalidValueType = "access control: rule %s: '%s' option 'value' is " +
 		"invalid: expected type was string but got %T"
+	errFmtAccessControlRuleHeadersSensitive = "access control: rule %s: 'headers' option 'key' is " +
+		"invalid: the header '%s' is sensitive and is never matched"
+	errFmtAccessControlRuleScheduleInvalid         = "access control: rule %s: 'schedule' option is invalid: %w"
+	errFmtAccessControlRuleConditionInvalid        = "access control: rule %s: 'condition' option is invalid: %w"
+	errFmtAccessControlRuleConditionBypassSubjects = "access control: rule %s: 'policy' option 'bypass' is " +
+		"not supported when the 'condition' option refers to the identity or authentication of the subject"
+	errFmtAccessControlRuleSecondFactorMethodsInvalid = "access control: rule %s: 'second_factor_methods' option '%s' is " +
+		"invalid: must be one of '%s'"
+	errFmtAccessControlRuleSecondFactorMethodsPolicy = "access control: rule %s: 'second_factor_methods' option is " +
+		"invalid: the 'policy' option must be 'two_factor' but it is configured as '%s'"
+	errFmtAccessControlRuleMaxAuthenticationAgeNegative = "access control: rule %s: 'max_authentication_age' option " +
+		"'%s' is invalid: must be greater than or equal to 0 but it is configured as '%s'"
+	errFmtAccessControlRuleMaxAuthenticationAgePolicy = "access control: rule %s: 'max_authentication_age' option " +
+		"'%s' is invalid: the 'policy' option must be %s but it is configured as '%s'"
 )
 
 // Theme Error constants.
@@ -249,6 +330,7 @@ const (
 	errFmtSessionOptionRequired           = "session: option '%s' is required"
 	errFmtSessionDomainMustBeRoot         = "session: option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '%s'"
 	errFmtSessionSameSite                 = "session: option 'same_site' must be one of '%s' but is configured as '%s'"
+	errFmtSessionDomainOverlap            = "session: option 'domain' with value '%s' overlaps with the domain of cookie #%d with value '%s' which is not supported"
 	errFmtSessionSecretRequired           = "session: option 'secret' is required when using the '%s' provider"
 	errFmtSessionRedisPortRange           = "session: redis: option 'port' must be between 1 and 65535 but is configured as '%d'"
 	errFmtSessionRedisHostRequired        = "session: redis: option 'host' is required"
@@ -257,6 +339,13 @@ const (
 
 	errFmtSessionRedisSentinelMissingName     = "session: redis: high_availability: option 'sentinel_name' is required"
 	errFmtSessionRedisSentinelNodeHostMissing = "session: redis: high_availability: option 'nodes': option 'host' is required for each node but one or more nodes are missing this"
+
+	errFmtSessionCookiesDomainRequired   = "session: cookies: cookie #%d: option 'domain' is required"
+	errFmtSessionCookiesDomainMustBeRoot = "session: cookies: cookie #%d: option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '%s'"
+	errFmtSessionCookiesDomainPrefix     = "session: cookies: cookie #%d: option 'domain' has a prefix of '.' which is not supported or intended behaviour: you can use this at your own risk but we recommend removing it from the value '%s'"
+	errFmtSessionCookiesDomainOverlap    = "session: cookies: cookie #%d (domain '%s'): option 'domain' overlaps with the domain of cookie #%d with value '%s' which is not supported"
+	errFmtSessionCookiesSameSite         = "session: cookies: cookie #%d (domain '%s'): option 'same_site' must be one of '%s' but is configured as '%s'"
+	errFmtSessionCookiesAutheliaURL      = "session: cookies: cookie #%d (domain '%s'): option 'authelia_url' must be a secure URL with a host under the domain but it is configured as '%s'"
 )
 
 // Regulation Error Consts.
@@ -275,12 +364,22 @@ const (
 
 	errFmtServerPathNoForwardSlashes = "server: option 'path' must not contain any forward slashes"
 	errFmtServerPathAlphaNum         = "server: option 'path' must only contain alpha numeric characters"
+
+	errFmtServerEndpointsAuthzDecideClientID          = "server: endpoints: authz_decide: client #%d: option 'id' is required"
+	errFmtServerEndpointsAuthzDecideClientIDDuplicate = "server: endpoints: authz_decide: client '%s': option 'id' must be unique"
+	errFmtServerEndpointsAuthzDecideClientSecret      = "server: endpoints: authz_decide: client #%d: option 'secret' is required" //nolint:gosec
 )
 
 const (
 	errPasswordPolicyMultipleDefined                        = "password_policy: only a single password policy mechanism can be specified"
 	errFmtPasswordPolicyStandardMinLengthNotGreaterThanZero = "password_policy: standard: option 'min_length' must be greater than 0 but is configured as %d"
 	errFmtPasswordPolicyZXCVBNMinScoreInvalid               = "password_policy: zxcvbn: option 'min_score' is invalid: must be between 1 and 4 but it's configured as %d"
+	errFmtPasswordPolicyHistoryCountNotGreaterThanZero      = "password_policy: history: option 'count' must be greater than 0 but is configured as %d"
+	errFmtPasswordPolicyHistoryCountTooHigh                 = "password_policy: history: option 'count' must not be more than %d but is configured as %d"
+	errPasswordPolicyBreachedPathAndURL                     = "password_policy: breached: only one of the options 'path' and 'url' can be configured"
+	errFmtPasswordPolicyBreachedURLScheme                   = "password_policy: breached: option 'url' must have the 'http' or 'https' scheme but it's configured as '%s' with the scheme '%s'"
+	errFmtPasswordPolicyBreachedTimeout                     = "password_policy: breached: option 'timeout' must be greater than 0 but it's configured as '%s'"
+	errFmtPasswordPolicyBreachedThreshold                   = "password_policy: breached: option 'threshold' must be greater than 0 but is configured as %d"
 )
 
 const (
@@ -323,7 +422,9 @@ const (
 )
 
 var (
-	validLDAPImplementations = []string{schema.LDAPImplementationCustom, schema.LDAPImplementationActiveDirectory, schema.LDAPImplementationFreeIPA, schema.LDAPImplementationLLDAP}
+	validAuthBackendChainNames   = []string{schema.AuthenticationBackendFile, schema.AuthenticationBackendLDAP, schema.AuthenticationBackendStorage}
+	validLDAPImplementations     = []string{schema.LDAPImplementationCustom, schema.LDAPImplementationActiveDirectory, schema.LDAPImplementationFreeIPA, schema.LDAPImplementationLLDAP}
+	validLDAPNestedGroupsMethods = []string{schema.LDAPNestedGroupsMethodInChain, schema.LDAPNestedGroupsMethodMember, schema.LDAPNestedGroupsMethodMemberOf}
 )
 
 var (
@@ -349,6 +450,25 @@ var (
 	validACLHTTPMethodVerbs = append(validRFC7231HTTPMethodVerbs, validRFC4918HTTPMethodVerbs...)
 	validACLRulePolicies    = []string{policyBypass, policyOneFactor, policyTwoFactor, policyDeny}
 	validACLRuleOperators   = []string{operatorPresent, operatorAbsent, operatorEqual, operatorNotEqual, operatorPattern, operatorNotPattern}
+
+	validACLRuleSubjectPrefixes = []string{"user:", "user-regex:", "user-glob:", "group:", "group-regex:", "group-glob:", "email:", "email-regex:", "email-glob:", "attribute:"}
+)
+
+var (
+	reAuthBackendExtraAttributeName   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+	reAuthBackendExtraAttributeHeader = regexp.MustCompile(`^(?i)remote-[a-z0-9-]+$`)
+)
+
+var (
+	reservedAuthBackendExtraAttributeHeaders = []string{"Remote-User", "Remote-Groups", "Remote-Name", "Remote-Email"}
+	reservedAuthBackendExtraAttributeClaims  = []string{
+		oidc.ClaimJWTID, oidc.ClaimSessionID, oidc.ClaimAccessTokenHash, oidc.ClaimCodeHash, oidc.ClaimIssuedAt,
+		oidc.ClaimNotBefore, oidc.ClaimRequestedAt, oidc.ClaimExpirationTime, oidc.ClaimAuthenticationTime,
+		oidc.ClaimIssuer, oidc.ClaimSubject, oidc.ClaimNonce, oidc.ClaimAudience, oidc.ClaimGroups, oidc.ClaimFullName,
+		oidc.ClaimPreferredUsername, oidc.ClaimPreferredEmail, oidc.ClaimEmailVerified, oidc.ClaimEmailAlts,
+		oidc.ClaimAuthorizedParty, oidc.ClaimAuthenticationContextClassReference,
+		oidc.ClaimAuthenticationMethodsReference, oidc.ClaimClientIdentifier,
+	}
 )
 
 var validDefault2FAMethods = []string{"totp", "webauthn", "mobile_push"}
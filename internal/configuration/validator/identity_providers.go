@@ -175,6 +175,8 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, val *schema.
 			val.Push(fmt.Errorf(errFmtOIDCClientInvalidPolicy, client.ID, client.Policy))
 		}
 
+		validateOIDCClientSecondFactorMethods(c, config, val)
+
 		validateOIDCClientConsentMode(c, config, val)
 		validateOIDCClientSectorIdentifier(client, val)
 		validateOIDCClientScopes(c, config, val)
@@ -194,6 +196,24 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, val *schema.
 	}
 }
 
+func validateOIDCClientSecondFactorMethods(c int, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	client := config.Clients[c]
+
+	if len(client.SecondFactorMethods) == 0 {
+		return
+	}
+
+	if client.Policy != policyTwoFactor {
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidSecondFactorMethodPolicy, client.ID, client.Policy))
+	}
+
+	for _, method := range client.SecondFactorMethods {
+		if !utils.IsStringInSlice(method, validDefault2FAMethods) {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidSecondFactorMethod, client.ID, strings.Join(validDefault2FAMethods, "', '"), method))
+		}
+	}
+}
+
 func validateOIDCClientSectorIdentifier(client schema.OpenIDConnectClientConfiguration, val *schema.StructValidator) {
 	if client.SectorIdentifier.String() != "" {
 		if utils.IsURLHostComponent(client.SectorIdentifier) || utils.IsURLHostComponentWithPort(client.SectorIdentifier) {
@@ -197,6 +197,24 @@ func TestShouldRaiseErrorWhenOIDCServerClientBadValues(t *testing.T) {
 			},
 			Errors: []string{fmt.Sprintf(errFmtOIDCClientInvalidPolicy, "client-1", "a-policy")},
 		},
+		{
+			Name: "InvalidSecondFactorMethods",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:                  "client-1",
+					Secret:              MustDecodeSecret("$plaintext$a-secret"),
+					Policy:              policyOneFactor,
+					SecondFactorMethods: []string{"webauthn", "sms"},
+					RedirectURIs: []string{
+						"https://google.com",
+					},
+				},
+			},
+			Errors: []string{
+				fmt.Sprintf(errFmtOIDCClientInvalidSecondFactorMethodPolicy, "client-1", policyOneFactor),
+				fmt.Sprintf(errFmtOIDCClientInvalidSecondFactorMethod, "client-1", "totp', 'webauthn', 'mobile_push", "sms"),
+			},
+		},
 		{
 			Name: "ClientIDDuplicated",
 			Clients: []schema.OpenIDConnectClientConfiguration{
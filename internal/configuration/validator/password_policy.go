@@ -33,4 +33,44 @@ func ValidatePasswordPolicy(config *schema.PasswordPolicyConfiguration, validato
 			validator.Push(fmt.Errorf(errFmtPasswordPolicyZXCVBNMinScoreInvalid, config.ZXCVBN.MinScore))
 		}
 	}
+
+	if config.Breached.Enabled {
+		validatePasswordPolicyBreached(&config.Breached, validator)
+	}
+
+	if config.History.Enabled {
+		switch {
+		case config.History.Count == 0:
+			config.History.Count = schema.DefaultPasswordPolicyConfiguration.History.Count
+		case config.History.Count < 0:
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyHistoryCountNotGreaterThanZero, config.History.Count))
+		case config.History.Count > passwordPolicyHistoryMaxCount:
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyHistoryCountTooHigh, passwordPolicyHistoryMaxCount, config.History.Count))
+		}
+	}
+}
+
+func validatePasswordPolicyBreached(config *schema.PasswordPolicyBreachedParams, validator *schema.StructValidator) {
+	switch {
+	case config.Path != "" && config.URL.String() != "":
+		validator.Push(fmt.Errorf(errPasswordPolicyBreachedPathAndURL))
+	case config.Path == "" && config.URL.String() == "":
+		config.URL = schema.DefaultPasswordPolicyConfiguration.Breached.URL
+	case config.Path == "" && config.URL.Scheme != schemeHTTP && config.URL.Scheme != schemeHTTPS:
+		validator.Push(fmt.Errorf(errFmtPasswordPolicyBreachedURLScheme, config.URL.String(), config.URL.Scheme))
+	}
+
+	switch {
+	case config.Timeout == 0:
+		config.Timeout = schema.DefaultPasswordPolicyConfiguration.Breached.Timeout
+	case config.Timeout < 0:
+		validator.Push(fmt.Errorf(errFmtPasswordPolicyBreachedTimeout, config.Timeout))
+	}
+
+	switch {
+	case config.Threshold == 0:
+		config.Threshold = schema.DefaultPasswordPolicyConfiguration.Breached.Threshold
+	case config.Threshold < 0:
+		validator.Push(fmt.Errorf(errFmtPasswordPolicyBreachedThreshold, config.Threshold))
+	}
 }
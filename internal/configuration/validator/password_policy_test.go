@@ -2,7 +2,9 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -122,6 +124,135 @@ func TestValidatePasswordPolicy(t *testing.T) {
 				"password_policy: zxcvbn: option 'min_score' is invalid: must be between 1 and 4 but it's configured as 5",
 			},
 		},
+		{
+			desc: "ShouldSetDefaultHistory",
+			have: &schema.PasswordPolicyConfiguration{
+				History: schema.PasswordPolicyHistoryParams{
+					Enabled: true,
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				History: schema.PasswordPolicyHistoryParams{
+					Enabled: true,
+					Count:   5,
+				},
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorsHistoryCountNegative",
+			have: &schema.PasswordPolicyConfiguration{
+				History: schema.PasswordPolicyHistoryParams{
+					Enabled: true,
+					Count:   -1,
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				History: schema.PasswordPolicyHistoryParams{
+					Enabled: true,
+					Count:   -1,
+				},
+			},
+			expectedErrs: []string{
+				"password_policy: history: option 'count' must be greater than 0 but is configured as -1",
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorsHistoryCountTooHigh",
+			have: &schema.PasswordPolicyConfiguration{
+				History: schema.PasswordPolicyHistoryParams{
+					Enabled: true,
+					Count:   25,
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				History: schema.PasswordPolicyHistoryParams{
+					Enabled: true,
+					Count:   25,
+				},
+			},
+			expectedErrs: []string{
+				"password_policy: history: option 'count' must not be more than 24 but is configured as 25",
+			},
+		},
+		{
+			desc: "ShouldSetDefaultBreached",
+			have: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled: true,
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled:   true,
+					URL:       url.URL{Scheme: "https", Host: "api.pwnedpasswords.com"},
+					Timeout:   time.Second * 5,
+					Threshold: 1,
+				},
+			},
+		},
+		{
+			desc: "ShouldNotSetDefaultBreachedURLWithPath",
+			have: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled: true,
+					Path:    "/data/pwned-passwords.txt",
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled:   true,
+					Path:      "/data/pwned-passwords.txt",
+					Timeout:   time.Second * 5,
+					Threshold: 1,
+				},
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorsBreachedMisconfigured",
+			have: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled:   true,
+					Path:      "/data/pwned-passwords.txt",
+					URL:       url.URL{Scheme: "https", Host: "hibp.example.com"},
+					Timeout:   -1,
+					Threshold: -1,
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled:   true,
+					Path:      "/data/pwned-passwords.txt",
+					URL:       url.URL{Scheme: "https", Host: "hibp.example.com"},
+					Timeout:   -1,
+					Threshold: -1,
+				},
+			},
+			expectedErrs: []string{
+				"password_policy: breached: only one of the options 'path' and 'url' can be configured",
+				"password_policy: breached: option 'timeout' must be greater than 0 but it's configured as '-1ns'",
+				"password_policy: breached: option 'threshold' must be greater than 0 but is configured as -1",
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorsBreachedURLScheme",
+			have: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled: true,
+					URL:     url.URL{Scheme: "ftp", Host: "hibp.example.com"},
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled:   true,
+					URL:       url.URL{Scheme: "ftp", Host: "hibp.example.com"},
+					Timeout:   time.Second * 5,
+					Threshold: 1,
+				},
+			},
+			expectedErrs: []string{
+				"password_policy: breached: option 'url' must have the 'http' or 'https' scheme but it's configured as 'ftp://hibp.example.com' with the scheme 'ftp'",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -138,6 +269,8 @@ func TestValidatePasswordPolicy(t *testing.T) {
 			assert.Equal(t, tc.expected.Standard.RequireUppercase, tc.have.Standard.RequireUppercase)
 			assert.Equal(t, tc.expected.Standard.RequireLowercase, tc.have.Standard.RequireLowercase)
 			assert.Equal(t, tc.expected.ZXCVBN.MinScore, tc.have.ZXCVBN.MinScore)
+			assert.Equal(t, tc.expected.History.Count, tc.have.History.Count)
+			assert.Equal(t, tc.expected.Breached, tc.have.Breached)
 
 			errs := validator.Errors()
 			require.Len(t, errs, len(tc.expectedErrs))
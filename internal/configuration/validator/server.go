@@ -89,4 +89,25 @@ func ValidateServer(config *schema.Configuration, validator *schema.StructValida
 	if config.Server.Timeouts.Idle <= 0 {
 		config.Server.Timeouts.Idle = schema.DefaultServerConfiguration.Timeouts.Idle
 	}
+
+	validateServerEndpointsAuthzDecide(config, validator)
+}
+
+func validateServerEndpointsAuthzDecide(config *schema.Configuration, validator *schema.StructValidator) {
+	var ids []string
+
+	for i, client := range config.Server.Endpoints.AuthzDecide.Clients {
+		switch {
+		case client.ID == "":
+			validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzDecideClientID, i+1))
+		case utils.IsStringInSlice(client.ID, ids):
+			validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzDecideClientIDDuplicate, client.ID))
+		default:
+			ids = append(ids, client.ID)
+		}
+
+		if client.Secret == nil {
+			validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzDecideClientSecret, i+1))
+		}
+	}
 }
@@ -278,3 +278,26 @@ func TestShouldValidateAndUpdatePort(t *testing.T) {
 	require.Len(t, validator.Errors(), 0)
 	assert.Equal(t, 9091, config.Server.Port)
 }
+
+func TestShouldRaiseErrorOnInvalidAuthzDecideClients(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+
+	digest, err := schema.DecodePasswordDigest("$plaintext$secret")
+	require.NoError(t, err)
+
+	config.Server.Endpoints.AuthzDecide.Clients = []schema.ServerEndpointsAuthzDecideClient{
+		{ID: "app", Secret: digest},
+		{ID: "app", Secret: digest},
+		{Secret: digest},
+		{ID: "intranet"},
+	}
+
+	ValidateServer(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "server: endpoints: authz_decide: client 'app': option 'id' must be unique")
+	assert.EqualError(t, validator.Errors()[1], "server: endpoints: authz_decide: client #3: option 'id' is required")
+	assert.EqualError(t, validator.Errors()[2], "server: endpoints: authz_decide: client #4: option 'secret' is required")
+}
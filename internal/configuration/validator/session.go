@@ -39,21 +39,121 @@ func validateSession(config *schema.SessionConfiguration, validator *schema.Stru
 		config.RememberMeDuration = schema.DefaultSessionConfiguration.RememberMeDuration // 1 month.
 	}
 
-	if config.Domain == "" {
+	if config.SameSite == "" {
+		config.SameSite = schema.DefaultSessionConfiguration.SameSite
+	} else if !utils.IsStringInSlice(config.SameSite, validSessionSameSiteValues) {
+		validator.Push(fmt.Errorf(errFmtSessionSameSite, strings.Join(validSessionSameSiteValues, "', '"), config.SameSite))
+	}
+
+	switch {
+	case config.Domain != "":
+		validateSessionDomainLegacy(config, validator)
+	case len(config.Cookies) == 0:
 		validator.Push(fmt.Errorf(errFmtSessionOptionRequired, "domain"))
-	} else if strings.HasPrefix(config.Domain, ".") {
+	}
+
+	for i := range config.Cookies {
+		validateSessionCookie(i, config, validator)
+	}
+
+	validateSessionCookieDomainsOverlap(config, validator)
+
+	if config.Domain != "" && !hasSessionCookieDomain(config.Cookies, config.Domain) {
+		// The legacy domain is the first cookie domain so it's the default when the request host is not protected.
+		config.Cookies = append([]schema.SessionCookieConfiguration{{
+			Domain:             config.Domain,
+			Name:               config.Name,
+			SameSite:           config.SameSite,
+			Expiration:         config.Expiration,
+			Inactivity:         config.Inactivity,
+			RememberMeDuration: config.RememberMeDuration,
+		}}, config.Cookies...)
+	}
+}
+
+func validateSessionDomainLegacy(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if strings.HasPrefix(config.Domain, ".") {
 		validator.PushWarning(fmt.Errorf("session: option 'domain' has a prefix of '.' which is not supported or intended behaviour: you can use this at your own risk but we recommend removing it"))
 	}
 
 	if strings.HasPrefix(config.Domain, "*.") {
 		validator.Push(fmt.Errorf(errFmtSessionDomainMustBeRoot, config.Domain))
 	}
+}
 
-	if config.SameSite == "" {
-		config.SameSite = schema.DefaultSessionConfiguration.SameSite
-	} else if !utils.IsStringInSlice(config.SameSite, validSessionSameSiteValues) {
-		validator.Push(fmt.Errorf(errFmtSessionSameSite, strings.Join(validSessionSameSiteValues, "', '"), config.SameSite))
+func validateSessionCookie(i int, config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	cookie := &config.Cookies[i]
+
+	switch {
+	case cookie.Domain == "":
+		validator.Push(fmt.Errorf(errFmtSessionCookiesDomainRequired, i+1))
+	case strings.HasPrefix(cookie.Domain, "*."):
+		validator.Push(fmt.Errorf(errFmtSessionCookiesDomainMustBeRoot, i+1, cookie.Domain))
+	case strings.HasPrefix(cookie.Domain, "."):
+		validator.PushWarning(fmt.Errorf(errFmtSessionCookiesDomainPrefix, i+1, cookie.Domain))
+	}
+
+	if cookie.Name == "" {
+		cookie.Name = config.Name
+	}
+
+	if cookie.SameSite == "" {
+		cookie.SameSite = config.SameSite
+	} else if !utils.IsStringInSlice(cookie.SameSite, validSessionSameSiteValues) {
+		validator.Push(fmt.Errorf(errFmtSessionCookiesSameSite, i+1, cookie.Domain, strings.Join(validSessionSameSiteValues, "', '"), cookie.SameSite))
 	}
+
+	if cookie.Expiration <= 0 {
+		cookie.Expiration = config.Expiration
+	}
+
+	if cookie.Inactivity <= 0 {
+		cookie.Inactivity = config.Inactivity
+	}
+
+	if cookie.RememberMeDuration <= 0 && cookie.RememberMeDuration != schema.RememberMeDisabled {
+		cookie.RememberMeDuration = config.RememberMeDuration
+	}
+
+	if cookie.AutheliaURL.String() != "" && cookie.Domain != "" && !utils.IsURISafeRedirection(&cookie.AutheliaURL, strings.TrimPrefix(cookie.Domain, ".")) {
+		validator.Push(fmt.Errorf(errFmtSessionCookiesAutheliaURL, i+1, cookie.Domain, cookie.AutheliaURL.String()))
+	}
+}
+
+func validateSessionCookieDomainsOverlap(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	for i := range config.Cookies {
+		if config.Cookies[i].Domain == "" {
+			continue
+		}
+
+		if config.Domain != "" && config.Domain != config.Cookies[i].Domain && isSessionDomainOverlap(config.Domain, config.Cookies[i].Domain) {
+			validator.Push(fmt.Errorf(errFmtSessionDomainOverlap, config.Domain, i+1, config.Cookies[i].Domain))
+		}
+
+		for j := i + 1; j < len(config.Cookies); j++ {
+			if config.Cookies[j].Domain != "" && isSessionDomainOverlap(config.Cookies[i].Domain, config.Cookies[j].Domain) {
+				validator.Push(fmt.Errorf(errFmtSessionCookiesDomainOverlap, j+1, config.Cookies[j].Domain, i+1, config.Cookies[i].Domain))
+			}
+		}
+	}
+}
+
+// isSessionDomainOverlap returns true if the domains are equal or one of the domains is a subdomain of the other, in
+// which case the browser would send both cookies to the same host.
+func isSessionDomainOverlap(a, b string) bool {
+	a, b = strings.ToLower(strings.TrimPrefix(a, ".")), strings.ToLower(strings.TrimPrefix(b, "."))
+
+	return a == b || strings.HasSuffix(a, "."+b) || strings.HasSuffix(b, "."+a)
+}
+
+func hasSessionCookieDomain(cookies []schema.SessionCookieConfiguration, domain string) bool {
+	for _, cookie := range cookies {
+		if cookie.Domain == domain {
+			return true
+		}
+	}
+
+	return false
 }
 
 func validateRedisCommon(config *schema.SessionConfiguration, validator *schema.StructValidator) {
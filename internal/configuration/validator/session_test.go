@@ -3,7 +3,9 @@ package validator
 import (
 	"crypto/tls"
 	"fmt"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -509,3 +511,182 @@ func TestShouldSetDefaultRememberMeDuration(t *testing.T) {
 	assert.False(t, validator.HasErrors())
 	assert.Equal(t, config.RememberMeDuration, schema.DefaultSessionConfiguration.RememberMeDuration)
 }
+
+func TestShouldConvertLegacyDomainToCookie(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.RememberMeDuration = schema.RememberMeDisabled
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+
+	require.Len(t, config.Cookies, 1)
+	assert.Equal(t, schema.SessionCookieConfiguration{
+		Domain:             examplecom,
+		Name:               schema.DefaultSessionConfiguration.Name,
+		SameSite:           schema.DefaultSessionConfiguration.SameSite,
+		Expiration:         schema.DefaultSessionConfiguration.Expiration,
+		Inactivity:         schema.DefaultSessionConfiguration.Inactivity,
+		RememberMeDuration: schema.RememberMeDisabled,
+	}, config.Cookies[0])
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasErrors())
+	assert.Len(t, config.Cookies, 1)
+}
+
+func TestShouldSetDefaultSessionCookieValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Domain = ""
+	config.Name = "default_session"
+	config.Inactivity = time.Minute
+	config.Cookies = []schema.SessionCookieConfiguration{
+		{
+			Domain:      "example.org",
+			AutheliaURL: url.URL{Scheme: "https", Host: "auth.example.org"},
+		},
+		{
+			Domain:             examplecom,
+			Name:               "example_session",
+			SameSite:           "strict",
+			Expiration:         time.Hour * 2,
+			Inactivity:         time.Minute * 10,
+			RememberMeDuration: schema.RememberMeDisabled,
+		},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+
+	require.Len(t, config.Cookies, 2)
+
+	assert.Equal(t, "example.org", config.Cookies[0].Domain)
+	assert.Equal(t, "default_session", config.Cookies[0].Name)
+	assert.Equal(t, schema.DefaultSessionConfiguration.SameSite, config.Cookies[0].SameSite)
+	assert.Equal(t, schema.DefaultSessionConfiguration.Expiration, config.Cookies[0].Expiration)
+	assert.Equal(t, time.Minute, config.Cookies[0].Inactivity)
+	assert.Equal(t, schema.DefaultSessionConfiguration.RememberMeDuration, config.Cookies[0].RememberMeDuration)
+
+	assert.Equal(t, "example_session", config.Cookies[1].Name)
+	assert.Equal(t, "strict", config.Cookies[1].SameSite)
+	assert.Equal(t, time.Hour*2, config.Cookies[1].Expiration)
+	assert.Equal(t, time.Minute*10, config.Cookies[1].Inactivity)
+	assert.Equal(t, schema.RememberMeDisabled, config.Cookies[1].RememberMeDuration)
+}
+
+func TestShouldRaiseErrorsWhenSessionCookiesInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		domain   string
+		have     []schema.SessionCookieConfiguration
+		errors   []string
+		warnings []string
+	}{
+		{
+			"ShouldRaiseErrorDomainRequired",
+			"",
+			[]schema.SessionCookieConfiguration{{Name: "session"}},
+			[]string{"session: cookies: cookie #1: option 'domain' is required"},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorWildcardDomain",
+			"",
+			[]schema.SessionCookieConfiguration{{Domain: "*.example.com"}},
+			[]string{"session: cookies: cookie #1: option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '*.example.com'"},
+			nil,
+		},
+		{
+			"ShouldWarnDomainPrefix",
+			"",
+			[]schema.SessionCookieConfiguration{{Domain: ".example.com"}},
+			nil,
+			[]string{"session: cookies: cookie #1: option 'domain' has a prefix of '.' which is not supported or intended behaviour: you can use this at your own risk but we recommend removing it from the value '.example.com'"},
+		},
+		{
+			"ShouldRaiseErrorSameSite",
+			"",
+			[]schema.SessionCookieConfiguration{{Domain: examplecom, SameSite: "NOne"}},
+			[]string{"session: cookies: cookie #1 (domain 'example.com'): option 'same_site' must be one of 'none', 'lax', 'strict' but is configured as 'NOne'"},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorAutheliaURLInsecure",
+			"",
+			[]schema.SessionCookieConfiguration{{Domain: examplecom, AutheliaURL: url.URL{Scheme: "http", Host: "auth.example.com"}}},
+			[]string{"session: cookies: cookie #1 (domain 'example.com'): option 'authelia_url' must be a secure URL with a host under the domain but it is configured as 'http://auth.example.com'"},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorAutheliaURLOtherDomain",
+			"",
+			[]schema.SessionCookieConfiguration{{Domain: examplecom, AutheliaURL: url.URL{Scheme: "https", Host: "auth.example.org"}}},
+			[]string{"session: cookies: cookie #1 (domain 'example.com'): option 'authelia_url' must be a secure URL with a host under the domain but it is configured as 'https://auth.example.org'"},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorDuplicateDomain",
+			"",
+			[]schema.SessionCookieConfiguration{{Domain: examplecom}, {Domain: examplecom}},
+			[]string{"session: cookies: cookie #2 (domain 'example.com'): option 'domain' overlaps with the domain of cookie #1 with value 'example.com' which is not supported"},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorSubdomain",
+			"",
+			[]schema.SessionCookieConfiguration{{Domain: "app.example.com"}, {Domain: examplecom}},
+			[]string{"session: cookies: cookie #2 (domain 'example.com'): option 'domain' overlaps with the domain of cookie #1 with value 'app.example.com' which is not supported"},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorLegacyDomainOverlap",
+			"app.example.com",
+			[]schema.SessionCookieConfiguration{{Domain: examplecom}},
+			[]string{"session: option 'domain' with value 'app.example.com' overlaps with the domain of cookie #1 with value 'example.com' which is not supported"},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultSessionConfig()
+			config.Domain = tc.domain
+			config.Cookies = tc.have
+
+			ValidateSession(&config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.errors))
+			require.Len(t, validator.Warnings(), len(tc.warnings))
+
+			for i, err := range tc.errors {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+
+			for i, warning := range tc.warnings {
+				assert.EqualError(t, validator.Warnings()[i], warning)
+			}
+		})
+	}
+}
+
+func TestShouldAddLegacyDomainAsFirstCookie(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Cookies = []schema.SessionCookieConfiguration{{Domain: "example.org"}}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+
+	require.Len(t, config.Cookies, 2)
+	assert.Equal(t, examplecom, config.Cookies[0].Domain)
+	assert.Equal(t, "example.org", config.Cookies[1].Domain)
+}
@@ -0,0 +1,191 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+// NewProvider creates a new Provider for the configured GeoIP databases. The databases are loaded by the StartupCheck.
+func NewProvider(config *schema.GeoIPConfiguration) (provider *Provider) {
+	return &Provider{
+		config: config,
+		log:    logging.Logger(),
+	}
+}
+
+// Provider resolves the country and autonomous system of an IP from the configured MaxMind DB files.
+type Provider struct {
+	config *schema.GeoIPConfiguration
+	log    *logrus.Logger
+
+	mu      sync.RWMutex
+	country *database
+	asn     *database
+}
+
+// Record is the GeoIP information for an IP.
+type Record struct {
+	// Country is the upper case ISO 3166-1 alpha-2 code of the country.
+	Country string
+
+	// ASN is the autonomous system number.
+	ASN uint
+
+	// Organization is the organization associated with the autonomous system.
+	Organization string
+}
+
+// StartupCheck loads the databases.
+func (p *Provider) StartupCheck() (err error) {
+	_, err = p.Reload()
+
+	return err
+}
+
+// Reload the databases. The existing databases are kept if any of the databases fail to load.
+func (p *Provider) Reload() (reloaded bool, err error) {
+	var country, asn *database
+
+	if p.config.CountryDatabase != "" {
+		if country, err = openDatabase(p.config.CountryDatabase); err != nil {
+			return false, err
+		}
+	}
+
+	switch {
+	case p.config.ASNDatabase == "":
+		break
+	case p.config.ASNDatabase == p.config.CountryDatabase:
+		asn = country
+	default:
+		if asn, err = openDatabase(p.config.ASNDatabase); err != nil {
+			return false, err
+		}
+	}
+
+	p.mu.Lock()
+
+	p.country, p.asn = country, asn
+
+	p.mu.Unlock()
+
+	return true, nil
+}
+
+// Paths returns the paths of the database files.
+func (p *Provider) Paths() (paths []string) {
+	if p.config.CountryDatabase != "" {
+		paths = append(paths, p.config.CountryDatabase)
+	}
+
+	if p.config.ASNDatabase != "" && p.config.ASNDatabase != p.config.CountryDatabase {
+		paths = append(paths, p.config.ASNDatabase)
+	}
+
+	return paths
+}
+
+// Lookup the GeoIP information for an IP. The fields of the record are empty if the information is not available.
+func (p *Provider) Lookup(ip net.IP) (record Record) {
+	if p == nil || ip == nil {
+		return record
+	}
+
+	p.mu.RLock()
+
+	country, asn := p.country, p.asn
+
+	p.mu.RUnlock()
+
+	if country != nil {
+		record.Country = p.lookup(country, ip).Country
+	}
+
+	if asn != nil {
+		r := p.lookup(asn, ip)
+
+		record.ASN, record.Organization = r.ASN, r.Organization
+	}
+
+	return record
+}
+
+func (p *Provider) lookup(db *database, ip net.IP) (record Record) {
+	offset, err := db.reader.LookupOffset(ip)
+
+	switch {
+	case err != nil:
+		p.log.WithError(err).Debug("Error occurred looking up the GeoIP information")
+
+		return record
+	case offset == maxminddb.NotFound:
+		return record
+	}
+
+	if cached, ok := db.cache.Load(offset); ok {
+		return cached.(Record)
+	}
+
+	var value databaseRecord
+
+	if err = db.reader.Decode(offset, &value); err != nil {
+		p.log.WithError(err).Debug("Error occurred decoding the GeoIP information")
+
+		return record
+	}
+
+	record = Record{
+		Country:      strings.ToUpper(value.Country.ISOCode),
+		ASN:          value.ASN,
+		Organization: value.Organization,
+	}
+
+	db.cache.Store(offset, record)
+
+	return record
+}
+
+// openDatabase reads the MaxMind DB file at the path into memory. The file isn't memory mapped so the previous database
+// doesn't have to be closed when the databases are reloaded while lookups are using it.
+func openDatabase(path string) (db *database, err error) {
+	var (
+		data   []byte
+		reader *maxminddb.Reader
+	)
+
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("error loading the GeoIP database '%s': %w", path, err)
+	}
+
+	if reader, err = maxminddb.FromBytes(data); err != nil {
+		return nil, fmt.Errorf("error loading the GeoIP database '%s': %w", path, err)
+	}
+
+	return &database{reader: reader}, nil
+}
+
+// database is a maxminddb.Reader with a cache of the records decoded from it. The records are cached by their offset in
+// the data section which is shared by every network with the same data, so the cache is bounded by the database size.
+type database struct {
+	reader *maxminddb.Reader
+	cache  sync.Map
+}
+
+// databaseRecord is the subset of the GeoLite2 / GeoIP2 Country and ASN database records used by the Provider.
+type databaseRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+
+	ASN          uint   `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+}
@@ -0,0 +1,333 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestProviderShouldLookup(t *testing.T) {
+	dir := t.TempDir()
+
+	country, asn := filepath.Join(dir, "country.mmdb"), filepath.Join(dir, "asn.mmdb")
+
+	require.NoError(t, os.WriteFile(country, newTestDatabase(t, 24, map[string]map[string]any{
+		"1.1.1.0/24":    {"country": map[string]any{"iso_code": "AU"}},
+		"2001:db8::/32": {"country": map[string]any{"iso_code": "de"}},
+	}), 0600))
+
+	require.NoError(t, os.WriteFile(asn, newTestDatabase(t, 28, map[string]map[string]any{
+		"1.1.1.0/24": {"autonomous_system_number": uint32(13335), "autonomous_system_organization": "CLOUDFLARENET"},
+	}), 0600))
+
+	provider := NewProvider(&schema.GeoIPConfiguration{CountryDatabase: country, ASNDatabase: asn})
+
+	assert.Equal(t, []string{country, asn}, provider.Paths())
+	assert.Equal(t, Record{}, provider.Lookup(net.ParseIP("1.1.1.1")))
+
+	require.NoError(t, provider.StartupCheck())
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, Record{Country: "AU", ASN: 13335, Organization: "CLOUDFLARENET"}, provider.Lookup(net.ParseIP("1.1.1.1")))
+	}
+
+	assert.Equal(t, Record{Country: "DE"}, provider.Lookup(net.ParseIP("2001:db8::1")))
+	assert.Equal(t, Record{}, provider.Lookup(net.ParseIP("8.8.8.8")))
+	assert.Equal(t, Record{}, provider.Lookup(nil))
+
+	require.NoError(t, os.WriteFile(country, newTestDatabase(t, 24, map[string]map[string]any{
+		"1.1.1.0/24": {"country": map[string]any{"iso_code": "US"}},
+	}), 0600))
+
+	reloaded, err := provider.Reload()
+
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, Record{Country: "US", ASN: 13335, Organization: "CLOUDFLARENET"}, provider.Lookup(net.ParseIP("1.1.1.1")))
+
+	require.NoError(t, os.WriteFile(asn, []byte("invalid"), 0600))
+
+	reloaded, err = provider.Reload()
+
+	assert.False(t, reloaded)
+	assert.EqualError(t, err, "error loading the GeoIP database '"+asn+"': error opening database: invalid MaxMind DB file")
+	assert.Equal(t, Record{Country: "US", ASN: 13335, Organization: "CLOUDFLARENET"}, provider.Lookup(net.ParseIP("1.1.1.1")))
+}
+
+func TestProviderShouldLookupCombinedDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "combined.mmdb")
+
+	require.NoError(t, os.WriteFile(path, newTestDatabase(t, 32, newTestDatabaseNetworks()), 0600))
+
+	provider := NewProvider(&schema.GeoIPConfiguration{CountryDatabase: path, ASNDatabase: path})
+
+	assert.Equal(t, []string{path}, provider.Paths())
+
+	require.NoError(t, provider.StartupCheck())
+
+	assert.Equal(t, Record{Country: "AU", ASN: 13335, Organization: "CLOUDFLARENET"}, provider.Lookup(net.ParseIP("1.1.1.1")))
+	assert.Equal(t, Record{Country: "GB"}, provider.Lookup(net.ParseIP("81.2.69.1")))
+}
+
+func TestProviderShouldLookupRecordSizes(t *testing.T) {
+	for _, size := range []int{24, 28, 32} {
+		path := filepath.Join(t.TempDir(), "combined.mmdb")
+
+		require.NoError(t, os.WriteFile(path, newTestDatabase(t, size, newTestDatabaseNetworks()), 0600))
+
+		provider := NewProvider(&schema.GeoIPConfiguration{CountryDatabase: path, ASNDatabase: path})
+
+		require.NoError(t, provider.StartupCheck())
+
+		assert.Equal(t, Record{Country: "AU", ASN: 13335, Organization: "CLOUDFLARENET"}, provider.Lookup(net.ParseIP("1.1.1.1")))
+		assert.Equal(t, Record{Country: "DE"}, provider.Lookup(net.ParseIP("2001:db8::1")))
+		assert.Equal(t, Record{Country: "GB"}, provider.Lookup(net.ParseIP("81.2.69.254")))
+		assert.Equal(t, Record{}, provider.Lookup(net.ParseIP("8.8.8.8")))
+	}
+}
+
+func TestProviderShouldErrorInvalidDatabases(t *testing.T) {
+	dir := t.TempDir()
+
+	invalid, missing := filepath.Join(dir, "invalid.mmdb"), filepath.Join(dir, "missing.mmdb")
+
+	require.NoError(t, os.WriteFile(invalid, []byte("not a database"), 0600))
+
+	err := NewProvider(&schema.GeoIPConfiguration{CountryDatabase: invalid}).StartupCheck()
+	assert.EqualError(t, err, "error loading the GeoIP database '"+invalid+"': error opening database: invalid MaxMind DB file")
+
+	err = NewProvider(&schema.GeoIPConfiguration{ASNDatabase: missing}).StartupCheck()
+	assert.EqualError(t, err, "error loading the GeoIP database '"+missing+"': open "+missing+": no such file or directory")
+
+	require.NoError(t, os.WriteFile(invalid, testEncode(append([]byte{}, testMetadataStartMarker...), map[string]any{
+		"binary_format_major_version": uint16(2),
+		"ip_version":                  uint16(6),
+		"node_count":                  uint32(10),
+		"record_size":                 uint16(24),
+	}), 0600))
+
+	err = NewProvider(&schema.GeoIPConfiguration{CountryDatabase: invalid}).StartupCheck()
+	assert.EqualError(t, err, "error loading the GeoIP database '"+invalid+"': the MaxMind DB contains invalid metadata")
+}
+
+func TestProviderShouldLookupNilProvider(t *testing.T) {
+	var provider *Provider
+
+	assert.Equal(t, Record{}, provider.Lookup(net.ParseIP("1.1.1.1")))
+}
+
+// The MaxMind DB constants used to build the test databases.
+var testMetadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+const testDataSectionSeparatorSize = 16
+
+const (
+	testTypeString uint = 2
+	testTypeUint16 uint = 5
+	testTypeUint32 uint = 6
+	testTypeMap    uint = 7
+	testTypeUint64 uint = 9
+	testTypeArray  uint = 11
+	testTypeBool   uint = 14
+)
+
+type testNode struct {
+	children [2]int
+	data     [2]int
+}
+
+// newTestDatabase builds an IPv6 MaxMind DB file with the provided networks and data using the provided record size.
+func newTestDatabase(t *testing.T, recordSize int, networks map[string]map[string]any) []byte {
+	nodes := []*testNode{{data: [2]int{-1, -1}}}
+
+	var data []byte
+
+	cidrs := make([]string, 0, len(networks))
+
+	for cidr := range networks {
+		cidrs = append(cidrs, cidr)
+	}
+
+	sort.Strings(cidrs)
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+
+		ones, bits := network.Mask.Size()
+
+		ip := network.IP.To16()
+
+		if bits == 32 {
+			ip, ones = append(make([]byte, 12), network.IP.To4()...), ones+96
+		}
+
+		offset := len(data)
+
+		data = testEncode(data, networks[cidr])
+
+		node := 0
+
+		for i := 0; i < ones; i++ {
+			bit := (ip[i>>3] >> (7 - uint(i&7))) & 1
+
+			if i == ones-1 {
+				nodes[node].data[bit] = offset
+
+				break
+			}
+
+			if nodes[node].children[bit] == 0 {
+				nodes = append(nodes, &testNode{data: [2]int{-1, -1}})
+				nodes[node].children[bit] = len(nodes) - 1
+			}
+
+			node = nodes[node].children[bit]
+		}
+	}
+
+	count := len(nodes)
+
+	var tree []byte
+
+	for _, node := range nodes {
+		var records [2]uint
+
+		for bit := 0; bit < 2; bit++ {
+			switch {
+			case node.data[bit] != -1:
+				records[bit] = uint(count + testDataSectionSeparatorSize + node.data[bit])
+			case node.children[bit] != 0:
+				records[bit] = uint(node.children[bit])
+			default:
+				records[bit] = uint(count)
+			}
+		}
+
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(records[0]>>16), byte(records[0]>>8), byte(records[0]), byte(records[1]>>16), byte(records[1]>>8), byte(records[1]))
+		case 28:
+			tree = append(tree, byte(records[0]>>16), byte(records[0]>>8), byte(records[0]), byte((records[0]>>20)&0xF0|(records[1]>>24)&0x0F), byte(records[1]>>16), byte(records[1]>>8), byte(records[1]))
+		default:
+			tree = binary.BigEndian.AppendUint32(tree, uint32(records[0]))
+			tree = binary.BigEndian.AppendUint32(tree, uint32(records[1]))
+		}
+	}
+
+	file := append(tree, make([]byte, testDataSectionSeparatorSize)...)
+	file = append(file, data...)
+	file = append(file, testMetadataStartMarker...)
+
+	return testEncode(file, map[string]any{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"database_type":               "Test",
+		"ip_version":                  uint16(6),
+		"node_count":                  uint32(count),
+		"record_size":                 uint16(recordSize),
+	})
+}
+
+func testEncode(b []byte, value any) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(testEncodeControl(b, testTypeString, len(v)), v...)
+	case uint16:
+		return testEncodeUint(b, testTypeUint16, uint64(v))
+	case uint32:
+		return testEncodeUint(b, testTypeUint32, uint64(v))
+	case uint64:
+		return testEncodeUint(b, testTypeUint64, v)
+	case bool:
+		if v {
+			return testEncodeControl(b, testTypeBool, 1)
+		}
+
+		return testEncodeControl(b, testTypeBool, 0)
+	case []any:
+		b = testEncodeControl(b, testTypeArray, len(v))
+
+		for _, item := range v {
+			b = testEncode(b, item)
+		}
+
+		return b
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		b = testEncodeControl(b, testTypeMap, len(v))
+
+		for _, key := range keys {
+			b = testEncode(b, key)
+			b = testEncode(b, v[key])
+		}
+
+		return b
+	default:
+		panic("unsupported type")
+	}
+}
+
+func testEncodeUint(b []byte, kind uint, value uint64) []byte {
+	var payload []byte
+
+	for ; value != 0; value >>= 8 {
+		payload = append([]byte{byte(value)}, payload...)
+	}
+
+	return append(testEncodeControl(b, kind, len(payload)), payload...)
+}
+
+func testEncodeControl(b []byte, kind uint, size int) []byte {
+	var extra []byte
+
+	switch {
+	case size >= 65821:
+		extra, size = []byte{byte((size - 65821) >> 16), byte((size - 65821) >> 8), byte(size - 65821)}, 31
+	case size >= 285:
+		extra, size = []byte{byte((size - 285) >> 8), byte(size - 285)}, 30
+	case size >= 29:
+		extra, size = []byte{byte(size - 29)}, 29
+	}
+
+	if kind > 7 {
+		b = append(b, byte(size), byte(kind-7))
+	} else {
+		b = append(b, byte(kind<<5)|byte(size))
+	}
+
+	return append(b, extra...)
+}
+
+func newTestDatabaseNetworks() map[string]map[string]any {
+	return map[string]map[string]any{
+		"1.1.1.0/24": {
+			"country":                        map[string]any{"iso_code": "AU", "names": map[string]any{"en": "Australia"}},
+			"autonomous_system_number":       uint32(13335),
+			"autonomous_system_organization": "CLOUDFLARENET",
+		},
+		"81.2.69.0/24": {
+			"country": map[string]any{"iso_code": "GB"},
+		},
+		"2001:db8::/32": {
+			"country":     map[string]any{"iso_code": "de"},
+			"is_eu":       true,
+			"subdivision": []any{"BE", "BY"},
+		},
+	}
+}
@@ -34,6 +34,9 @@ const (
 	queryArgConsentID  = "consent_id"
 	queryArgWorkflow   = "workflow"
 	queryArgWorkflowID = "workflow_id"
+
+	queryArgSecondFactorMethods = "sfm"
+	queryArgReauthenticate      = "reauth"
 )
 
 var (
@@ -56,14 +59,30 @@ const (
 	messageUnableToRegisterOneTimePassword = "Unable to set up one-time passwords." //nolint:gosec
 	messageUnableToRegisterSecurityKey     = "Unable to register your security key."
 	messageUnableToResetPassword           = "Unable to reset your password."
+	messageUnableToChangePassword          = "Unable to change your password."
+	messageIncorrectPassword               = "Your current password is incorrect."
 	messageMFAValidationFailed             = "Authentication failed, please retry later."
 	messagePasswordWeak                    = "Your supplied password does not meet the password policy requirements"
+	messagePasswordReused                  = "Your supplied password has been used recently and can not be reused"
+	messagePasswordBreached                = "Your supplied password has appeared in a data breach and can not be used"
+)
+
+const (
+	messageAccountDisabled     = "Authentication failed. Your account is disabled."
+	messageAccountLocked       = "Authentication failed. Your account is locked."
+	messageAccountExpired      = "Authentication failed. Your account has expired."
+	messagePasswordMustChange  = "Authentication failed. Your password must be changed."
+	pathResetPasswordStep1View = "/reset-password/step1"
 )
 
 const (
 	workflowOpenIDConnect = "openid_connect"
 )
 
+const (
+	prefixAuthzDecideRegulation = "authz_decide:"
+)
+
 const (
 	logFmtErrParseRequestBody     = "Failed to parse %s request body: %+v"
 	logFmtErrWriteResponseBody    = "Failed to write %s response body for user '%s': %+v"
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// AuthzDecidePOST handler which returns the policy decision of the access control rules for the subject and object
+// provided in the body. The client is authenticated using HTTP Basic Authentication with the credentials of one of the
+// configured policy decision clients.
+func AuthzDecidePOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.RequestHandler {
+	return func(ctx *middlewares.AutheliaCtx) {
+		var successful bool
+
+		requestTime := time.Now()
+
+		if delayFunc != nil {
+			defer delayFunc(ctx, requestTime, &successful)
+		}
+
+		client, err := authzDecideClient(ctx)
+		if err != nil {
+			ctx.Logger.Errorf("Unable to authenticate the policy decision client: %v", err)
+
+			ctx.ReplyUnauthorized()
+			ctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, `Basic realm="Authelia"`)
+
+			return
+		}
+
+		successful = true
+
+		var bodyJSON bodyAuthzDecideRequest
+
+		if err = ctx.ParseBody(&bodyJSON); err != nil {
+			ctx.Error(err, messageOperationFailed)
+
+			return
+		}
+
+		subject, object, err := authzDecideSubjectObject(ctx, client, bodyJSON)
+		if err != nil {
+			ctx.Error(fmt.Errorf("unable to determine the policy decision for client %s: %w", client.ID, err), messageOperationFailed)
+
+			return
+		}
+
+		ctx.Logger.Debugf("Policy decision requested by client %s for subject %s and object %s (method %s)", client.ID, subject, object, object.Method)
+
+		requirement := ctx.Providers.Authorizer.GetRequirement(subject, object)
+		results := ctx.Providers.Authorizer.GetRuleMatchResults(subject, object)
+
+		if err = ctx.SetJSONBody(newAuthzDecideResponse(requirement, results)); err != nil {
+			ctx.Error(fmt.Errorf("unable to create response body: %w", err), messageOperationFailed)
+
+			return
+		}
+	}
+}
+
+func authzDecideClient(ctx *middlewares.AutheliaCtx) (client *schema.ServerEndpointsAuthzDecideClient, err error) {
+	id, secret, err := parseBasicAuth(headerAuthorization, string(ctx.Request.Header.PeekBytes(headerAuthorization)))
+	if err != nil {
+		return nil, err
+	}
+
+	// The attempts are regulated under a prefixed name so failed client authentications can't ban a user with the
+	// same name as the client.
+	name := prefixAuthzDecideRegulation + id
+
+	if bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, name); err != nil {
+		if errors.Is(err, regulation.ErrUserIsBanned) {
+			_ = markAuthenticationAttempt(ctx, false, &bannedUntil, name, regulation.AuthTypeAuthzDecide, nil)
+
+			return nil, fmt.Errorf("the client %s is banned until %s", id, bannedUntil)
+		}
+
+		return nil, fmt.Errorf("unable to regulate client %s: %w", id, err)
+	}
+
+	clients := ctx.Configuration.Server.Endpoints.AuthzDecide.Clients
+
+	for i := range clients {
+		if clients[i].ID != id {
+			continue
+		}
+
+		var match bool
+
+		if match, err = clients[i].Secret.MatchAdvanced(secret); err != nil {
+			_ = markAuthenticationAttempt(ctx, false, nil, name, regulation.AuthTypeAuthzDecide, err)
+
+			return nil, fmt.Errorf("error occurred checking the secret of client %s: %w", id, err)
+		}
+
+		if !match {
+			_ = markAuthenticationAttempt(ctx, false, nil, name, regulation.AuthTypeAuthzDecide, nil)
+
+			return nil, fmt.Errorf("the secret of client %s is incorrect", id)
+		}
+
+		if err = markAuthenticationAttempt(ctx, true, nil, name, regulation.AuthTypeAuthzDecide, nil); err != nil {
+			return nil, fmt.Errorf("unable to mark the authentication attempt of client %s: %w", id, err)
+		}
+
+		return &clients[i], nil
+	}
+
+	// Match the secret against the digest of a configured client so unknown clients take the same time to reject as
+	// clients with an incorrect secret.
+	if len(clients) != 0 {
+		_, _ = clients[0].Secret.MatchAdvanced(secret)
+	}
+
+	_ = markAuthenticationAttempt(ctx, false, nil, name, regulation.AuthTypeAuthzDecide, nil)
+
+	return nil, fmt.Errorf("the client %s is not configured", id)
+}
+
+func authzDecideSubjectObject(ctx *middlewares.AutheliaCtx, client *schema.ServerEndpointsAuthzDecideClient, bodyJSON bodyAuthzDecideRequest) (subject authorization.Subject, object authorization.Object, err error) {
+	var targetURL *url.URL
+
+	if targetURL, err = url.ParseRequestURI(bodyJSON.Object.URL); err != nil {
+		return subject, object, fmt.Errorf("unable to parse the object url '%s': %w", bodyJSON.Object.URL, err)
+	}
+
+	method := bodyJSON.Object.Method
+	if method == "" {
+		method = fasthttp.MethodGet
+	}
+
+	object = authorization.NewObject(targetURL, method)
+	object.Time = bodyJSON.Object.Time
+
+	for name, values := range bodyJSON.Object.Headers {
+		if utils.IsStringInSliceFold(name, authorization.SensitiveHeaders) {
+			continue
+		}
+
+		if object.Header == nil {
+			object.Header = http.Header{}
+		}
+
+		for _, value := range values {
+			object.Header.Add(name, value)
+		}
+	}
+
+	subject = authorization.Subject{
+		Username:   bodyJSON.Subject.Username,
+		Groups:     bodyJSON.Subject.Groups,
+		Emails:     bodyJSON.Subject.Emails,
+		Attributes: bodyJSON.Subject.Attributes,
+		IP:         bodyJSON.Subject.IP,
+		AMR:        bodyJSON.Subject.AMR,
+	}
+
+	if subject.AuthenticationLevel, err = authentication.ParseLevel(bodyJSON.Subject.Level); err != nil {
+		return subject, object, fmt.Errorf("the subject level %w", err)
+	}
+
+	if bodyJSON.Subject.Resolve && subject.Username != "" {
+		if !client.ResolveUsers {
+			return subject, object, errors.New("the client is not permitted to resolve users")
+		}
+
+		var details *authentication.UserDetails
+
+		// The error is generic so the response and error log don't reveal if the user exists.
+		if details, err = ctx.Providers.UserProvider.GetDetails(subject.Username); err != nil {
+			ctx.Logger.Debugf("Unable to retrieve the details of user '%s' for client %s: %v", subject.Username, client.ID, err)
+
+			return subject, object, errors.New("unable to resolve the subject")
+		}
+
+		subject.Groups, subject.Emails, subject.Attributes = details.Groups, details.Emails, details.Attributes
+	}
+
+	return subject, object, nil
+}
+
+// newAuthzDecideResponse creates the response of the policy decision endpoint. The policy and rule are taken from the
+// requirement the authorizer applies to the request so they match the decision made at runtime.
+func newAuthzDecideResponse(requirement authorization.Requirement, results []authorization.RuleMatchResult) (response AuthzDecideResponse) {
+	response = AuthzDecideResponse{
+		Policy:  requirement.Level.String(),
+		Rule:    requirement.Position,
+		Results: make([]AuthzDecideRuleResult, len(results)),
+	}
+
+	for i, result := range results {
+		response.Results[i] = AuthzDecideRuleResult{
+			Rule:           i + 1,
+			Policy:         result.Rule.Policy.String(),
+			Skipped:        result.Skipped,
+			Match:          result.IsMatch(),
+			PotentialMatch: result.IsPotentialMatch(),
+
+			Domain:         result.MatchDomain,
+			Resources:      result.MatchResources,
+			Query:          result.MatchQuery,
+			Headers:        result.MatchHeaders,
+			Methods:        result.MatchMethods,
+			Networks:       result.MatchNetworks,
+			Schedule:       result.MatchSchedule,
+			Subjects:       result.MatchSubjects,
+			SubjectsExact:  result.MatchSubjectsExact,
+			Condition:      result.MatchCondition,
+			ConditionExact: result.MatchConditionExact,
+		}
+	}
+
+	return response
+}
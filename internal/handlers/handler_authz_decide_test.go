@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+func newAuthzDecideMockAutheliaCtx(t *testing.T, id, secret string) (mock *mocks.MockAutheliaCtx) {
+	mock = mocks.NewMockAutheliaCtx(t)
+
+	digest, err := schema.DecodePasswordDigest("$plaintext$secret")
+	require.NoError(t, err)
+
+	mock.Ctx.Configuration.Server.Endpoints.AuthzDecide.Clients = []schema.ServerEndpointsAuthzDecideClient{
+		{ID: "app", Secret: digest},
+	}
+
+	mock.Ctx.Request.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(id+":"+secret)))
+
+	mock.StorageMock.EXPECT().
+		AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	return mock
+}
+
+func TestAuthzDecidePOSTShouldReturnDecision(t *testing.T) {
+	testCases := []struct {
+		name      string
+		body      bodyAuthzDecideRequest
+		policy    string
+		rule      int
+		potential bool
+	}{
+		{
+			"ShouldApplyRule",
+			bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: "https://one-factor.example.com/"}},
+			"one_factor", 2, false,
+		},
+		{
+			"ShouldApplyDefaultPolicy",
+			bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: "https://unknown.example.com/"}},
+			"deny", 0, false,
+		},
+		{
+			"ShouldApplySubjectRule",
+			bodyAuthzDecideRequest{
+				Subject: bodyAuthzDecideSubject{Username: "john", Groups: []string{"admin"}, IP: net.ParseIP("10.0.0.1"), Level: "two_factor"},
+				Object:  bodyAuthzDecideObject{URL: "https://admin.example.com/", Method: "POST"},
+			},
+			"two_factor", 5, false,
+		},
+		{
+			"ShouldApplySubjectRuleToAnonymous",
+			bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: "https://admin.example.com/"}},
+			"two_factor", 5, true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newAuthzDecideMockAutheliaCtx(t, "app", "secret")
+			defer mock.Close()
+
+			mock.SetRequestBody(t, tc.body)
+
+			AuthzDecidePOST(nil)(mock.Ctx)
+
+			assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+
+			response := AuthzDecideResponse{}
+
+			mock.GetResponseData(t, &response)
+
+			assert.Equal(t, tc.policy, response.Policy)
+			assert.Equal(t, tc.rule, response.Rule)
+			require.Len(t, response.Results, 6)
+
+			if tc.rule != 0 {
+				result := response.Results[tc.rule-1]
+
+				assert.Equal(t, !tc.potential, result.Match)
+				assert.Equal(t, tc.potential, result.PotentialMatch)
+				assert.True(t, result.Domain)
+				assert.Equal(t, tc.policy, result.Policy)
+			}
+		})
+	}
+}
+
+func TestAuthzDecidePOSTShouldApplyQueryRules(t *testing.T) {
+	testCases := []struct {
+		name   string
+		url    string
+		policy string
+		rule   int
+	}{
+		{"ShouldApplyRuleWhenQueryMatches", "https://query.example.com/?mode=read", "bypass", 1},
+		{"ShouldNotApplyRuleWhenQueryDoesNotMatch", "https://query.example.com/?mode=write", "one_factor", 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newAuthzDecideMockAutheliaCtx(t, "app", "secret")
+			defer mock.Close()
+
+			mock.Ctx.Providers.Authorizer = authorization.NewAuthorizer(&schema.Configuration{
+				AccessControl: schema.AccessControlConfiguration{
+					DefaultPolicy: "deny",
+					Rules: []schema.ACLRule{
+						{
+							Domains: []string{"query.example.com"},
+							Policy:  "bypass",
+							Query:   [][]schema.ACLQueryRule{{{Operator: "equal", Key: "mode", Value: "read"}}},
+						},
+						{
+							Domains: []string{"query.example.com"},
+							Policy:  "one_factor",
+						},
+					},
+				},
+			})
+
+			mock.SetRequestBody(t, bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: tc.url}})
+
+			AuthzDecidePOST(nil)(mock.Ctx)
+
+			response := AuthzDecideResponse{}
+
+			mock.GetResponseData(t, &response)
+
+			assert.Equal(t, tc.policy, response.Policy)
+			assert.Equal(t, tc.rule, response.Rule)
+			require.Len(t, response.Results, 2)
+			assert.Equal(t, tc.rule == 1, response.Results[0].Query)
+			assert.Equal(t, tc.rule == 1, response.Results[0].Match)
+		})
+	}
+}
+
+func TestAuthzDecidePOSTShouldResolveUserDetails(t *testing.T) {
+	mock := newAuthzDecideMockAutheliaCtx(t, "app", "secret")
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Server.Endpoints.AuthzDecide.Clients[0].ResolveUsers = true
+
+	mock.UserProviderMock.EXPECT().
+		GetDetails(gomock.Eq("john")).
+		Return(&authentication.UserDetails{Username: "john", Groups: []string{"grafana"}}, nil)
+
+	mock.SetRequestBody(t, bodyAuthzDecideRequest{
+		Subject: bodyAuthzDecideSubject{Username: "john", Resolve: true},
+		Object:  bodyAuthzDecideObject{URL: "https://grafana.example.com/"},
+	})
+
+	AuthzDecidePOST(nil)(mock.Ctx)
+
+	response := AuthzDecideResponse{}
+
+	mock.GetResponseData(t, &response)
+
+	assert.Equal(t, "two_factor", response.Policy)
+	assert.Equal(t, 6, response.Rule)
+}
+
+func TestAuthzDecidePOSTShouldNotResolveUserDetailsWhenNotPermitted(t *testing.T) {
+	mock := newAuthzDecideMockAutheliaCtx(t, "app", "secret")
+	defer mock.Close()
+
+	mock.SetRequestBody(t, bodyAuthzDecideRequest{
+		Subject: bodyAuthzDecideSubject{Username: "john", Resolve: true},
+		Object:  bodyAuthzDecideObject{URL: "https://grafana.example.com/"},
+	})
+
+	AuthzDecidePOST(nil)(mock.Ctx)
+
+	mock.Assert200KO(t, messageOperationFailed)
+	assert.Equal(t, "unable to determine the policy decision for client app: the client is not permitted to resolve users", mock.Hook.LastEntry().Message)
+}
+
+func TestAuthzDecidePOSTShouldFailResolveUserDetails(t *testing.T) {
+	mock := newAuthzDecideMockAutheliaCtx(t, "app", "secret")
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Server.Endpoints.AuthzDecide.Clients[0].ResolveUsers = true
+
+	mock.UserProviderMock.EXPECT().
+		GetDetails(gomock.Eq("john")).
+		Return(nil, authentication.ErrUserNotFound)
+
+	mock.SetRequestBody(t, bodyAuthzDecideRequest{
+		Subject: bodyAuthzDecideSubject{Username: "john", Resolve: true},
+		Object:  bodyAuthzDecideObject{URL: "https://grafana.example.com/"},
+	})
+
+	AuthzDecidePOST(nil)(mock.Ctx)
+
+	mock.Assert200KO(t, messageOperationFailed)
+	assert.Equal(t, "unable to determine the policy decision for client app: unable to resolve the subject", mock.Hook.LastEntry().Message)
+}
+
+func TestAuthzDecidePOSTShouldFailInvalidBody(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     bodyAuthzDecideRequest
+		expected string
+	}{
+		{
+			"ShouldFailMissingURL",
+			bodyAuthzDecideRequest{},
+			"unable to validate body: Object.url: non zero value required",
+		},
+		{
+			"ShouldFailRelativeURL",
+			bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: "example.com"}},
+			"unable to determine the policy decision for client app: unable to parse the object url 'example.com': parse \"example.com\": invalid URI for request",
+		},
+		{
+			"ShouldFailInvalidLevel",
+			bodyAuthzDecideRequest{Subject: bodyAuthzDecideSubject{Level: "three_factor"}, Object: bodyAuthzDecideObject{URL: "https://example.com/"}},
+			"unable to determine the policy decision for client app: the subject level must be one of 'not_authenticated', 'one_factor' or 'two_factor' but it's configured as 'three_factor'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newAuthzDecideMockAutheliaCtx(t, "app", "secret")
+			defer mock.Close()
+
+			mock.SetRequestBody(t, tc.body)
+
+			AuthzDecidePOST(nil)(mock.Ctx)
+
+			mock.Assert200KO(t, messageOperationFailed)
+			assert.Equal(t, tc.expected, mock.Hook.LastEntry().Message)
+		})
+	}
+}
+
+func TestAuthzDecidePOSTShouldRejectUnauthenticatedClients(t *testing.T) {
+	testCases := []struct {
+		name, id, secret, expected string
+	}{
+		{"ShouldRejectIncorrectSecret", "app", "wrong", "Unable to authenticate the policy decision client: the secret of client app is incorrect"},
+		{"ShouldRejectUnknownClient", "other", "secret", "Unable to authenticate the policy decision client: the client other is not configured"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newAuthzDecideMockAutheliaCtx(t, tc.id, tc.secret)
+			defer mock.Close()
+
+			mock.SetRequestBody(t, bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: "https://one-factor.example.com/"}})
+
+			AuthzDecidePOST(nil)(mock.Ctx)
+
+			assert.Equal(t, 401, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, `Basic realm="Authelia"`, string(mock.Ctx.Response.Header.Peek("WWW-Authenticate")))
+			assert.Equal(t, tc.expected, mock.Hook.LastEntry().Message)
+		})
+	}
+
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	AuthzDecidePOST(nil)(mock.Ctx)
+
+	assert.Equal(t, 401, mock.Ctx.Response.StatusCode())
+}
+
+func TestAuthzDecidePOSTShouldDelayResponses(t *testing.T) {
+	testCases := []struct {
+		name, id, secret string
+		expected         bool
+	}{
+		{"ShouldDelaySuccessful", "app", "secret", true},
+		{"ShouldDelayIncorrectSecret", "app", "wrong", false},
+		{"ShouldDelayUnknownClient", "other", "secret", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newAuthzDecideMockAutheliaCtx(t, tc.id, tc.secret)
+			defer mock.Close()
+
+			mock.SetRequestBody(t, bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: "https://one-factor.example.com/"}})
+
+			var (
+				called     bool
+				successful bool
+			)
+
+			AuthzDecidePOST(func(_ *middlewares.AutheliaCtx, _ time.Time, s *bool) {
+				called, successful = true, *s
+			})(mock.Ctx)
+
+			assert.True(t, called)
+			assert.Equal(t, tc.expected, successful)
+		})
+	}
+}
+
+func TestAuthzDecidePOSTShouldRegulateClients(t *testing.T) {
+	testCases := []struct {
+		name, id, secret string
+		successful       bool
+		status           int
+	}{
+		{"ShouldMarkSuccessful", "app", "secret", true, 200},
+		{"ShouldMarkIncorrectSecret", "app", "wrong", false, 401},
+		{"ShouldMarkUnknownClient", "other", "secret", false, 401},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newAuthzDecideMockAutheliaCtx(t, tc.id, tc.secret)
+			defer mock.Close()
+
+			storage := mocks.NewMockStorage(mock.Ctrl)
+
+			mock.Ctx.Providers.Regulator = regulation.NewRegulator(schema.RegulationConfiguration{MaxRetries: 3, FindTime: time.Minute, BanTime: time.Hour}, storage, &mock.Clock)
+
+			gomock.InOrder(
+				storage.EXPECT().
+					LoadAuthenticationLogs(mock.Ctx, gomock.Eq("authz_decide:"+tc.id), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+					Return(nil, nil),
+				storage.EXPECT().
+					AppendAuthenticationLog(mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+						Username:   "authz_decide:" + tc.id,
+						Successful: tc.successful,
+						Banned:     false,
+						Time:       mock.Clock.Now(),
+						Type:       regulation.AuthTypeAuthzDecide,
+						RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+					})).
+					Return(nil),
+			)
+
+			mock.SetRequestBody(t, bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: "https://one-factor.example.com/"}})
+
+			AuthzDecidePOST(nil)(mock.Ctx)
+
+			assert.Equal(t, tc.status, mock.Ctx.Response.StatusCode())
+		})
+	}
+}
+
+func TestAuthzDecidePOSTShouldRejectBannedClients(t *testing.T) {
+	mock := newAuthzDecideMockAutheliaCtx(t, "app", "secret")
+	defer mock.Close()
+
+	storage := mocks.NewMockStorage(mock.Ctrl)
+
+	mock.Ctx.Providers.Regulator = regulation.NewRegulator(schema.RegulationConfiguration{MaxRetries: 3, FindTime: time.Minute, BanTime: time.Hour}, storage, &mock.Clock)
+
+	now := mock.Clock.Now()
+
+	gomock.InOrder(
+		storage.EXPECT().
+			LoadAuthenticationLogs(mock.Ctx, gomock.Eq("authz_decide:app"), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return([]model.AuthenticationAttempt{
+				{Username: "authz_decide:app", Successful: false, Time: now.Add(-time.Second)},
+				{Username: "authz_decide:app", Successful: false, Time: now.Add(-time.Second * 2)},
+				{Username: "authz_decide:app", Successful: false, Time: now.Add(-time.Second * 3)},
+			}, nil),
+		storage.EXPECT().
+			AppendAuthenticationLog(mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   "authz_decide:app",
+				Successful: false,
+				Banned:     true,
+				Time:       now,
+				Type:       regulation.AuthTypeAuthzDecide,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	mock.SetRequestBody(t, bodyAuthzDecideRequest{Object: bodyAuthzDecideObject{URL: "https://one-factor.example.com/"}})
+
+	AuthzDecidePOST(nil)(mock.Ctx)
+
+	assert.Equal(t, 401, mock.Ctx.Response.StatusCode())
+	assert.Regexp(t, "^Unable to authenticate the policy decision client: the client app is banned until ", mock.Hook.LastEntry().Message)
+}
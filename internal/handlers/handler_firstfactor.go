@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"errors"
+	"path"
 	"time"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/regulation"
@@ -53,7 +55,7 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		if err != nil {
 			_ = markAuthenticationAttempt(ctx, false, nil, bodyJSON.Username, regulation.AuthType1FA, err)
 
-			respondUnauthorized(ctx, messageAuthenticationFailed)
+			respondFirstFactorError(ctx, err)
 
 			return
 		}
@@ -84,7 +86,7 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 			return
 		}
 
-		if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx); err != nil {
+		if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx, ctx.GetSessionHost()); err != nil {
 			ctx.Logger.Errorf(logFmtErrSessionRegenerate, regulation.AuthType1FA, bodyJSON.Username, err)
 
 			respondUnauthorized(ctx, messageAuthenticationFailed)
@@ -93,11 +95,12 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		}
 
 		// Check if bodyJSON.KeepMeLoggedIn can be deref'd and derive the value based on the configuration and JSON data.
-		keepMeLoggedIn := ctx.Providers.SessionProvider.RememberMe != schema.RememberMeDisabled && bodyJSON.KeepMeLoggedIn != nil && *bodyJSON.KeepMeLoggedIn
+		rememberMe := ctx.Providers.SessionProvider.GetCookieConfiguration(ctx.GetSessionHost()).RememberMeDuration
+		keepMeLoggedIn := rememberMe != schema.RememberMeDisabled && bodyJSON.KeepMeLoggedIn != nil && *bodyJSON.KeepMeLoggedIn
 
 		// Set the cookie to expire if remember me is enabled and the user has asked us to.
 		if keepMeLoggedIn {
-			err = ctx.Providers.SessionProvider.UpdateExpiration(ctx.RequestCtx, ctx.Providers.SessionProvider.RememberMe)
+			err = ctx.Providers.SessionProvider.UpdateExpiration(ctx.RequestCtx, ctx.GetSessionHost(), rememberMe)
 			if err != nil {
 				ctx.Logger.Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthType1FA, bodyJSON.Username, err)
 
@@ -138,7 +141,49 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		if bodyJSON.Workflow == workflowOpenIDConnect {
 			handleOIDCWorkflowResponse(ctx, bodyJSON.TargetURL, bodyJSON.WorkflowID)
 		} else {
-			Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups)
+			Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups, userSession.Emails, userSession.Attributes)
+		}
+	}
+}
+
+// respondFirstFactorError responds to a first factor attempt which failed with an error. The user providers only
+// return errors representing the account state when doing so does not disclose whether the password was correct, so
+// these are safe to report to the user. Users who must change their password are redirected to the reset password
+// flow when it's enabled.
+func respondFirstFactorError(ctx *middlewares.AutheliaCtx, err error) {
+	switch {
+	case errors.Is(err, authentication.ErrPasswordMustChange):
+		config := ctx.Configuration.AuthenticationBackend.PasswordReset
+
+		if config.Disable {
+			respondUnauthorized(ctx, messagePasswordMustChange)
+
+			return
+		}
+
+		var redirectURL string
+
+		if config.CustomURL.String() != "" {
+			redirectURL = config.CustomURL.String()
+		} else {
+			rootURL := ctx.RootURL()
+			rootURL.Path = path.Join(rootURL.Path, pathResetPasswordStep1View)
+
+			redirectURL = rootURL.String()
+		}
+
+		ctx.Logger.Debugf("Redirecting user to '%s' as their password must be changed", redirectURL)
+
+		if err = ctx.SetJSONBody(redirectResponse{Redirect: redirectURL}); err != nil {
+			ctx.Logger.Errorf("Unable to set password change redirection URL in body: %s", err)
 		}
+	case errors.Is(err, authentication.ErrAccountDisabled):
+		respondUnauthorized(ctx, messageAccountDisabled)
+	case errors.Is(err, authentication.ErrAccountExpired):
+		respondUnauthorized(ctx, messageAccountExpired)
+	case errors.Is(err, authentication.ErrAccountLocked):
+		respondUnauthorized(ctx, messageAccountLocked)
+	default:
+		respondUnauthorized(ctx, messageAuthenticationFailed)
 	}
 }
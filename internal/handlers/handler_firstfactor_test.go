@@ -178,6 +178,102 @@ func (s *FirstFactorSuite) TestShouldFailIfAuthenticationMarkFail() {
 	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
 }
 
+func (s *FirstFactorSuite) TestShouldFailWithAccountDisabledMessage() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, fmt.Errorf("authentication failed. Cause: %w", authentication.ErrAccountDisabled))
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	assert.Equal(s.T(), "Unsuccessful 1FA authentication attempt by user 'test': authentication failed. Cause: account is disabled", s.mock.Hook.LastEntry().Message)
+	s.mock.Assert401KO(s.T(), "Authentication failed. Your account is disabled.")
+}
+
+func (s *FirstFactorSuite) TestShouldFailWithAccountLockedMessage() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, fmt.Errorf("authentication failed. Cause: %w", authentication.ErrAccountLocked))
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed. Your account is locked.")
+}
+
+func (s *FirstFactorSuite) TestShouldRedirectToResetPasswordWhenPasswordMustChange() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, fmt.Errorf("authentication failed. Cause: %w", authentication.ErrPasswordMustChange))
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "test",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthType1FA,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), redirectResponse{Redirect: "https://auth.example.com/reset-password/step1"})
+	assert.Equal(s.T(), "", s.mock.Ctx.GetSession().Username)
+}
+
+func (s *FirstFactorSuite) TestShouldFailWithPasswordMustChangeMessageWhenResetDisabled() {
+	s.mock.Ctx.Configuration.AuthenticationBackend.PasswordReset.Disable = true
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, fmt.Errorf("authentication failed. Cause: %w", authentication.ErrPasswordMustChange))
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed. Your password must be changed.")
+}
+
 func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeChecked() {
 	s.mock.UserProviderMock.
 		EXPECT().
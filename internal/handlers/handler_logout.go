@@ -5,11 +5,11 @@ import (
 	"net/url"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
-	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 type logoutBody struct {
-	TargetURL string `json:"targetURL"`
+	TargetURL  string `json:"targetURL"`
+	Everywhere bool   `json:"everywhere"`
 }
 
 type logoutResponseBody struct {
@@ -26,14 +26,18 @@ func LogoutPOST(ctx *middlewares.AutheliaCtx) {
 		ctx.Error(fmt.Errorf("unable to parse body during logout: %s", err), messageOperationFailed)
 	}
 
-	err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx)
+	if body.Everywhere {
+		logoutEverywhere(ctx)
+	}
+
+	err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx, ctx.GetSessionHost())
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to destroy session during logout: %s", err), messageOperationFailed)
 	}
 
 	redirectionURL, err := url.ParseRequestURI(body.TargetURL)
 	if err == nil {
-		responseBody.SafeTargetURL = utils.IsURISafeRedirection(redirectionURL, ctx.Configuration.Session.Domain)
+		responseBody.SafeTargetURL = isURISafeRedirection(ctx, redirectionURL)
 	}
 
 	if body.TargetURL != "" {
@@ -45,3 +49,26 @@ func LogoutPOST(ctx *middlewares.AutheliaCtx) {
 		ctx.Error(fmt.Errorf("unable to set body during logout: %s", err), messageOperationFailed)
 	}
 }
+
+// logoutEverywhere revokes all of the sessions of the user attached to the given cookie including the sessions which
+// are not in the index of the sessions of the user.
+func logoutEverywhere(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	if userSession.IsAnonymous() {
+		return
+	}
+
+	if err := ctx.Providers.StorageProvider.SaveUserSessionRevocation(ctx, userSession.Username, ctx.Clock.Now()); err != nil {
+		ctx.Logger.Errorf("Unable to revoke the sessions of user %s during logout: %v", userSession.Username, err)
+	}
+
+	revoked, err := ctx.Providers.SessionProvider.RevokeUserSessions(userSession.Username, "")
+	if err != nil {
+		ctx.Logger.Errorf("Unable to destroy the sessions of user %s during logout: %v", userSession.Username, err)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Destroyed %d sessions of user %s during logout", revoked, userSession.Username)
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/mocks"
 )
 
@@ -38,6 +40,41 @@ func (s *LogoutSuite) TestShouldDestroySession() {
 	assert.True(s.T(), strings.HasPrefix(string(b), "authelia_session=;"))
 }
 
+func (s *LogoutSuite) TestShouldDestroySessionsEverywhere() {
+	s.mock.Ctx.Clock = &s.mock.Clock
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.AuthenticationLevel = authentication.OneFactor
+
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	other := &fasthttp.RequestCtx{}
+
+	otherSession, err := s.mock.Ctx.Providers.SessionProvider.GetSession(other, s.mock.Ctx.GetSessionHost())
+	s.Require().NoError(err)
+
+	otherSession.Username = testUsername
+	otherSession.AuthenticationLevel = authentication.OneFactor
+
+	s.Require().NoError(s.mock.Ctx.Providers.SessionProvider.SaveSession(other, s.mock.Ctx.GetSessionHost(), otherSession))
+
+	s.mock.StorageMock.EXPECT().
+		SaveUserSessionRevocation(s.mock.Ctx, testUsername, s.mock.Clock.Now()).
+		Return(nil)
+
+	s.mock.SetRequestBody(s.T(), logoutBody{Everywhere: true})
+
+	LogoutPOST(s.mock.Ctx)
+
+	otherSession, err = s.mock.Ctx.Providers.SessionProvider.GetSession(other, s.mock.Ctx.GetSessionHost())
+	s.Require().NoError(err)
+	s.True(otherSession.IsAnonymous())
+
+	sessions, err := s.mock.Ctx.Providers.SessionProvider.ListUserSessions(testUsername)
+	s.Require().NoError(err)
+	s.Len(sessions, 0)
+}
+
 func TestRunLogoutSuite(t *testing.T) {
 	s := new(LogoutSuite)
 	suite.Run(t, s)
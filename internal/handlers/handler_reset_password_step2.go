@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
@@ -31,8 +32,11 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if err = ctx.Providers.PasswordPolicy.Check(requestBody.Password); err != nil {
-		ctx.Error(err, messagePasswordWeak)
+	if !checkPasswordPolicy(ctx, requestBody.Password) {
+		return
+	}
+
+	if !checkPasswordHistory(ctx, username, requestBody.Password, messageUnableToResetPassword) {
 		return
 	}
 
@@ -50,6 +54,8 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 
 	ctx.Logger.Debugf("Password of user %s has been reset", username)
 
+	savePasswordHistory(ctx, username, requestBody.Password)
+
 	// Reset the request.
 	userSession.PasswordResetUsername = nil
 
@@ -58,18 +64,62 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	// Send Notification.
+	sendPasswordChangedNotification(ctx, username)
+
+	ctx.ReplyOK()
+}
+
+// checkPasswordPolicy checks the password against the password policy replying with an error if it does not meet it.
+func checkPasswordPolicy(ctx *middlewares.AutheliaCtx, password string) (ok bool) {
+	if err := ctx.Providers.PasswordPolicy.Check(password); err != nil {
+		if errors.Is(err, middlewares.ErrPasswordPolicyBreached) {
+			ctx.Error(err, messagePasswordBreached)
+		} else {
+			ctx.Error(err, messagePasswordWeak)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// checkPasswordHistory checks the password against the password history of the user replying with an error if it has
+// been used recently.
+func checkPasswordHistory(ctx *middlewares.AutheliaCtx, username, password, message string) (ok bool) {
+	if err := ctx.Providers.PasswordHistory.CheckHistory(ctx, username, password); err != nil {
+		if errors.Is(err, middlewares.ErrPasswordPolicyHistoryReused) {
+			ctx.Error(err, messagePasswordReused)
+		} else {
+			ctx.Error(fmt.Errorf("unable to check the password history of user %s: %w", username, err), message)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// savePasswordHistory records the password in the password history of the user. Errors are logged only as the password
+// has already been changed at this point.
+func savePasswordHistory(ctx *middlewares.AutheliaCtx, username, password string) {
+	if err := ctx.Providers.PasswordHistory.SaveHistory(ctx, username, password); err != nil {
+		ctx.Logger.Errorf("Unable to save the password history of user %s: %v", username, err)
+	}
+}
+
+// sendPasswordChangedNotification informs the user that their password has been changed. Errors are logged only as the
+// password has already been changed at this point.
+func sendPasswordChangedNotification(ctx *middlewares.AutheliaCtx, username string) {
 	userInfo, err := ctx.Providers.UserProvider.GetDetails(username)
 	if err != nil {
 		ctx.Logger.Error(err)
-		ctx.ReplyOK()
 
 		return
 	}
 
 	if len(userInfo.Emails) == 0 {
 		ctx.Logger.Error(fmt.Errorf("user %s has no email address configured", username))
-		ctx.ReplyOK()
 
 		return
 	}
@@ -90,7 +140,6 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 	if !disableHTML {
 		if err = ctx.Providers.Templates.ExecuteEmailPasswordResetTemplate(bufHTML, values, templates.HTMLFormat); err != nil {
 			ctx.Logger.Error(err)
-			ctx.ReplyOK()
 
 			return
 		}
@@ -98,7 +147,6 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 
 	if err = ctx.Providers.Templates.ExecuteEmailPasswordResetTemplate(bufText, values, templates.PlainTextFormat); err != nil {
 		ctx.Logger.Error(err)
-		ctx.ReplyOK()
 
 		return
 	}
@@ -110,8 +158,5 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 
 	if err = ctx.Providers.Notifier.Send(addresses[0], "Password changed successfully", bufText.Bytes(), bufHTML.Bytes()); err != nil {
 		ctx.Logger.Error(err)
-		ctx.ReplyOK()
-
-		return
 	}
 }
@@ -86,6 +86,37 @@ func (s *StateGetSuite) TestShouldReturnAuthenticationLevelFromSession() {
 	assert.Equal(s.T(), expectedBody, actualBody)
 }
 
+func (s *StateGetSuite) TestShouldReturnSecondFactorMethodsFromSession() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.SetOneFactor(s.mock.Clock.Now(), &authentication.UserDetails{Username: "john"}, false)
+	userSession.SetTwoFactorWebauthn(s.mock.Clock.Now(), true, false)
+	err := s.mock.Ctx.SaveSession(userSession)
+	require.NoError(s.T(), err)
+
+	StateGET(s.mock.Ctx)
+
+	type Response struct {
+		Status string
+		Data   StateResponse
+	}
+
+	expectedBody := Response{
+		Status: "OK",
+		Data: StateResponse{
+			Username:              "john",
+			DefaultRedirectionURL: "",
+			AuthenticationLevel:   authentication.TwoFactor,
+			SecondFactorMethods:   []string{"webauthn"},
+		},
+	}
+	actualBody := Response{}
+
+	err = json.Unmarshal(s.mock.Ctx.Response.Body(), &actualBody)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
+	assert.Equal(s.T(), expectedBody, actualBody)
+}
+
 func TestRunStateGetSuite(t *testing.T) {
 	s := new(StateGetSuite)
 	suite.Run(t, s)
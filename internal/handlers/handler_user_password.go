@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// UserPasswordPOST handler for changing the password of the currently logged in user.
+func UserPasswordPOST(ctx *middlewares.AutheliaCtx) {
+	config := ctx.Configuration.AuthenticationBackend.PasswordChange
+
+	if config.Disable {
+		ctx.ReplyForbidden()
+
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	if config.RequireSecondFactor {
+		authenticatedTime, _ := userSession.AuthenticatedTime(authorization.TwoFactor)
+
+		if userSession.AuthenticationLevel < authentication.TwoFactor || ctx.Clock.Now().Sub(authenticatedTime) > config.SecondFactorMaxAge {
+			ctx.Logger.Debugf("User %s attempted to change their password without a recent second factor authentication", userSession.Username)
+
+			ctx.ReplyForbidden()
+
+			return
+		}
+	}
+
+	var (
+		bodyJSON bodyChangePasswordRequest
+		err      error
+	)
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageUnableToChangePassword)
+
+		return
+	}
+
+	if !userPasswordCheckCurrent(ctx, userSession.Username, bodyJSON.CurrentPassword) {
+		return
+	}
+
+	if !checkPasswordPolicy(ctx, bodyJSON.NewPassword) {
+		return
+	}
+
+	// The current password is not necessarily part of the password history if the history was enabled recently.
+	if ctx.Configuration.PasswordPolicy.History.Enabled && bodyJSON.NewPassword == bodyJSON.CurrentPassword {
+		ctx.Error(middlewares.ErrPasswordPolicyHistoryReused, messagePasswordReused)
+
+		return
+	}
+
+	if !checkPasswordHistory(ctx, userSession.Username, bodyJSON.NewPassword, messageUnableToChangePassword) {
+		return
+	}
+
+	if err = ctx.Providers.UserProvider.UpdatePassword(userSession.Username, bodyJSON.NewPassword); err != nil {
+		switch {
+		case utils.IsStringInSliceContains(err.Error(), ldapPasswordComplexityCodes),
+			utils.IsStringInSliceContains(err.Error(), ldapPasswordComplexityErrors):
+			ctx.Error(err, ldapPasswordComplexityCode)
+		default:
+			ctx.Error(err, messageUnableToChangePassword)
+		}
+
+		return
+	}
+
+	ctx.Logger.Debugf("Password of user %s has been changed", userSession.Username)
+
+	savePasswordHistory(ctx, userSession.Username, bodyJSON.NewPassword)
+
+	now := ctx.Clock.Now()
+
+	// Revoke all other sessions of the user, the current session is kept by considering it authenticated at the
+	// time of the revocation given the user has just proven knowledge of their password.
+	if err = ctx.Providers.StorageProvider.SaveUserSessionRevocation(ctx, userSession.Username, now); err != nil {
+		ctx.Error(fmt.Errorf("unable to revoke the other sessions after a password change: %w", err), messageOperationFailed)
+
+		return
+	}
+
+	userSession.FirstFactorAuthnTimestamp = now.Unix()
+	userSession.FirstFactorAuthnTime = now
+	userSession.RevocationCheckTTL = now.Add(session.RevocationCheckInterval)
+
+	if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx, ctx.GetSessionHost()); err != nil {
+		ctx.Error(fmt.Errorf("unable to regenerate session after a password change: %w", err), messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Error(fmt.Errorf("unable to update session after a password change: %w", err), messageOperationFailed)
+
+		return
+	}
+
+	sendPasswordChangedNotification(ctx, userSession.Username)
+
+	ctx.ReplyOK()
+}
+
+func userPasswordCheckCurrent(ctx *middlewares.AutheliaCtx, username, password string) (ok bool) {
+	if bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, username); err != nil {
+		if errors.Is(err, regulation.ErrUserIsBanned) {
+			_ = markAuthenticationAttempt(ctx, false, &bannedUntil, username, regulation.AuthType1FA, nil)
+
+			ctx.Error(err, messageUnableToChangePassword)
+
+			return false
+		}
+
+		ctx.Logger.Errorf(logFmtErrRegulationFail, regulation.AuthType1FA, username, err)
+
+		ctx.Error(err, messageUnableToChangePassword)
+
+		return false
+	}
+
+	valid, err := ctx.Providers.UserProvider.CheckUserPassword(username, password)
+	if err != nil || !valid {
+		_ = markAuthenticationAttempt(ctx, false, nil, username, regulation.AuthType1FA, err)
+
+		if err == nil {
+			err = fmt.Errorf("the current password provided by user %s is incorrect", username)
+		}
+
+		ctx.Error(err, messageIncorrectPassword)
+
+		return false
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, username, regulation.AuthType1FA, nil); err != nil {
+		ctx.Error(err, messageUnableToChangePassword)
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+type UserPasswordSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *UserPasswordSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Clock.Set(time.Unix(1000000, 0))
+	s.mock.Ctx.Clock = &s.mock.Clock
+	s.mock.Ctx.Configuration.AuthenticationBackend.PasswordChange = schema.DefaultPasswordChangeAuthenticationBackend
+	s.mock.Ctx.Providers.PasswordPolicy = middlewares.NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{
+		Standard: schema.PasswordPolicyStandardParams{
+			Enabled:   true,
+			MinLength: 8,
+		},
+	}, nil)
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.OneFactor
+	userSession.FirstFactorAuthnTimestamp = s.mock.Clock.Now().Add(-time.Hour).Unix()
+
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *UserPasswordSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *UserPasswordSuite) TestShouldFailWhenDisabled() {
+	s.mock.Ctx.Configuration.AuthenticationBackend.PasswordChange.Disable = true
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.Equal(403, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *UserPasswordSuite) TestShouldFailWithoutRecentSecondFactor() {
+	s.mock.Ctx.Configuration.AuthenticationBackend.PasswordChange.RequireSecondFactor = true
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.Equal(403, s.mock.Ctx.Response.StatusCode())
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.AuthenticationLevel = authentication.TwoFactor
+	userSession.SecondFactorAuthnTimestamp = s.mock.Clock.Now().Add(-time.Hour).Unix()
+
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Ctx.Response.Reset()
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.Equal(403, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *UserPasswordSuite) TestShouldFailWithIncorrectCurrentPassword() {
+	s.mock.UserProviderMock.EXPECT().CheckUserPassword(testUsername, "wrong").Return(false, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   testUsername,
+			Successful: false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthType1FA,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	s.mock.Ctx.Request.SetBodyString(`{"current_password":"wrong","new_password":"new-password"}`)
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageIncorrectPassword)
+}
+
+func (s *UserPasswordSuite) TestShouldFailWithWeakPassword() {
+	s.mock.UserProviderMock.EXPECT().CheckUserPassword(testUsername, "password").Return(true, nil)
+	s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"current_password":"password","new_password":"weak"}`)
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messagePasswordWeak)
+}
+
+func (s *UserPasswordSuite) TestShouldFailWithBreachedPassword() {
+	s.mock.Ctx.Providers.PasswordPolicy = testPasswordPolicyProvider{err: middlewares.ErrPasswordPolicyBreached}
+
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().CheckUserPassword(testUsername, "password").Return(true, nil),
+		s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"current_password":"password","new_password":"new-password"}`)
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messagePasswordBreached)
+}
+
+func (s *UserPasswordSuite) TestShouldFailWithReusedPassword() {
+	hash, err := authentication.NewFileCryptoHashFromConfig(schema.DefaultCIPasswordConfig)
+	s.Require().NoError(err)
+
+	digest, err := hash.Hash("new-password")
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Configuration.PasswordPolicy.History = schema.PasswordPolicyHistoryParams{Enabled: true, Count: 3}
+	s.mock.Ctx.Providers.PasswordHistory, err = middlewares.NewPasswordHistoryProvider(s.mock.Ctx.Configuration.PasswordPolicy.History, schema.DefaultCIPasswordConfig, s.mock.StorageMock)
+	s.Require().NoError(err)
+
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().CheckUserPassword(testUsername, "password").Return(true, nil),
+		s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).Return(nil),
+		s.mock.StorageMock.EXPECT().LoadUserPasswordHistory(s.mock.Ctx, testUsername, 3).Return([]string{digest.Encode()}, nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"current_password":"password","new_password":"new-password"}`)
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messagePasswordReused)
+}
+
+func (s *UserPasswordSuite) TestShouldFailWithCurrentPasswordWhenHistoryEnabled() {
+	s.mock.Ctx.Configuration.PasswordPolicy.History = schema.PasswordPolicyHistoryParams{Enabled: true, Count: 3}
+
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().CheckUserPassword(testUsername, "password").Return(true, nil),
+		s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"current_password":"password","new_password":"password"}`)
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messagePasswordReused)
+}
+
+func (s *UserPasswordSuite) TestShouldFailWhenUpdateFails() {
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().CheckUserPassword(testUsername, "password").Return(true, nil),
+		s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).Return(nil),
+		s.mock.UserProviderMock.EXPECT().UpdatePassword(testUsername, "new-password").Return(errors.New("failed")),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"current_password":"password","new_password":"new-password"}`)
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToChangePassword)
+}
+
+func (s *UserPasswordSuite) TestShouldFailWhenRevocationFails() {
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().CheckUserPassword(testUsername, "password").Return(true, nil),
+		s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).Return(nil),
+		s.mock.UserProviderMock.EXPECT().UpdatePassword(testUsername, "new-password").Return(nil),
+		s.mock.StorageMock.EXPECT().SaveUserSessionRevocation(s.mock.Ctx, testUsername, s.mock.Clock.Now()).Return(errors.New("failed")),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"current_password":"password","new_password":"new-password"}`)
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Equal("unable to revoke the other sessions after a password change: failed", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *UserPasswordSuite) TestShouldChangePasswordAndRevokeOtherSessions() {
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().CheckUserPassword(testUsername, "password").Return(true, nil),
+		s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).Return(nil),
+		s.mock.UserProviderMock.EXPECT().UpdatePassword(testUsername, "new-password").Return(nil),
+		s.mock.StorageMock.EXPECT().SaveUserSessionRevocation(s.mock.Ctx, testUsername, s.mock.Clock.Now()).Return(nil),
+		s.mock.UserProviderMock.EXPECT().GetDetails(testUsername).Return(&authentication.UserDetails{
+			Username:    testUsername,
+			DisplayName: "John Smith",
+			Emails:      []string{"john@example.com"},
+		}, nil),
+		s.mock.NotifierMock.EXPECT().Send(gomock.Any(), "Password changed successfully", gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"current_password":"password","new_password":"new-password"}`)
+
+	UserPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	userSession := s.mock.Ctx.GetSession()
+
+	assert.Equal(s.T(), testUsername, userSession.Username)
+	assert.Equal(s.T(), s.mock.Clock.Now().Unix(), userSession.FirstFactorAuthnTimestamp)
+	assert.True(s.T(), s.mock.Clock.Now().Equal(userSession.FirstFactorAuthnTime))
+
+	assert.True(s.T(), s.mock.Clock.Now().Add(session.RevocationCheckInterval).Equal(userSession.RevocationCheckTTL))
+
+	// The storage must not be checked for revocations until the next interval.
+	revoked, err := s.mock.Ctx.IsSessionRevoked(&userSession)
+
+	s.NoError(err)
+	s.False(revoked)
+
+	revokedAt := s.mock.Clock.Now()
+
+	s.mock.Clock.Set(revokedAt.Add(session.RevocationCheckInterval))
+
+	// The current session must not be considered revoked by the revocation it just created.
+	s.mock.StorageMock.EXPECT().LoadUserSessionRevocation(s.mock.Ctx, testUsername).Return(revokedAt, nil)
+
+	revoked, err = s.mock.Ctx.IsSessionRevoked(&userSession)
+
+	s.NoError(err)
+	s.False(revoked)
+}
+
+type testPasswordPolicyProvider struct {
+	err error
+}
+
+func (p testPasswordPolicyProvider) Check(_ string) (err error) {
+	return p.err
+}
+
+func TestRunUserPasswordSuite(t *testing.T) {
+	suite.Run(t, new(UserPasswordSuite))
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// UserSessionsGET returns the active sessions of the user identified by the session.
+func UserSessionsGET(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	sessions, err := ctx.Providers.SessionProvider.ListUserSessions(userSession.Username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to list the sessions of user '%s': %w", userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	current, err := ctx.Providers.SessionProvider.GetUserSessionID(ctx.RequestCtx, ctx.GetSessionHost())
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to determine the current session of user '%s': %w", userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	response := make([]UserSessionResponse, len(sessions))
+
+	for i, s := range sessions {
+		response[i] = UserSessionResponse{
+			ID:                  s.ID,
+			Domain:              s.Domain,
+			IP:                  s.IP,
+			UserAgent:           s.UserAgent,
+			CreatedAt:           s.CreatedAt,
+			LastActivityAt:      s.LastActivityAt,
+			AuthenticationLevel: s.AuthenticationLevel.String(),
+			Current:             s.ID == current,
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.Errorf("Unable to set user sessions response in body: %s", err)
+	}
+}
+
+// UserSessionsDELETE revokes the session of the user identified by the session with the id provided in the body, or
+// all of the other sessions of the user if the body is empty.
+func UserSessionsDELETE(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	bodyJSON := bodyRevokeUserSessionRequest{}
+
+	if len(ctx.PostBody()) != 0 {
+		if err := ctx.ParseBody(&bodyJSON); err != nil {
+			ctx.Error(err, messageOperationFailed)
+			return
+		}
+	}
+
+	current, err := ctx.Providers.SessionProvider.GetUserSessionID(ctx.RequestCtx, ctx.GetSessionHost())
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to determine the current session of user '%s': %w", userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	switch bodyJSON.ID {
+	case "":
+		var revoked int
+
+		if revoked, err = ctx.Providers.SessionProvider.RevokeUserSessions(userSession.Username, current); err != nil {
+			ctx.Error(fmt.Errorf("unable to revoke the other sessions of user '%s': %w", userSession.Username, err), messageOperationFailed)
+			return
+		}
+
+		ctx.Logger.Debugf("Revoked %d other sessions of user %s", revoked, userSession.Username)
+	case current:
+		ctx.Error(fmt.Errorf("user '%s' attempted to revoke the current session which must be done by logging out", userSession.Username), messageOperationFailed)
+		return
+	default:
+		switch err = ctx.Providers.SessionProvider.RevokeUserSession(userSession.Username, bodyJSON.ID); {
+		case errors.Is(err, session.ErrUserSessionNotFound):
+			ctx.Error(fmt.Errorf("user '%s' attempted to revoke the session '%s' which does not exist", userSession.Username, bodyJSON.ID), messageOperationFailed)
+			return
+		case err != nil:
+			ctx.Error(fmt.Errorf("unable to revoke the session '%s' of user '%s': %w", bodyJSON.ID, userSession.Username, err), messageOperationFailed)
+			return
+		}
+
+		ctx.Logger.Debugf("Revoked session %s of user %s", bodyJSON.ID, userSession.Username)
+	}
+
+	ctx.ReplyOK()
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func newUserSessionsMockAutheliaCtx(t *testing.T) (mock *mocks.MockAutheliaCtx, other *fasthttp.RequestCtx) {
+	mock = mocks.NewMockAutheliaCtx(t)
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.TwoFactor
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	other = &fasthttp.RequestCtx{}
+	other.Request.Header.SetUserAgent("Mozilla/5.0")
+
+	otherSession, err := mock.Ctx.Providers.SessionProvider.GetSession(other, mock.Ctx.GetSessionHost())
+	require.NoError(t, err)
+
+	otherSession.Username = testUsername
+	otherSession.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, mock.Ctx.Providers.SessionProvider.SaveSession(other, mock.Ctx.GetSessionHost(), otherSession))
+
+	return mock, other
+}
+
+func TestUserSessionsGETShouldListSessions(t *testing.T) {
+	mock, _ := newUserSessionsMockAutheliaCtx(t)
+	defer mock.Close()
+
+	UserSessionsGET(mock.Ctx)
+
+	var response []UserSessionResponse
+
+	mock.GetResponseData(t, &response)
+
+	require.Len(t, response, 2)
+
+	levels := map[bool]string{}
+
+	for _, s := range response {
+		levels[s.Current] = s.AuthenticationLevel
+	}
+
+	assert.Equal(t, map[bool]string{true: "two_factor", false: "one_factor"}, levels)
+}
+
+func TestUserSessionsDELETEShouldRevokeOtherSessions(t *testing.T) {
+	mock, other := newUserSessionsMockAutheliaCtx(t)
+	defer mock.Close()
+
+	UserSessionsDELETE(mock.Ctx)
+
+	mock.Assert200OK(t, nil)
+
+	otherSession, err := mock.Ctx.Providers.SessionProvider.GetSession(other, mock.Ctx.GetSessionHost())
+	require.NoError(t, err)
+	assert.True(t, otherSession.IsAnonymous())
+
+	sessions, err := mock.Ctx.Providers.SessionProvider.ListUserSessions(testUsername)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	current, err := mock.Ctx.Providers.SessionProvider.GetUserSessionID(mock.Ctx.RequestCtx, mock.Ctx.GetSessionHost())
+	require.NoError(t, err)
+	assert.Equal(t, current, sessions[0].ID)
+}
+
+func TestUserSessionsDELETEShouldRevokeSession(t *testing.T) {
+	mock, other := newUserSessionsMockAutheliaCtx(t)
+	defer mock.Close()
+
+	id, err := mock.Ctx.Providers.SessionProvider.GetUserSessionID(other, mock.Ctx.GetSessionHost())
+	require.NoError(t, err)
+
+	mock.SetRequestBody(t, bodyRevokeUserSessionRequest{ID: id})
+
+	UserSessionsDELETE(mock.Ctx)
+
+	mock.Assert200OK(t, nil)
+
+	otherSession, err := mock.Ctx.Providers.SessionProvider.GetSession(other, mock.Ctx.GetSessionHost())
+	require.NoError(t, err)
+	assert.True(t, otherSession.IsAnonymous())
+}
+
+func TestUserSessionsDELETEShouldFailRevokeSession(t *testing.T) {
+	testCases := []struct {
+		name     string
+		current  bool
+		expected string
+	}{
+		{"ShouldFailUnknownSession", false, "user 'john' attempted to revoke the session 'abc' which does not exist"},
+		{"ShouldFailCurrentSession", true, "user 'john' attempted to revoke the current session which must be done by logging out"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, _ := newUserSessionsMockAutheliaCtx(t)
+			defer mock.Close()
+
+			id := "abc"
+
+			if tc.current {
+				var err error
+
+				id, err = mock.Ctx.Providers.SessionProvider.GetUserSessionID(mock.Ctx.RequestCtx, mock.Ctx.GetSessionHost())
+				require.NoError(t, err)
+			}
+
+			mock.SetRequestBody(t, bodyRevokeUserSessionRequest{ID: id})
+
+			UserSessionsDELETE(mock.Ctx)
+
+			mock.Assert200KO(t, messageOperationFailed)
+			assert.Equal(t, tc.expected, mock.Hook.LastEntry().Message)
+
+			sessions, err := mock.Ctx.Providers.SessionProvider.ListUserSessions(testUsername)
+			require.NoError(t, err)
+			assert.Len(t, sessions, 2)
+		})
+	}
+}
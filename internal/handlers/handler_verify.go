@@ -3,8 +3,10 @@ package handlers
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -19,10 +21,6 @@ import (
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
-func isURLUnderProtectedDomain(url *url.URL, domain string) bool {
-	return strings.HasSuffix(url.Hostname(), domain)
-}
-
 func isSchemeHTTPS(url *url.URL) bool {
 	return url.Scheme == "https"
 }
@@ -53,67 +51,123 @@ func parseBasicAuth(header []byte, auth string) (username, password string, err
 	return cs[:s], cs[s+1:], nil
 }
 
-// isTargetURLAuthorized check whether the given user is authorized to access the resource.
+// getRequestHeader returns the headers of the request forwarded by the proxy excluding the sensitive headers. It returns
+// nil if the authorizer doesn't have any rules which match the request headers.
+func getRequestHeader(ctx *middlewares.AutheliaCtx) (header http.Header) {
+	if !ctx.Providers.Authorizer.IsHeadersRequired() {
+		return nil
+	}
+
+	header = http.Header{}
+
+	ctx.Request.Header.VisitAll(func(key, value []byte) {
+		name := string(key)
+
+		if utils.IsStringInSliceFold(name, authorization.SensitiveHeaders) {
+			return
+		}
+
+		header.Add(name, string(value))
+	})
+
+	return header
+}
+
+// isTargetURLAuthorized check whether the given user is authorized to access the resource. When the user is not
+// authorized the steps which the user must take to satisfy the matched rule are also returned.
 func isTargetURLAuthorized(authorizer *authorization.Authorizer, targetURL url.URL,
-	username string, userGroups []string, clientIP net.IP, method []byte, authLevel authentication.Level) authorizationMatching {
-	hasSubject, level := authorizer.GetRequiredLevel(
+	username string, userGroups, userEmails []string, userAttributes map[string][]string, clientIP net.IP, method []byte, header http.Header,
+	authn authenticationState, now time.Time) (matching authorizationMatching, stepUp authorizationStepUp) {
+	object := authorization.NewObjectRaw(&targetURL, method)
+
+	object.Header = header
+
+	requirement := authorizer.GetRequirement(
 		authorization.Subject{
-			Username: username,
-			Groups:   userGroups,
-			IP:       clientIP,
+			Username:   username,
+			Groups:     userGroups,
+			Emails:     userEmails,
+			Attributes: userAttributes,
+			IP:         clientIP,
+
+			AuthenticationLevel: authn.Level,
+			AMR:                 authn.AMR.MarshalRFC8176(),
 		},
-		authorization.NewObjectRaw(&targetURL, method))
+		object)
+
+	level := requirement.Level
 
 	switch {
 	case level == authorization.Bypass:
-		return Authorized
-	case level == authorization.Denied && (username != "" || !hasSubject):
+		return Authorized, stepUp
+	case level == authorization.Denied && (username != "" || !requirement.HasSubjects):
 		// If the user is not anonymous, it means that we went through
 		// all the rules related to that user and knowing who he is we can
 		// deduce the access is forbidden
 		// For anonymous users though, we check that the matched rule has no subject
 		// if matched rule has not subject then this rule applies to all users including anonymous.
-		return Forbidden
-	case level == authorization.OneFactor && authLevel >= authentication.OneFactor,
-		level == authorization.TwoFactor && authLevel >= authentication.TwoFactor:
-		return Authorized
+		return Forbidden, stepUp
+	case level == authorization.OneFactor && authn.Level >= authentication.OneFactor,
+		level == authorization.TwoFactor && authn.Level >= authentication.TwoFactor:
+		switch {
+		case isAuthenticationTooOld(authn.FirstFactorTime, requirement.MaxAuthenticationAgeFirstFactor, now):
+			stepUp.Reauthenticate = authorization.OneFactor.String()
+		case level == authorization.TwoFactor && isAuthenticationTooOld(authn.SecondFactorTime, requirement.MaxAuthenticationAgeSecondFactor, now):
+			stepUp.Reauthenticate = authorization.TwoFactor.String()
+		case level != authorization.TwoFactor || authn.AMR.IsSecondFactorMethodSatisfied(requirement.SecondFactorMethods):
+			return Authorized, stepUp
+		}
+	}
+
+	stepUp.SecondFactorMethods = requirement.SecondFactorMethods
+
+	return NotAuthorized, stepUp
+}
+
+// isAuthenticationTooOld returns true if the authentication at the given time is older than the maximum age. The age
+// isn't checked if the maximum age is 0.
+func isAuthenticationTooOld(authenticated time.Time, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 {
+		return false
 	}
 
-	return NotAuthorized
+	return authenticated.Add(maxAge).Before(now)
 }
 
 // verifyBasicAuth verify that the provided username and password are correct and
 // that the user is authorized to target the resource.
-func verifyBasicAuth(ctx *middlewares.AutheliaCtx, header, auth []byte) (username, name string, groups, emails []string, authLevel authentication.Level, err error) {
+func verifyBasicAuth(ctx *middlewares.AutheliaCtx, header, auth []byte) (username, name string, groups, emails []string, attributes map[string][]string, authLevel authentication.Level, err error) {
 	username, password, err := parseBasicAuth(header, string(auth))
 
 	if err != nil {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to parse content of %s header: %s", header, err)
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to parse content of %s header: %s", header, err)
 	}
 
 	authenticated, err := ctx.Providers.UserProvider.CheckUserPassword(username, password)
 
 	if err != nil {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to check credentials extracted from %s header: %w", header, err)
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to check credentials extracted from %s header: %w", header, err)
 	}
 
 	// If the user is not correctly authenticated, send a 401.
 	if !authenticated {
 		// Request Basic Authentication otherwise.
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("user %s is not authenticated", username)
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("user %s is not authenticated", username)
 	}
 
 	details, err := ctx.Providers.UserProvider.GetDetails(username)
 
 	if err != nil {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to retrieve details of user %s: %s", username, err)
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to retrieve details of user %s: %s", username, err)
 	}
 
-	return username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
+	return username, details.DisplayName, details.Groups, details.Emails, details.Attributes, authentication.OneFactor, nil
 }
 
-// setForwardedHeaders set the forwarded User, Groups, Name and Email headers.
-func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string, groups, emails []string) {
+// setForwardedHeaders set the forwarded User, Groups, Name and Email headers as well as the headers for the extra
+// attributes which have a header configured.
+func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string, groups, emails []string,
+	attributes map[string][]string, extra []schema.AuthenticationBackendExtraAttribute) {
 	if username != "" {
 		headers.SetBytesK(headerRemoteUser, username)
 		headers.SetBytesK(headerRemoteGroups, strings.Join(groups, ","))
@@ -124,60 +178,84 @@ func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string
 		} else {
 			headers.SetBytesK(headerRemoteEmail, "")
 		}
+
+		for _, attribute := range extra {
+			if attribute.Header == "" {
+				continue
+			}
+
+			headers.Set(attribute.Header, strings.Join(attributes[attribute.Name], ","))
+		}
 	}
 }
 
 func isSessionInactiveTooLong(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, isUserAnonymous bool) (isInactiveTooLong bool) {
-	if userSession.KeepMeLoggedIn || isUserAnonymous || int64(ctx.Providers.SessionProvider.Inactivity.Seconds()) == 0 {
+	inactivity := ctx.Providers.SessionProvider.GetCookieConfiguration(ctx.GetSessionHost()).Inactivity
+
+	if userSession.KeepMeLoggedIn || isUserAnonymous || int64(inactivity.Seconds()) == 0 {
 		return false
 	}
 
-	isInactiveTooLong = time.Unix(userSession.LastActivity, 0).Add(ctx.Providers.SessionProvider.Inactivity).Before(ctx.Clock.Now())
+	isInactiveTooLong = time.Unix(userSession.LastActivity, 0).Add(inactivity).Before(ctx.Clock.Now())
 
-	ctx.Logger.Tracef("Inactivity report for user '%s'. Current Time: %d, Last Activity: %d, Maximum Inactivity: %d.", userSession.Username, ctx.Clock.Now().Unix(), userSession.LastActivity, int(ctx.Providers.SessionProvider.Inactivity.Seconds()))
+	ctx.Logger.Tracef("Inactivity report for user '%s'. Current Time: %d, Last Activity: %d, Maximum Inactivity: %d.", userSession.Username, ctx.Clock.Now().Unix(), userSession.LastActivity, int(inactivity.Seconds()))
 
 	return isInactiveTooLong
 }
 
 // verifySessionCookie verifies if a user is identified by a cookie.
 func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userSession *session.UserSession, refreshProfile bool,
-	refreshProfileInterval time.Duration) (username, name string, groups, emails []string, authLevel authentication.Level, err error) {
+	refreshProfileInterval time.Duration) (username, name string, groups, emails []string, attributes map[string][]string, authLevel authentication.Level, err error) {
 	// No username in the session means the user is anonymous.
 	isUserAnonymous := userSession.IsAnonymous()
 
 	if isUserAnonymous && userSession.AuthenticationLevel != authentication.NotAuthenticated {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("an anonymous user cannot be authenticated (this might be the sign of a security compromise)")
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("an anonymous user cannot be authenticated (this might be the sign of a security compromise)")
 	}
 
 	if isSessionInactiveTooLong(ctx, userSession, isUserAnonymous) {
 		// Destroy the session a new one will be regenerated on next request.
-		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
-			return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to destroy session for user '%s' after the session has been inactive too long: %w", userSession.Username, err)
+		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx, ctx.GetSessionHost()); err != nil {
+			return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to destroy session for user '%s' after the session has been inactive too long: %w", userSession.Username, err)
 		}
 
 		ctx.Logger.Warnf("Session destroyed for user '%s' after exceeding configured session inactivity and not being marked as remembered", userSession.Username)
 
-		return "", "", nil, nil, authentication.NotAuthenticated, nil
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, nil
+	}
+
+	switch revoked, err := ctx.IsSessionRevoked(userSession); {
+	case err != nil:
+		ctx.Logger.Errorf("Unable to check if the session of user '%s' has been revoked: %v", userSession.Username, err)
+	case revoked:
+		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx, ctx.GetSessionHost()); err != nil {
+			return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to destroy session for user '%s' after the session has been revoked: %w", userSession.Username, err)
+		}
+
+		ctx.Logger.Infof("Session destroyed for user '%s' after the sessions of the user were revoked", userSession.Username)
+
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, nil
 	}
 
 	if err = verifySessionHasUpToDateProfile(ctx, targetURL, userSession, refreshProfile, refreshProfileInterval); err != nil {
-		if err == authentication.ErrUserNotFound {
-			if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
-				ctx.Logger.Errorf("Unable to destroy user session after provider refresh didn't find the user: %v", err)
+		if errors.Is(err, authentication.ErrUserNotFound) || errors.Is(err, authentication.ErrAccountDisabled) ||
+			errors.Is(err, authentication.ErrAccountExpired) {
+			if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx, ctx.GetSessionHost()); err != nil {
+				ctx.Logger.Errorf("Unable to destroy user session after provider refresh determined the user can no longer sign in: %v", err)
 			}
 
-			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, authentication.NotAuthenticated, err
+			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.Attributes, authentication.NotAuthenticated, err
 		}
 
 		ctx.Logger.Errorf("Error occurred while attempting to update user details from LDAP: %v", err)
 
-		return "", "", nil, nil, authentication.NotAuthenticated, err
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, err
 	}
 
-	return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.AuthenticationLevel, nil
+	return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.Attributes, userSession.AuthenticationLevel, nil
 }
 
-func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, isBasicAuth bool, username string, method []byte) {
+func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL *url.URL, isBasicAuth bool, username string, method []byte, stepUp authorizationStepUp) {
 	var (
 		statusCode            int
 		friendlyUsername      string
@@ -208,11 +286,19 @@ func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, is
 		friendlyRequestMethod = rm
 	}
 
+	cookie := ctx.Providers.SessionProvider.Get(targetURL.Hostname())
+
 	redirectionURL := ctxGetPortalURL(ctx)
 
+	if redirectionURL == nil && cookie != nil && cookie.Config.AutheliaURL.String() != "" {
+		portalURL := cookie.Config.AutheliaURL
+
+		redirectionURL = &portalURL
+	}
+
 	if redirectionURL != nil {
-		if !utils.IsURISafeRedirection(redirectionURL, ctx.Configuration.Session.Domain) {
-			ctx.Logger.Errorf("Configured Portal URL '%s' does not appear to be able to write cookies for the '%s' domain", redirectionURL, ctx.Configuration.Session.Domain)
+		if cookie == nil || !utils.IsURISafeRedirection(redirectionURL, cookie.Config.Domain) {
+			ctx.Logger.Errorf("Configured Portal URL '%s' does not appear to be able to write cookies for the domain of the target URL '%s'", redirectionURL, targetURL)
 
 			ctx.ReplyUnauthorized()
 
@@ -227,6 +313,14 @@ func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, is
 			qry.Set("rm", rm)
 		}
 
+		if len(stepUp.SecondFactorMethods) != 0 {
+			qry.Set(queryArgSecondFactorMethods, strings.Join(stepUp.SecondFactorMethods, ","))
+		}
+
+		if stepUp.Reauthenticate != "" {
+			qry.Set(queryArgReauthenticate, stepUp.Reauthenticate)
+		}
+
 		redirectionURL.RawQuery = qry.Encode()
 	}
 
@@ -340,8 +434,9 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 	emailsDiff := utils.IsStringSlicesDifferent(userSession.Emails, details.Emails)
 	groupsDiff := utils.IsStringSlicesDifferent(userSession.Groups, details.Groups)
 	nameDiff := userSession.DisplayName != details.DisplayName
+	attributesDiff := isAttributesDifferent(userSession.Attributes, details.Attributes)
 
-	if !groupsDiff && !emailsDiff && !nameDiff {
+	if !groupsDiff && !emailsDiff && !nameDiff && !attributesDiff {
 		ctx.Logger.Tracef("Updated profile not detected for %s.", userSession.Username)
 		// Only update TTL if the user has an interval set.
 		// We get to this check when there were no changes.
@@ -360,6 +455,7 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 		userSession.Emails = details.Emails
 		userSession.Groups = details.Groups
 		userSession.DisplayName = details.DisplayName
+		userSession.Attributes = details.Attributes
 
 		// Only update TTL if the user has a interval set.
 		if refreshProfileInterval != schema.RefreshIntervalAlways {
@@ -373,6 +469,21 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 	return nil
 }
 
+// isAttributesDifferent returns true if the extra attributes of a user have changed.
+func isAttributesDifferent(a, b map[string][]string) bool {
+	if len(a) != len(b) {
+		return true
+	}
+
+	for name, values := range a {
+		if utils.IsStringSlicesDifferent(values, b[name]) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func getProfileRefreshSettings(cfg schema.AuthenticationBackend) (refresh bool, refreshInterval time.Duration) {
 	if cfg.LDAP != nil {
 		if cfg.RefreshInterval == schema.ProfileRefreshDisabled {
@@ -393,7 +504,7 @@ func getProfileRefreshSettings(cfg schema.AuthenticationBackend) (refresh bool,
 	return refresh, refreshInterval
 }
 
-func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile bool, refreshProfileInterval time.Duration) (isBasicAuth bool, username, name string, groups, emails []string, authLevel authentication.Level, err error) {
+func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile bool, refreshProfileInterval time.Duration) (isBasicAuth bool, username, name string, groups, emails []string, attributes map[string][]string, authLevel authentication.Level, err error) {
 	authHeader := headerProxyAuthorization
 	if bytes.Equal(ctx.QueryArgs().Peek("auth"), []byte("basic")) {
 		authHeader = headerAuthorization
@@ -404,32 +515,32 @@ func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile
 	if authValue != nil {
 		isBasicAuth = true
 	} else if isBasicAuth {
-		return isBasicAuth, username, name, groups, emails, authLevel, fmt.Errorf("basic auth requested via query arg, but no value provided via %s header", authHeader)
+		return isBasicAuth, username, name, groups, emails, attributes, authLevel, fmt.Errorf("basic auth requested via query arg, but no value provided via %s header", authHeader)
 	}
 
 	if isBasicAuth {
-		username, name, groups, emails, authLevel, err = verifyBasicAuth(ctx, authHeader, authValue)
+		username, name, groups, emails, attributes, authLevel, err = verifyBasicAuth(ctx, authHeader, authValue)
 
-		return isBasicAuth, username, name, groups, emails, authLevel, err
+		return isBasicAuth, username, name, groups, emails, attributes, authLevel, err
 	}
 
 	userSession := ctx.GetSession()
-	if username, name, groups, emails, authLevel, err = verifySessionCookie(ctx, targetURL, &userSession, refreshProfile, refreshProfileInterval); err != nil {
-		return isBasicAuth, username, name, groups, emails, authLevel, err
+	if username, name, groups, emails, attributes, authLevel, err = verifySessionCookie(ctx, targetURL, &userSession, refreshProfile, refreshProfileInterval); err != nil {
+		return isBasicAuth, username, name, groups, emails, attributes, authLevel, err
 	}
 
 	sessionUsername := ctx.Request.Header.PeekBytes(headerSessionUsername)
 	if sessionUsername != nil && !strings.EqualFold(string(sessionUsername), username) {
 		ctx.Logger.Warnf("Possible cookie hijack or attempt to bypass security detected destroying the session and sending 401 response")
 
-		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx, ctx.GetSessionHost()); err != nil {
 			ctx.Logger.Errorf("Unable to destroy user session after handler could not match them to their %s header: %s", headerSessionUsername, err)
 		}
 
-		return isBasicAuth, username, name, groups, emails, authLevel, fmt.Errorf("could not match user %s to their %s header with a value of %s when visiting %s", username, headerSessionUsername, sessionUsername, targetURL.String())
+		return isBasicAuth, username, name, groups, emails, attributes, authLevel, fmt.Errorf("could not match user %s to their %s header with a value of %s when visiting %s", username, headerSessionUsername, sessionUsername, targetURL.String())
 	}
 
-	return isBasicAuth, username, name, groups, emails, authLevel, err
+	return isBasicAuth, username, name, groups, emails, attributes, authLevel, err
 }
 
 // VerifyGET returns the handler verifying if a request is allowed to go through.
@@ -455,16 +566,15 @@ func VerifyGET(cfg schema.AuthenticationBackend) middlewares.RequestHandler {
 			return
 		}
 
-		if !isURLUnderProtectedDomain(targetURL, ctx.Configuration.Session.Domain) {
-			ctx.Logger.Errorf("Target URL %s is not under the protected domain %s",
-				targetURL.String(), ctx.Configuration.Session.Domain)
+		if ctx.Providers.SessionProvider.Get(targetURL.Hostname()) == nil {
+			ctx.Logger.Errorf("Target URL %s is not under any of the protected domains", targetURL.String())
 			ctx.ReplyUnauthorized()
 
 			return
 		}
 
 		method := ctx.XForwardedMethod()
-		isBasicAuth, username, name, groups, emails, authLevel, err := verifyAuth(ctx, targetURL, refreshProfile, refreshProfileInterval)
+		isBasicAuth, username, name, groups, emails, attributes, authLevel, err := verifyAuth(ctx, targetURL, refreshProfile, refreshProfileInterval)
 
 		if err != nil {
 			ctx.Logger.Errorf("Error caught when verifying user authorization: %s", err)
@@ -474,22 +584,38 @@ func VerifyGET(cfg schema.AuthenticationBackend) middlewares.RequestHandler {
 				return
 			}
 
-			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method)
+			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method, authorizationStepUp{})
 
 			return
 		}
 
-		authorized := isTargetURLAuthorized(ctx.Providers.Authorizer, *targetURL, username,
-			groups, ctx.RemoteIP(), method, authLevel)
+		authn := authenticationState{Level: authLevel}
+
+		if isBasicAuth {
+			authn.FirstFactorTime = ctx.Clock.Now()
+		} else {
+			userSession := ctx.GetSession()
+
+			authn.AMR = userSession.AuthenticationMethodRefs
+			authn.FirstFactorTime = time.Unix(userSession.FirstFactorAuthnTimestamp, 0)
+			authn.SecondFactorTime = time.Unix(userSession.SecondFactorAuthnTimestamp, 0)
+		}
+
+		authorized, stepUp := isTargetURLAuthorized(ctx.Providers.Authorizer, *targetURL, username,
+			groups, emails, attributes, ctx.RemoteIP(), method, getRequestHeader(ctx), authn, ctx.Clock.Now())
 
 		switch authorized {
 		case Forbidden:
 			ctx.Logger.Infof("Access to %s is forbidden to user %s", targetURL.String(), username)
 			ctx.ReplyForbidden()
 		case NotAuthorized:
-			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method)
+			if stepUp.Reauthenticate != "" {
+				ctx.Logger.Infof("Access to %s requires user %s to re-authenticate as their %s authentication is older than the maximum authentication age", targetURL.String(), username, stepUp.Reauthenticate)
+			}
+
+			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method, stepUp)
 		case Authorized:
-			setForwardedHeaders(&ctx.Response.Header, username, name, groups, emails)
+			setForwardedHeaders(&ctx.Response.Header, username, name, groups, emails, attributes, cfg.ExtraAttributes)
 		}
 
 		if err = updateActivityTimestamp(ctx, isBasicAuth); err != nil {
@@ -3,12 +3,13 @@ package handlers
 import (
 	"github.com/ory/fosite"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
-func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2ConsentSession, userSession *session.UserSession) (extraClaims map[string]any) {
+func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2ConsentSession, userSession *session.UserSession, attributes []schema.AuthenticationBackendExtraAttribute) (extraClaims map[string]any) {
 	extraClaims = map[string]any{}
 
 	for _, scope := range consent.GrantedScopes {
@@ -22,6 +23,8 @@ func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2Consen
 		case oidc.ScopeProfile:
 			extraClaims[oidc.ClaimPreferredUsername] = userSession.Username
 			extraClaims[oidc.ClaimFullName] = userSession.DisplayName
+
+			oidcGrantAttributeClaims(extraClaims, userSession, attributes)
 		case oidc.ScopeEmail:
 			if len(userSession.Emails) != 0 {
 				extraClaims[oidc.ClaimPreferredEmail] = userSession.Emails[0]
@@ -43,3 +46,22 @@ func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2Consen
 
 	return extraClaims
 }
+
+// oidcGrantAttributeClaims adds the claims for the extra attributes which have a claim configured. Attributes with a
+// single value are released as a string and attributes with multiple values are released as a list.
+func oidcGrantAttributeClaims(extraClaims map[string]any, userSession *session.UserSession, attributes []schema.AuthenticationBackendExtraAttribute) {
+	for _, attribute := range attributes {
+		if attribute.Claim == "" {
+			continue
+		}
+
+		switch values := userSession.Attributes[attribute.Name]; len(values) {
+		case 0:
+			continue
+		case 1:
+			extraClaims[attribute.Claim] = values[0]
+		default:
+			extraClaims[attribute.Claim] = values
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -16,7 +17,7 @@ func TestShouldGrantAppropriateClaimsForScopeProfile(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeProfile},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -32,7 +33,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeGroups},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -41,7 +42,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "admin")
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "dev")
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -55,7 +56,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeEmail},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 3)
 
@@ -69,7 +70,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimEmailVerified)
 	assert.Equal(t, true, extraClaims[oidc.ClaimEmailVerified])
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -85,7 +86,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeOpenID, oidc.ScopeProfile},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -95,7 +96,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimFullName)
 	assert.Equal(t, "John Smith", extraClaims[oidc.ClaimFullName])
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -106,6 +107,43 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	assert.Equal(t, extraClaims[oidc.ClaimFullName], "Fred Smith")
 }
 
+func TestShouldGrantAppropriateClaimsForExtraAttributes(t *testing.T) {
+	consent := &model.OAuth2ConsentSession{
+		GrantedScopes: []string{oidc.ScopeProfile},
+	}
+
+	userSession := oidcUserSessionJohn
+	userSession.Attributes = map[string][]string{
+		"department": {"engineering"},
+		"phone":      {"555-0100", "555-0101"},
+		"title":      {"Engineer"},
+	}
+
+	attributes := []schema.AuthenticationBackendExtraAttribute{
+		{Name: "department", Claim: "department"},
+		{Name: "phone", Claim: "phone_numbers"},
+		{Name: "employee_number", Claim: "employee_number"},
+		{Name: "title"},
+	}
+
+	extraClaims := oidcGrantRequests(nil, consent, &userSession, attributes)
+
+	assert.Len(t, extraClaims, 4)
+
+	require.Contains(t, extraClaims, "department")
+	assert.Equal(t, "engineering", extraClaims["department"])
+
+	require.Contains(t, extraClaims, "phone_numbers")
+	assert.Equal(t, []string{"555-0100", "555-0101"}, extraClaims["phone_numbers"])
+
+	consent.GrantedScopes = []string{oidc.ScopeGroups}
+
+	extraClaims = oidcGrantRequests(nil, consent, &userSession, attributes)
+
+	assert.Len(t, extraClaims, 1)
+	assert.NotContains(t, extraClaims, "department")
+}
+
 var (
 	oidcUserSessionJohn = session.UserSession{
 		Username:    "john",
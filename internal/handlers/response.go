@@ -13,11 +13,10 @@ import (
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
-	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // Handle1FAResponse handle the redirection upon 1FA authentication.
-func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod string, username string, groups []string) {
+func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod string, username string, groups, emails []string, attributes map[string][]string) {
 	var err error
 
 	if len(targetURI) == 0 {
@@ -40,11 +39,18 @@ func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod st
 		return
 	}
 
+	userSession := ctx.GetSession()
+
 	_, requiredLevel := ctx.Providers.Authorizer.GetRequiredLevel(
 		authorization.Subject{
-			Username: username,
-			Groups:   groups,
-			IP:       ctx.RemoteIP(),
+			Username:   username,
+			Groups:     groups,
+			Emails:     emails,
+			Attributes: attributes,
+			IP:         ctx.RemoteIP(),
+
+			AuthenticationLevel: userSession.AuthenticationLevel,
+			AMR:                 userSession.AuthenticationMethodRefs.MarshalRFC8176(),
 		},
 		authorization.NewObject(targetURL, requestMethod))
 
@@ -57,7 +63,7 @@ func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod st
 		return
 	}
 
-	if !utils.IsURISafeRedirection(targetURL, ctx.Configuration.Session.Domain) {
+	if !isURISafeRedirection(ctx, targetURL) {
 		ctx.Logger.Debugf("Redirection URL %s is not safe", targetURI)
 
 		if !ctx.Providers.Authorizer.IsSecondFactorEnabled() && ctx.Configuration.DefaultRedirectionURL != "" {
@@ -100,7 +106,7 @@ func Handle2FAResponse(ctx *middlewares.AutheliaCtx, targetURI string) {
 
 	var safe bool
 
-	if safe, err = utils.IsURIStringSafeRedirection(targetURI, ctx.Configuration.Session.Domain); err != nil {
+	if safe, err = isURIStringSafeRedirection(ctx, targetURI); err != nil {
 		ctx.Error(fmt.Errorf("unable to check target URL: %s", err), messageMFAValidationFailed)
 
 		return
@@ -205,7 +211,7 @@ func handleOIDCWorkflowResponseWithID(ctx *middlewares.AutheliaCtx, id string) {
 		return
 	}
 
-	if !client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel) {
+	if !client.IsAuthenticationSufficient(userSession.AuthenticationLevel, userSession.AuthenticationMethodRefs) {
 		ctx.Logger.Warnf("OpenID Connect client '%s' requires 2FA, cannot be redirected yet", client.ID)
 		ctx.ReplyOK()
 
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"net"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
@@ -19,6 +21,26 @@ type MethodList = []string
 
 type authorizationMatching int
 
+// authenticationState describes how the user making a request has authenticated.
+type authenticationState struct {
+	Level authentication.Level
+	AMR   oidc.AuthenticationMethodsReferences
+
+	FirstFactorTime  time.Time
+	SecondFactorTime time.Time
+}
+
+// authorizationStepUp describes what the user must do to satisfy the requirements of the matched rule when they are
+// not authorized.
+type authorizationStepUp struct {
+	// SecondFactorMethods are the second factor methods which satisfy the matched rule.
+	SecondFactorMethods []string
+
+	// Reauthenticate is the policy name of the factor the user must authenticate again as it's older than the matched
+	// rule allows.
+	Reauthenticate string
+}
+
 // configurationBody the content returned by the configuration endpoint.
 type configurationBody struct {
 	AvailableMethods MethodList `json:"available_methods"`
@@ -119,6 +141,7 @@ type DuoSignResponse struct {
 type StateResponse struct {
 	Username              string               `json:"username"`
 	AuthenticationLevel   authentication.Level `json:"authentication_level"`
+	SecondFactorMethods   []string             `json:"second_factor_methods,omitempty"`
 	DefaultRedirectionURL string               `json:"default_redirection_url"`
 }
 
@@ -132,6 +155,69 @@ type resetPasswordStep2RequestBody struct {
 	Password string `json:"password"`
 }
 
+// bodyChangePasswordRequest model of the change password request body.
+type bodyChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// bodyAuthzDecideRequest model of the policy decision request body.
+type bodyAuthzDecideRequest struct {
+	Subject bodyAuthzDecideSubject `json:"subject"`
+	Object  bodyAuthzDecideObject  `json:"object"`
+}
+
+// bodyAuthzDecideSubject model of the subject of the policy decision request body.
+type bodyAuthzDecideSubject struct {
+	Username   string              `json:"username"`
+	Groups     []string            `json:"groups"`
+	Emails     []string            `json:"emails"`
+	Attributes map[string][]string `json:"attributes"`
+	IP         net.IP              `json:"ip"`
+	Level      string              `json:"level"`
+	AMR        []string            `json:"amr"`
+
+	// Resolve loads the groups, emails, and attributes of the user from the authentication backend.
+	Resolve bool `json:"resolve"`
+}
+
+// bodyAuthzDecideObject model of the object of the policy decision request body.
+type bodyAuthzDecideObject struct {
+	URL     string              `json:"url" valid:"required"`
+	Method  string              `json:"method"`
+	Headers map[string][]string `json:"headers"`
+	Time    time.Time           `json:"time"`
+}
+
+// AuthzDecideResponse represents the response sent by the policy decision endpoint.
+type AuthzDecideResponse struct {
+	Policy string `json:"policy"`
+	Rule   int    `json:"rule"`
+
+	Results []AuthzDecideRuleResult `json:"results"`
+}
+
+// AuthzDecideRuleResult represents the per-criteria match breakdown of a rule in the policy decision response.
+type AuthzDecideRuleResult struct {
+	Rule           int    `json:"rule"`
+	Policy         string `json:"policy"`
+	Skipped        bool   `json:"skipped"`
+	Match          bool   `json:"match"`
+	PotentialMatch bool   `json:"potential_match"`
+
+	Domain         bool `json:"domain"`
+	Resources      bool `json:"resources"`
+	Query          bool `json:"query"`
+	Headers        bool `json:"headers"`
+	Methods        bool `json:"methods"`
+	Networks       bool `json:"networks"`
+	Schedule       bool `json:"schedule"`
+	Subjects       bool `json:"subjects"`
+	SubjectsExact  bool `json:"subjects_exact"`
+	Condition      bool `json:"condition"`
+	ConditionExact bool `json:"condition_exact"`
+}
+
 // PasswordPolicyBody represents the response sent by the password reset step 2.
 type PasswordPolicyBody struct {
 	Mode             string `json:"mode"`
@@ -142,6 +228,7 @@ type PasswordPolicyBody struct {
 	RequireLowercase bool   `json:"require_lowercase"`
 	RequireNumber    bool   `json:"require_number"`
 	RequireSpecial   bool   `json:"require_special"`
+	Breached         bool   `json:"breached"`
 }
 
 type handlerAuthorizationConsent func(
@@ -149,3 +236,20 @@ type handlerAuthorizationConsent func(
 	userSession session.UserSession, subject uuid.UUID,
 	rw http.ResponseWriter, r *http.Request,
 	requester fosite.AuthorizeRequester) (consent *model.OAuth2ConsentSession, handled bool)
+
+// bodyRevokeUserSessionRequest model of the revoke user session request body.
+type bodyRevokeUserSessionRequest struct {
+	ID string `json:"id"`
+}
+
+// UserSessionResponse represents a session of the user in the response sent by the user sessions endpoint.
+type UserSessionResponse struct {
+	ID                  string    `json:"id"`
+	Domain              string    `json:"domain"`
+	IP                  string    `json:"ip"`
+	UserAgent           string    `json:"user_agent"`
+	CreatedAt           time.Time `json:"created_at"`
+	LastActivityAt      time.Time `json:"last_activity_at"`
+	AuthenticationLevel string    `json:"authentication_level"`
+	Current             bool      `json:"current"`
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"bytes"
+	"fmt"
 	"net/url"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 var bytesEmpty = []byte("")
@@ -24,3 +26,19 @@ func ctxGetPortalURL(ctx *middlewares.AutheliaCtx) (portalURL *url.URL) {
 
 	return nil
 }
+
+// isURISafeRedirection returns true if the URI has a secure scheme and is under one of the protected cookie domains.
+func isURISafeRedirection(ctx *middlewares.AutheliaCtx, uri *url.URL) bool {
+	return utils.IsURISecure(uri) && ctx.Providers.SessionProvider.Get(uri.Hostname()) != nil
+}
+
+// isURIStringSafeRedirection is the same as isURISafeRedirection but parses the URI first.
+func isURIStringSafeRedirection(ctx *middlewares.AutheliaCtx, uri string) (safe bool, err error) {
+	var parsedURI *url.URL
+
+	if parsedURI, err = url.ParseRequestURI(uri); err != nil {
+		return false, fmt.Errorf("failed to parse URI '%s': %w", uri, err)
+	}
+
+	return isURISafeRedirection(ctx, parsedURI), nil
+}
@@ -3,6 +3,7 @@ package metrics
 import (
 	"time"
 
+	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/regulation"
 )
 
@@ -10,6 +11,9 @@ import (
 type Provider interface {
 	Recorder
 	regulation.MetricsRecorder
+	LDAPPoolRecorder
+	PasswordPolicyRecorder
+	authorization.MetricsRecorder
 }
 
 // Recorder of metrics.
@@ -18,3 +22,15 @@ type Recorder interface {
 	RecordVerifyRequest(statusCode string)
 	RecordAuthenticationDuration(success bool, elapsed time.Duration)
 }
+
+// LDAPPoolRecorder of LDAP connection pool metrics.
+type LDAPPoolRecorder interface {
+	RecordLDAPPoolConnections(open, idle int)
+	RecordLDAPPoolAcquire(success bool, elapsed time.Duration)
+	RecordLDAPPoolConnectionClosed(reason string)
+}
+
+// PasswordPolicyRecorder of password policy metrics.
+type PasswordPolicyRecorder interface {
+	RecordPasswordPolicyBreachedCheck(result string)
+}
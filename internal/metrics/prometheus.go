@@ -25,6 +25,14 @@ type Prometheus struct {
 	reqVerifyCounter *prometheus.CounterVec
 	auth1FACounter   *prometheus.CounterVec
 	auth2FACounter   *prometheus.CounterVec
+
+	ldapPoolConnections     *prometheus.GaugeVec
+	ldapPoolAcquireDuration *prometheus.HistogramVec
+	ldapPoolClosedCounter   *prometheus.CounterVec
+
+	passwordPolicyBreachedCounter *prometheus.CounterVec
+
+	accessControlDuration *prometheus.HistogramVec
 }
 
 // RecordRequest takes the statusCode string, requestMethod string, and the elapsed time.Duration to record the request and request duration metrics.
@@ -53,6 +61,34 @@ func (r *Prometheus) RecordAuthenticationDuration(success bool, elapsed time.Dur
 	r.authDuration.WithLabelValues(strconv.FormatBool(success)).Observe(elapsed.Seconds())
 }
 
+// RecordLDAPPoolConnections takes the number of open and idle connections to record the LDAP connection pool metrics.
+func (r *Prometheus) RecordLDAPPoolConnections(open, idle int) {
+	r.ldapPoolConnections.WithLabelValues("open").Set(float64(open))
+	r.ldapPoolConnections.WithLabelValues("idle").Set(float64(idle))
+}
+
+// RecordLDAPPoolAcquire takes the success boolean and the elapsed time.Duration to record the time taken to acquire a connection from the LDAP connection pool.
+func (r *Prometheus) RecordLDAPPoolAcquire(success bool, elapsed time.Duration) {
+	r.ldapPoolAcquireDuration.WithLabelValues(strconv.FormatBool(success)).Observe(elapsed.Seconds())
+}
+
+// RecordLDAPPoolConnectionClosed takes the reason string to record the LDAP connection pool connections which were closed.
+func (r *Prometheus) RecordLDAPPoolConnectionClosed(reason string) {
+	r.ldapPoolClosedCounter.WithLabelValues(reason).Inc()
+}
+
+// RecordPasswordPolicyBreachedCheck takes the result string to record the checks of passwords against the breached
+// password corpus.
+func (r *Prometheus) RecordPasswordPolicyBreachedCheck(result string) {
+	r.passwordPolicyBreachedCounter.WithLabelValues(result).Inc()
+}
+
+// RecordAccessControlEvaluation takes the matched boolean and the elapsed time.Duration to record the time taken to
+// evaluate the access control rules for a request.
+func (r *Prometheus) RecordAccessControlEvaluation(matched bool, elapsed time.Duration) {
+	r.accessControlDuration.WithLabelValues(strconv.FormatBool(matched)).Observe(elapsed.Seconds())
+}
+
 func (r *Prometheus) register() {
 	r.authDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -109,4 +145,51 @@ func (r *Prometheus) register() {
 		},
 		[]string{"success", "banned", "type"},
 	)
+
+	r.ldapPoolConnections = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "authelia",
+			Name:      "ldap_pool_connections",
+			Help:      "The number of connections in the LDAP connection pool.",
+		},
+		[]string{"state"},
+	)
+
+	r.ldapPoolAcquireDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: "authelia",
+			Name:      "ldap_pool_acquire_duration",
+			Help:      "The time taken to acquire a connection from the LDAP connection pool in seconds.",
+			Buckets:   []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"success"},
+	)
+
+	r.ldapPoolClosedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "ldap_pool_connections_closed",
+			Help:      "The number of connections closed by the LDAP connection pool.",
+		},
+		[]string{"reason"},
+	)
+
+	r.passwordPolicyBreachedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "password_policy_breached_check",
+			Help:      "The number of passwords checked against the breached password corpus.",
+		},
+		[]string{"result"},
+	)
+
+	r.accessControlDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: "authelia",
+			Name:      "access_control_evaluation_duration",
+			Help:      "The time taken to evaluate the access control rules for a request in seconds.",
+			Buckets:   []float64{.00001, .000025, .00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1},
+		},
+		[]string{"matched"},
+	)
 }
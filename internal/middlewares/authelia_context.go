@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/sirupsen/logrus"
@@ -227,9 +228,19 @@ func (ctx *AutheliaCtx) RootURLSlash() (issuerURL *url.URL) {
 	}
 }
 
+// GetSessionHost returns the host used to select the session cookie domain of the request. The host of the original
+// URL is used when it's available as the request may be an authorization request from a proxy.
+func (ctx *AutheliaCtx) GetSessionHost() string {
+	if originalURL, err := ctx.GetOriginalURL(); err == nil {
+		return originalURL.Hostname()
+	}
+
+	return (&url.URL{Host: string(ctx.XForwardedHost())}).Hostname()
+}
+
 // GetSession return the user session. Any update will be saved in cache.
 func (ctx *AutheliaCtx) GetSession() session.UserSession {
-	userSession, err := ctx.Providers.SessionProvider.GetSession(ctx.RequestCtx)
+	userSession, err := ctx.Providers.SessionProvider.GetSession(ctx.RequestCtx, ctx.GetSessionHost())
 	if err != nil {
 		ctx.Logger.Error("Unable to retrieve user session")
 		return session.NewDefaultUserSession()
@@ -240,7 +251,42 @@ func (ctx *AutheliaCtx) GetSession() session.UserSession {
 
 // SaveSession save the content of the session.
 func (ctx *AutheliaCtx) SaveSession(userSession session.UserSession) error {
-	return ctx.Providers.SessionProvider.SaveSession(ctx.RequestCtx, userSession)
+	return ctx.Providers.SessionProvider.SaveSession(ctx.RequestCtx, ctx.GetSessionHost(), userSession)
+}
+
+// IsSessionRevoked returns true if the sessions of the user were revoked after the user session was authenticated. The
+// storage is checked at most once per session every minute, the time of the last check is recorded in the session.
+func (ctx *AutheliaCtx) IsSessionRevoked(userSession *session.UserSession) (revoked bool, err error) {
+	if userSession.IsAnonymous() {
+		return false, nil
+	}
+
+	now := ctx.Clock.Now()
+
+	if userSession.RevocationCheckTTL.After(now) {
+		return false, nil
+	}
+
+	var revokedAt time.Time
+
+	if revokedAt, err = ctx.Providers.StorageProvider.LoadUserSessionRevocation(ctx, userSession.Username); err != nil {
+		return false, err
+	}
+
+	authenticatedAt := userSession.FirstFactorAuthnTime
+
+	// Sessions authenticated before the sub-second time was recorded only have the time in seconds.
+	if authenticatedAt.IsZero() {
+		authenticatedAt = time.Unix(userSession.FirstFactorAuthnTimestamp, 0)
+	}
+
+	if !revokedAt.IsZero() && authenticatedAt.Before(revokedAt) {
+		return true, nil
+	}
+
+	userSession.RevocationCheckTTL = now.Add(session.RevocationCheckInterval)
+
+	return false, ctx.SaveSession(*userSession)
 }
 
 // ReplyOK is a helper method to reply ok.
@@ -3,12 +3,14 @@ package middlewares_test
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/mocks"
@@ -122,6 +124,36 @@ func TestShouldGetOriginalURLFromForwardedHeadersWithURI(t *testing.T) {
 	assert.Equal(t, "Unable to parse URL extracted from X-Original-URL header: parse \"htt-ps//home?-.example.com\": invalid URI for request", err.Error())
 }
 
+func TestShouldGetSessionHost(t *testing.T) {
+	testCases := []struct {
+		name     string
+		host     string
+		headers  map[string]string
+		expected string
+	}{
+		{"ShouldUseHost", "auth.example.org", nil, "auth.example.org"},
+		{"ShouldUseXForwardedHost", "authelia:9091", map[string]string{fasthttp.HeaderXForwardedHost: "app.example.org:8443"}, "app.example.org"},
+		{"ShouldUseXForwardedHostWithProto", "authelia:9091", map[string]string{fasthttp.HeaderXForwardedProto: "https", fasthttp.HeaderXForwardedHost: "app.example.org:8443"}, "app.example.org"},
+		{"ShouldUseXOriginalURL", "authelia:9091", map[string]string{fasthttp.HeaderXForwardedHost: "app.example.com", "X-Original-URL": "https://app.example.org/"}, "app.example.org"},
+		{"ShouldIgnoreInvalidXOriginalURL", "authelia:9091", map[string]string{fasthttp.HeaderXForwardedHost: "app.example.com", "X-Original-URL": "htt-ps//home?-.example.com"}, "app.example.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Request.SetHost(tc.host)
+
+			for name, value := range tc.headers {
+				mock.Ctx.Request.Header.Set(name, value)
+			}
+
+			assert.Equal(t, tc.expected, mock.Ctx.GetSessionHost())
+		})
+	}
+}
+
 func TestShouldFallbackToNonXForwardedHeaders(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
@@ -191,3 +223,82 @@ func TestShouldReturnCorrectSecondFactorMethods(t *testing.T) {
 
 	assert.Equal(t, []string{}, mock.Ctx.AvailableSecondFactorMethods())
 }
+
+func TestShouldCheckSessionRevocation(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Clock.Set(time.Unix(1000000, 0))
+	mock.Ctx.Clock = &mock.Clock
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	userSession.AuthenticationLevel = authentication.OneFactor
+	userSession.FirstFactorAuthnTimestamp = mock.Clock.Now().Add(-time.Hour).Unix()
+
+	mock.StorageMock.EXPECT().LoadUserSessionRevocation(mock.Ctx, "john").Return(time.Time{}, nil)
+
+	revoked, err := mock.Ctx.IsSessionRevoked(&userSession)
+
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	assert.Equal(t, mock.Clock.Now().Add(time.Minute), userSession.RevocationCheckTTL)
+
+	// The storage is not checked again until the check interval has elapsed.
+	revoked, err = mock.Ctx.IsSessionRevoked(&userSession)
+
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	mock.Clock.Set(mock.Clock.Now().Add(time.Minute * 2))
+
+	mock.StorageMock.EXPECT().LoadUserSessionRevocation(mock.Ctx, "john").Return(mock.Clock.Now().Add(-time.Minute), nil)
+
+	revoked, err = mock.Ctx.IsSessionRevoked(&userSession)
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestShouldCheckSessionRevocationWithSubSecondPrecision(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Clock.Set(time.Unix(1000000, 0))
+	mock.Ctx.Clock = &mock.Clock
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	userSession.AuthenticationLevel = authentication.OneFactor
+	userSession.FirstFactorAuthnTimestamp = mock.Clock.Now().Unix()
+	userSession.FirstFactorAuthnTime = mock.Clock.Now().Add(time.Millisecond * 200)
+
+	// The sessions were revoked later within the same second the session was authenticated.
+	mock.StorageMock.EXPECT().LoadUserSessionRevocation(mock.Ctx, "john").Return(mock.Clock.Now().Add(time.Millisecond*500), nil)
+
+	revoked, err := mock.Ctx.IsSessionRevoked(&userSession)
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	userSession.FirstFactorAuthnTime = mock.Clock.Now().Add(time.Millisecond * 500)
+
+	mock.StorageMock.EXPECT().LoadUserSessionRevocation(mock.Ctx, "john").Return(mock.Clock.Now().Add(time.Millisecond*500), nil)
+
+	revoked, err = mock.Ctx.IsSessionRevoked(&userSession)
+
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestShouldNotCheckSessionRevocationForAnonymousUsers(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	userSession := mock.Ctx.GetSession()
+
+	revoked, err := mock.Ctx.IsSessionRevoked(&userSession)
+
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
@@ -105,3 +105,11 @@ const (
 var protoHostSeparator = []byte("://")
 
 var errPasswordPolicyNoMet = errors.New("the supplied password does not met the security policy")
+
+var (
+	// ErrPasswordPolicyHistoryReused is returned when the supplied password is in the password history of the user.
+	ErrPasswordPolicyHistoryReused = errors.New("the supplied password has been used recently")
+
+	// ErrPasswordPolicyBreached is returned when the supplied password is in the breached password corpus.
+	ErrPasswordPolicyBreached = errors.New("the supplied password has appeared in a data breach")
+)
@@ -1,11 +1,18 @@
 package middlewares
 
 import (
+	"context"
+	"crypto/x509"
+	"fmt"
 	"regexp"
 
+	"github.com/go-crypt/crypt"
+	"github.com/go-crypt/crypt/algorithm"
 	"github.com/trustelem/zxcvbn"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 // PasswordPolicyProvider represents an implementation of a password policy provider.
@@ -14,7 +21,21 @@ type PasswordPolicyProvider interface {
 }
 
 // NewPasswordPolicyProvider returns a new password policy provider.
-func NewPasswordPolicyProvider(config schema.PasswordPolicyConfiguration) (provider PasswordPolicyProvider) {
+func NewPasswordPolicyProvider(config schema.PasswordPolicyConfiguration, certPool *x509.CertPool) (provider PasswordPolicyProvider) {
+	provider = newPasswordPolicyProvider(config)
+
+	if config.Breached.Enabled {
+		return &BreachedPasswordPolicyProvider{
+			policy:    provider,
+			source:    NewBreachedPasswordSource(config.Breached, certPool),
+			threshold: config.Breached.Threshold,
+		}
+	}
+
+	return provider
+}
+
+func newPasswordPolicyProvider(config schema.PasswordPolicyConfiguration) (provider PasswordPolicyProvider) {
 	if !config.Standard.Enabled && !config.ZXCVBN.Enabled {
 		return &StandardPasswordPolicyProvider{}
 	}
@@ -92,3 +113,76 @@ func (p StandardPasswordPolicyProvider) Check(password string) (err error) {
 
 	return nil
 }
+
+// PasswordHistoryProvider represents an implementation of a password history provider which prevents users reusing
+// their recent passwords.
+type PasswordHistoryProvider interface {
+	CheckHistory(ctx context.Context, username, password string) (err error)
+	SaveHistory(ctx context.Context, username, password string) (err error)
+}
+
+// NewPasswordHistoryProvider returns a new password history provider. The digests of new passwords are created using the
+// password config, as each check matches the password against up to the configured count of digests the cost of a check
+// is proportional to both.
+func NewPasswordHistoryProvider(config schema.PasswordPolicyHistoryParams, password schema.Password, provider storage.Provider) (history PasswordHistoryProvider, err error) {
+	if !config.Enabled {
+		return &StoragePasswordHistoryProvider{}, nil
+	}
+
+	p := &StoragePasswordHistoryProvider{storage: provider, count: config.Count}
+
+	if p.hash, err = authentication.NewFileCryptoHashFromConfig(password); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// StoragePasswordHistoryProvider handles password history checking using the storage provider.
+type StoragePasswordHistoryProvider struct {
+	storage storage.Provider
+	hash    algorithm.Hash
+	count   int
+}
+
+// CheckHistory checks the password against the password history of the user.
+func (p StoragePasswordHistoryProvider) CheckHistory(ctx context.Context, username, password string) (err error) {
+	if p.count == 0 {
+		return nil
+	}
+
+	var digests []string
+
+	if digests, err = p.storage.LoadUserPasswordHistory(ctx, username, p.count); err != nil {
+		return err
+	}
+
+	var digest algorithm.Digest
+
+	for _, encoded := range digests {
+		if digest, err = crypt.Decode(encoded); err != nil {
+			return fmt.Errorf("error decoding password history for user '%s': %w", username, err)
+		}
+
+		if digest.Match(password) {
+			return ErrPasswordPolicyHistoryReused
+		}
+	}
+
+	return nil
+}
+
+// SaveHistory saves the password to the password history of the user.
+func (p StoragePasswordHistoryProvider) SaveHistory(ctx context.Context, username, password string) (err error) {
+	if p.count == 0 {
+		return nil
+	}
+
+	var digest algorithm.Digest
+
+	if digest, err = p.hash.Hash(password); err != nil {
+		return err
+	}
+
+	return p.storage.SaveUserPasswordHistory(ctx, username, digest.Encode(), p.count)
+}
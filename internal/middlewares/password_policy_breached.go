@@ -0,0 +1,256 @@
+package middlewares
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1" //nolint:gosec // SHA-1 is required to look up passwords in the breached password corpus.
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/metrics"
+)
+
+// BreachedPasswordSource represents a source of breached passwords which returns the number of times the password with
+// the given uppercase hex encoded SHA-1 hash has been seen in a breach.
+type BreachedPasswordSource interface {
+	Count(hash string) (count int, err error)
+}
+
+// NewBreachedPasswordSource returns a new BreachedPasswordSource given a valid configuration. The certificate pool is
+// trusted by the client of the range API.
+func NewBreachedPasswordSource(config schema.PasswordPolicyBreachedParams, certPool *x509.CertPool) (source BreachedPasswordSource) {
+	if config.Path != "" {
+		return &FileBreachedPasswordSource{path: config.Path}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    certPool,
+	}
+
+	return &RangeBreachedPasswordSource{
+		url:    config.URL,
+		client: &http.Client{Timeout: config.Timeout, Transport: transport},
+	}
+}
+
+// BreachedPasswordPolicyProvider handles checking passwords against a breached password corpus in addition to another
+// password policy.
+type BreachedPasswordPolicyProvider struct {
+	policy    PasswordPolicyProvider
+	source    BreachedPasswordSource
+	threshold int
+	metrics   metrics.PasswordPolicyRecorder
+}
+
+// SetMetricsRecorder sets the metrics recorder used to record the results of the checks against the corpus.
+func (p *BreachedPasswordPolicyProvider) SetMetricsRecorder(metrics metrics.PasswordPolicyRecorder) {
+	p.metrics = metrics
+}
+
+// Check checks the password against the policy.
+func (p *BreachedPasswordPolicyProvider) Check(password string) (err error) {
+	if err = p.policy.Check(password); err != nil {
+		return err
+	}
+
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // SHA-1 is required to look up passwords in the breached password corpus.
+
+	var count int
+
+	// The check fails open as an unavailable corpus should not prevent users from changing their password.
+	if count, err = p.source.Count(strings.ToUpper(hex.EncodeToString(sum[:]))); err != nil {
+		p.record(breachedResultError)
+
+		logging.Logger().WithError(err).Warn("Error occurred checking the password against the breached password corpus, the password was accepted without being checked against the corpus")
+
+		return nil
+	}
+
+	if count >= p.threshold {
+		p.record(breachedResultBreached)
+
+		return ErrPasswordPolicyBreached
+	}
+
+	p.record(breachedResultNotBreached)
+
+	return nil
+}
+
+func (p *BreachedPasswordPolicyProvider) record(result string) {
+	if p.metrics != nil {
+		p.metrics.RecordPasswordPolicyBreachedCheck(result)
+	}
+}
+
+// FileBreachedPasswordSource is a BreachedPasswordSource backed by a local file in the format of the Have I Been Pwned
+// corpus ordered by hash, where each line is the uppercase hex encoded SHA-1 hash and count separated by a colon.
+type FileBreachedPasswordSource struct {
+	path string
+}
+
+// Count returns the number of times the hash has been seen using a binary search of the file.
+func (s *FileBreachedPasswordSource) Count(hash string) (count int, err error) {
+	var file *os.File
+
+	if file, err = os.Open(s.path); err != nil {
+		return 0, fmt.Errorf("error opening breached password file: %w", err)
+	}
+
+	defer file.Close()
+
+	var info os.FileInfo
+
+	if info, err = file.Stat(); err != nil {
+		return 0, fmt.Errorf("error reading breached password file: %w", err)
+	}
+
+	var (
+		lo, hi     = int64(0), info.Size()
+		start, end int64
+		line       []byte
+	)
+
+	target := []byte(hash)
+
+	// The lo and hi offsets are always the start of a line, or the end of the file in the case of hi.
+	for lo < hi {
+		if start, end, line, err = breachedFileLineAt(file, lo+(hi-lo)/2, lo, hi); err != nil {
+			return 0, err
+		}
+
+		candidate, value, _ := bytes.Cut(line, []byte(":"))
+
+		switch bytes.Compare(bytes.ToUpper(candidate), target) {
+		case 0:
+			return breachedParseCount(value)
+		case -1:
+			lo = end
+		default:
+			hi = start
+		}
+	}
+
+	return 0, nil
+}
+
+const breachedFileMaxLineLength = 256
+
+const (
+	breachedResultBreached    = "breached"
+	breachedResultNotBreached = "not_breached"
+	breachedResultError       = "error"
+)
+
+// breachedFileLineAt returns the line which contains the offset, the start of the line, and the start of the next line.
+func breachedFileLineAt(r io.ReaderAt, offset, lo, hi int64) (start, end int64, line []byte, err error) {
+	bufStart := offset - breachedFileMaxLineLength
+	if bufStart < lo {
+		bufStart = lo
+	}
+
+	bufEnd := offset + breachedFileMaxLineLength
+	if bufEnd > hi {
+		bufEnd = hi
+	}
+
+	buf := make([]byte, bufEnd-bufStart)
+
+	if _, err = r.ReadAt(buf, bufStart); err != nil && err != io.EOF {
+		return 0, 0, nil, fmt.Errorf("error reading breached password file: %w", err)
+	}
+
+	rel := offset - bufStart
+
+	i := bytes.LastIndexByte(buf[:rel], '\n')
+
+	if i == -1 && bufStart != lo {
+		return 0, 0, nil, fmt.Errorf("error reading breached password file: line at offset %d exceeds the maximum length", offset)
+	}
+
+	start = bufStart + int64(i) + 1
+
+	j := bytes.IndexByte(buf[rel:], '\n')
+
+	switch {
+	case j != -1:
+		end = offset + int64(j) + 1
+	case bufEnd == hi:
+		end = hi
+	default:
+		return 0, 0, nil, fmt.Errorf("error reading breached password file: line at offset %d exceeds the maximum length", offset)
+	}
+
+	return start, end, bytes.TrimRight(buf[start-bufStart:end-bufStart], "\r\n"), nil
+}
+
+// RangeBreachedPasswordSource is a BreachedPasswordSource backed by the k-anonymity range API of Have I Been Pwned or
+// a mirror of it. Only the first 5 characters of the hash are sent to the API.
+type RangeBreachedPasswordSource struct {
+	url    url.URL
+	client *http.Client
+}
+
+// Count returns the number of times the hash has been seen using the range API.
+func (s *RangeBreachedPasswordSource) Count(hash string) (count int, err error) {
+	prefix, suffix := hash[:5], []byte(hash[5:])
+
+	uri := s.url.JoinPath("range", prefix)
+
+	var req *http.Request
+
+	if req, err = http.NewRequest(http.MethodGet, uri.String(), nil); err != nil {
+		return 0, fmt.Errorf("error creating breached password range request: %w", err)
+	}
+
+	req.Header.Set("Add-Padding", "true")
+
+	var resp *http.Response
+
+	if resp, err = s.client.Do(req); err != nil {
+		return 0, fmt.Errorf("error performing breached password range request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("error performing breached password range request: status code %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		candidate, value, _ := bytes.Cut(bytes.TrimSpace(scanner.Bytes()), []byte(":"))
+
+		if bytes.EqualFold(candidate, suffix) {
+			return breachedParseCount(value)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading breached password range response: %w", err)
+	}
+
+	return 0, nil
+}
+
+func breachedParseCount(value []byte) (count int, err error) {
+	if count, err = strconv.Atoi(string(bytes.TrimSpace(value))); err != nil {
+		return 0, fmt.Errorf("error parsing breached password count: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,170 @@
+package middlewares
+
+import (
+	"crypto/sha1" //nolint:gosec // Required to generate test data for the breached password corpus.
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func testBreachedHash(password string) string {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // Required to generate test data for the breached password corpus.
+
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestFileBreachedPasswordSource(t *testing.T) {
+	var lines []string
+
+	for i := 0; i < 500; i++ {
+		lines = append(lines, fmt.Sprintf("%s:%d", testBreachedHash(fmt.Sprintf("password%d", i)), i+1))
+	}
+
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")), 0600))
+
+	source := &FileBreachedPasswordSource{path: path}
+
+	for i := 0; i < 500; i++ {
+		count, err := source.Count(testBreachedHash(fmt.Sprintf("password%d", i)))
+
+		require.NoError(t, err)
+		assert.Equal(t, i+1, count)
+	}
+
+	count, err := source.Count(testBreachedHash("not-in-the-corpus"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	source = &FileBreachedPasswordSource{path: filepath.Join(t.TempDir(), "missing.txt")}
+
+	_, err = source.Count(testBreachedHash("password"))
+
+	assert.Regexp(t, `^error opening breached password file: `, err.Error())
+}
+
+func TestRangeBreachedPasswordSource(t *testing.T) {
+	hash := testBreachedHash("password")
+
+	var paths []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+
+		_, _ = fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n%s:3861493\r\n00D4F6E8FA6EECAD2A3AA415EEC418D38EC:0\r\n", hash[5:])
+	}))
+
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+
+	require.NoError(t, err)
+
+	provider := NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{
+		Breached: schema.PasswordPolicyBreachedParams{
+			Enabled:   true,
+			URL:       *uri,
+			Timeout:   time.Second,
+			Threshold: 1,
+		},
+	}, nil)
+
+	assert.ErrorIs(t, provider.Check("password"), ErrPasswordPolicyBreached)
+	assert.NoError(t, provider.Check("not-in-the-corpus"))
+	assert.Equal(t, []string{"/range/" + hash[:5], "/range/" + testBreachedHash("not-in-the-corpus")[:5]}, paths)
+}
+
+func TestRangeBreachedPasswordSourceShouldUseTrustedCertificates(t *testing.T) {
+	hash := testBreachedHash("password")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s:3861493\r\n", hash[5:])
+	}))
+
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+
+	require.NoError(t, err)
+
+	config := schema.PasswordPolicyBreachedParams{
+		URL:     *uri,
+		Timeout: time.Second,
+	}
+
+	_, err = NewBreachedPasswordSource(config, x509.NewCertPool()).Count(hash)
+
+	assert.Regexp(t, `^error performing breached password range request: .*certificate`, err.Error())
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	count, err := NewBreachedPasswordSource(config, pool).Count(hash)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3861493, count)
+}
+
+func TestBreachedPasswordPolicyProvider(t *testing.T) {
+	source := &testBreachedPasswordSource{counts: map[string]int{
+		testBreachedHash("abc123ABC"):  10,
+		testBreachedHash("abc123ABCD"): 1,
+	}}
+
+	recorder := &testBreachedPasswordRecorder{results: map[string]int{}}
+
+	provider := &BreachedPasswordPolicyProvider{
+		policy:    &StandardPasswordPolicyProvider{min: 8},
+		source:    source,
+		threshold: 5,
+	}
+
+	provider.SetMetricsRecorder(recorder)
+
+	assert.EqualError(t, provider.Check("abc"), "the supplied password does not met the security policy")
+	assert.ErrorIs(t, provider.Check("abc123ABC"), ErrPasswordPolicyBreached)
+	assert.NoError(t, provider.Check("abc123ABCD"))
+
+	source.err = fmt.Errorf("unavailable")
+
+	assert.NoError(t, provider.Check("abc123ABC"))
+
+	assert.Equal(t, map[string]int{"breached": 1, "not_breached": 1, "error": 1}, recorder.results)
+}
+
+type testBreachedPasswordRecorder struct {
+	results map[string]int
+}
+
+func (r *testBreachedPasswordRecorder) RecordPasswordPolicyBreachedCheck(result string) {
+	r.results[result]++
+}
+
+type testBreachedPasswordSource struct {
+	counts map[string]int
+	err    error
+}
+
+func (s *testBreachedPasswordSource) Count(hash string) (count int, err error) {
+	return s.counts[hash], s.err
+}
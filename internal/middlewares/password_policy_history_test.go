@@ -0,0 +1,79 @@
+package middlewares_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestShouldNotUsePasswordHistoryWhenDisabled(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	provider, err := middlewares.NewPasswordHistoryProvider(schema.PasswordPolicyHistoryParams{}, schema.DefaultCIPasswordConfig, mock.StorageMock)
+
+	require.NoError(t, err)
+
+	assert.NoError(t, provider.CheckHistory(mock.Ctx, "john", "password"))
+	assert.NoError(t, provider.SaveHistory(mock.Ctx, "john", "password"))
+}
+
+func TestShouldCheckAndSavePasswordHistory(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	provider, err := middlewares.NewPasswordHistoryProvider(schema.PasswordPolicyHistoryParams{Enabled: true, Count: 3}, schema.DefaultCIPasswordConfig, mock.StorageMock)
+
+	require.NoError(t, err)
+
+	var digest string
+
+	mock.StorageMock.EXPECT().
+		SaveUserPasswordHistory(mock.Ctx, "john", gomock.Any(), 3).
+		DoAndReturn(func(_ any, _, encoded string, _ int) error {
+			digest = encoded
+
+			return nil
+		})
+
+	require.NoError(t, provider.SaveHistory(mock.Ctx, "john", "password"))
+	assert.Regexp(t, `^\$argon2id\$`, digest)
+
+	mock.StorageMock.EXPECT().LoadUserPasswordHistory(mock.Ctx, "john", 3).Return([]string{digest}, nil).Times(2)
+
+	assert.ErrorIs(t, provider.CheckHistory(mock.Ctx, "john", "password"), middlewares.ErrPasswordPolicyHistoryReused)
+	assert.NoError(t, provider.CheckHistory(mock.Ctx, "john", "another-password"))
+
+	mock.StorageMock.EXPECT().LoadUserPasswordHistory(mock.Ctx, "john", 3).Return(nil, errors.New("failed"))
+
+	assert.EqualError(t, provider.CheckHistory(mock.Ctx, "john", "password"), "failed")
+}
+
+func TestShouldSavePasswordHistoryWithConfiguredAlgorithm(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	config := schema.DefaultCIPasswordConfig
+	config.Algorithm = "sha2crypt"
+
+	provider, err := middlewares.NewPasswordHistoryProvider(schema.PasswordPolicyHistoryParams{Enabled: true, Count: 3}, config, mock.StorageMock)
+
+	require.NoError(t, err)
+
+	mock.StorageMock.EXPECT().
+		SaveUserPasswordHistory(mock.Ctx, "john", gomock.Any(), 3).
+		DoAndReturn(func(_ any, _, encoded string, _ int) error {
+			assert.Regexp(t, `^\$6\$rounds=`, encoded)
+
+			return nil
+		})
+
+	require.NoError(t, provider.SaveHistory(mock.Ctx, "john", "password"))
+}
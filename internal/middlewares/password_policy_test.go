@@ -60,7 +60,7 @@ func TestNewPasswordPolicyProvider(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			actual := NewPasswordPolicyProvider(tc.have)
+			actual := NewPasswordPolicyProvider(tc.have, nil)
 			assert.Equal(t, tc.expected, actual)
 		})
 	}
@@ -135,7 +135,7 @@ func TestPasswordPolicyProvider_Validate(t *testing.T) {
 		t.Run(tc.desc, func(t *testing.T) {
 			require.Equal(t, len(tc.have), len(tc.expected))
 			for i := 0; i < len(tc.have); i++ {
-				provider := NewPasswordPolicyProvider(tc.config)
+				provider := NewPasswordPolicyProvider(tc.config, nil)
 				t.Run(tc.have[i], func(t *testing.T) {
 					assert.Equal(t, tc.expected[i], provider.Check(tc.have[i]))
 				})
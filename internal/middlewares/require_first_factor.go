@@ -7,8 +7,25 @@ import (
 // Require1FA check if user has enough permissions to execute the next handler.
 func Require1FA(next RequestHandler) RequestHandler {
 	return func(ctx *AutheliaCtx) {
-		if ctx.GetSession().AuthenticationLevel < authentication.OneFactor {
+		userSession := ctx.GetSession()
+
+		if userSession.AuthenticationLevel < authentication.OneFactor {
+			ctx.ReplyForbidden()
+			return
+		}
+
+		switch revoked, err := ctx.IsSessionRevoked(&userSession); {
+		case err != nil:
+			ctx.Logger.Errorf("Unable to check if the session of user '%s' has been revoked: %v", userSession.Username, err)
+		case revoked:
+			ctx.Logger.Infof("Session of user '%s' has been revoked, destroying the session", userSession.Username)
+
+			if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx, ctx.GetSessionHost()); err != nil {
+				ctx.Logger.Errorf("Unable to destroy the revoked session of user '%s': %v", userSession.Username, err)
+			}
+
 			ctx.ReplyForbidden()
+
 			return
 		}
 
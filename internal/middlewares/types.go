@@ -44,6 +44,7 @@ type Providers struct {
 	Templates       *templates.Provider
 	TOTP            totp.Provider
 	PasswordPolicy  PasswordPolicyProvider
+	PasswordHistory PasswordHistoryProvider
 }
 
 // RequestHandler represents an Authelia request handler.
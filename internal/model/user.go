@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// User represents a user managed by the storage authentication backend.
+type User struct {
+	ID          int       `db:"id"`
+	CreatedAt   time.Time `db:"created_at"`
+	Username    string    `db:"username"`
+	Password    string    `db:"password"`
+	DisplayName string    `db:"display_name"`
+	Email       string    `db:"email"`
+	Disabled    bool      `db:"disabled"`
+
+	Groups []string `db:"-"`
+}
@@ -1,5 +1,10 @@
 package oidc
 
+import (
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
 // AuthenticationMethodsReferences holds AMR information.
 type AuthenticationMethodsReferences struct {
 	UsernameAndPassword  bool
@@ -25,6 +30,39 @@ func (r AuthenticationMethodsReferences) MultiFactorAuthentication() bool {
 	return r.FactorKnowledge() && r.FactorPossession()
 }
 
+// SecondFactorMethods returns the second factor methods which were used to authenticate.
+func (r AuthenticationMethodsReferences) SecondFactorMethods() (methods []string) {
+	if r.TOTP {
+		methods = append(methods, model.SecondFactorMethodTOTP)
+	}
+
+	if r.Webauthn {
+		methods = append(methods, model.SecondFactorMethodWebauthn)
+	}
+
+	if r.Duo {
+		methods = append(methods, model.SecondFactorMethodDuo)
+	}
+
+	return methods
+}
+
+// IsSecondFactorMethodSatisfied returns true if one of the provided second factor methods was used to authenticate or
+// if no methods are provided.
+func (r AuthenticationMethodsReferences) IsSecondFactorMethodSatisfied(methods []string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+
+	for _, method := range r.SecondFactorMethods() {
+		if utils.IsStringInSlice(method, methods) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ChannelBrowser returns true if a browser was used to authenticate.
 func (r AuthenticationMethodsReferences) ChannelBrowser() bool {
 	return r.UsernameAndPassword || r.TOTP || r.Webauthn
@@ -187,3 +187,17 @@ func TestAuthenticationMethodsReferences(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthenticationMethodsReferences_IsSecondFactorMethodSatisfied(t *testing.T) {
+	amr := AuthenticationMethodsReferences{UsernameAndPassword: true, TOTP: true}
+
+	assert.Equal(t, []string{"totp"}, amr.SecondFactorMethods())
+	assert.True(t, amr.IsSecondFactorMethodSatisfied(nil))
+	assert.True(t, amr.IsSecondFactorMethodSatisfied([]string{"webauthn", "totp"}))
+	assert.False(t, amr.IsSecondFactorMethodSatisfied([]string{"webauthn", "mobile_push"}))
+
+	amr = AuthenticationMethodsReferences{UsernameAndPassword: true}
+
+	assert.Nil(t, amr.SecondFactorMethods())
+	assert.False(t, amr.IsSecondFactorMethodSatisfied([]string{"totp"}))
+}
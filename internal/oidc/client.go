@@ -27,7 +27,8 @@ func NewClient(config schema.OpenIDConnectClientConfiguration) (client *Client)
 
 		UserinfoSigningAlgorithm: config.UserinfoSigningAlgorithm,
 
-		Policy: authorization.NewLevel(config.Policy),
+		Policy:              authorization.NewLevel(config.Policy),
+		SecondFactorMethods: config.SecondFactorMethods,
 
 		Consent: NewClientConsent(config.ConsentMode, config.ConsentPreConfiguredDuration),
 	}
@@ -48,6 +49,16 @@ func (c *Client) IsAuthenticationLevelSufficient(level authentication.Level) boo
 	return authorization.IsAuthLevelSufficient(level, c.Policy)
 }
 
+// IsAuthenticationSufficient returns if the provided authentication.Level and AuthenticationMethodsReferences are
+// sufficient for the client of the AutheliaClient.
+func (c *Client) IsAuthenticationSufficient(level authentication.Level, amr AuthenticationMethodsReferences) bool {
+	if !c.IsAuthenticationLevelSufficient(level) {
+		return false
+	}
+
+	return c.Policy != authorization.TwoFactor || amr.IsSecondFactorMethodSatisfied(c.SecondFactorMethods)
+}
+
 // GetID returns the ID.
 func (c *Client) GetID() string {
 	return c.ID
@@ -68,6 +68,20 @@ func TestIsAuthenticationLevelSufficient(t *testing.T) {
 	assert.False(t, c.IsAuthenticationLevelSufficient(authentication.TwoFactor))
 }
 
+func TestIsAuthenticationSufficient(t *testing.T) {
+	c := Client{Policy: authorization.TwoFactor}
+
+	totp := AuthenticationMethodsReferences{UsernameAndPassword: true, TOTP: true}
+
+	assert.False(t, c.IsAuthenticationSufficient(authentication.OneFactor, totp))
+	assert.True(t, c.IsAuthenticationSufficient(authentication.TwoFactor, totp))
+
+	c.SecondFactorMethods = []string{"webauthn"}
+
+	assert.False(t, c.IsAuthenticationSufficient(authentication.TwoFactor, totp))
+	assert.True(t, c.IsAuthenticationSufficient(authentication.TwoFactor, AuthenticationMethodsReferences{UsernameAndPassword: true, Webauthn: true}))
+}
+
 func TestClient_GetConsentResponseBody(t *testing.T) {
 	c := Client{}
 
@@ -116,7 +116,8 @@ type Client struct {
 
 	UserinfoSigningAlgorithm string
 
-	Policy authorization.Level
+	Policy              authorization.Level
+	SecondFactorMethods []string
 
 	Consent ClientConsent
 }
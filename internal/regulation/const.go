@@ -17,4 +17,7 @@ const (
 
 	// AuthTypeDuo is the string representing an auth log for second-factor authentication via DUO.
 	AuthTypeDuo = "Duo"
+
+	// AuthTypeAuthzDecide is the string representing an auth log for the authentication of a policy decision client.
+	AuthTypeAuthzDecide = "AuthzDecide"
 )
@@ -158,6 +158,10 @@ func handleRouter(config schema.Configuration, providers middlewares.Providers)
 
 	r.POST("/api/checks/safe-redirection", middlewareAPI(handlers.CheckSafeRedirectionPOST))
 
+	if len(config.Server.Endpoints.AuthzDecide.Clients) != 0 {
+		r.POST("/api/authz/decide", middlewareAPI(handlers.AuthzDecidePOST(middlewares.TimingAttackDelay(10, 250, 85, time.Second, true))))
+	}
+
 	delayFunc := middlewares.TimingAttackDelay(10, 250, 85, time.Second, true)
 
 	r.POST("/api/firstfactor", middlewareAPI(handlers.FirstFactorPOST(delayFunc)))
@@ -176,6 +180,12 @@ func handleRouter(config schema.Configuration, providers middlewares.Providers)
 	r.GET("/api/user/info", middleware1FA(handlers.UserInfoGET))
 	r.POST("/api/user/info", middleware1FA(handlers.UserInfoPOST))
 	r.POST("/api/user/info/2fa_method", middleware1FA(handlers.MethodPreferencePOST))
+	r.GET("/api/user/sessions", middleware1FA(handlers.UserSessionsGET))
+	r.DELETE("/api/user/sessions", middleware1FA(handlers.UserSessionsDELETE))
+
+	if !config.AuthenticationBackend.PasswordChange.Disable {
+		r.POST("/api/user/password", middleware1FA(handlers.UserPasswordPOST))
+	}
 
 	if !config.TOTP.Disable {
 		// TOTP related endpoints.
@@ -121,7 +121,7 @@ func NewTemplatedFileOptions(config *schema.Configuration) (opts *TemplatedFileO
 	opts = &TemplatedFileOptions{
 		AssetPath:              config.Server.AssetPath,
 		DuoSelfEnrollment:      f,
-		RememberMe:             strconv.FormatBool(config.Session.RememberMeDuration != schema.RememberMeDisabled),
+		RememberMe:             strconv.FormatBool(isRememberMeEnabled(config.Session)),
 		ResetPassword:          strconv.FormatBool(!config.AuthenticationBackend.PasswordReset.Disable),
 		ResetPasswordCustomURL: config.AuthenticationBackend.PasswordReset.CustomURL.String(),
 		Theme:                  config.Theme,
@@ -134,6 +134,21 @@ func NewTemplatedFileOptions(config *schema.Configuration) (opts *TemplatedFileO
 	return opts
 }
 
+// isRememberMeEnabled returns true if the remember me option is enabled for any of the protected cookie domains.
+func isRememberMeEnabled(config schema.SessionConfiguration) bool {
+	if len(config.Cookies) == 0 {
+		return config.RememberMeDuration != schema.RememberMeDisabled
+	}
+
+	for _, cookie := range config.Cookies {
+		if cookie.RememberMeDuration != schema.RememberMeDisabled {
+			return true
+		}
+	}
+
+	return false
+}
+
 // TemplatedFileOptions is a struct which is used for many templated files.
 type TemplatedFileOptions struct {
 	AssetPath              string
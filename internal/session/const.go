@@ -1,7 +1,10 @@
 package session
 
 import (
+	"errors"
 	"time"
+
+	"github.com/valyala/fasthttp"
 )
 
 const (
@@ -11,7 +14,28 @@ const (
 	testUsername   = "john"
 )
 
+var (
+	headerXForwardedFor = []byte(fasthttp.HeaderXForwardedFor)
+)
+
+// RevocationCheckInterval is the minimum interval between checks of the storage for revocations of a session.
+const RevocationCheckInterval = time.Minute
+
+// ErrUserSessionNotFound is returned when revoking a session which is not in the index of the sessions of the user.
+var ErrUserSessionNotFound = errors.New("the session was not found")
+
 const (
-	userSessionStorerKey = "UserSession"
-	randomSessionChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_!#$%^*"
+	userSessionStorerKey      = "UserSession"
+	userSessionIndexStorerKey = "UserSessionIndex"
+	randomSessionChars        = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_!#$%^*"
+
+	// userSessionIndexKeyPrefix is the prefix of the key of the index of the sessions of a user, the key can't collide
+	// with a session id as the prefix contains a character which is not in randomSessionChars.
+	userSessionIndexKeyPrefix = "user-sessions:"
+
+	// userSessionIndexActivityInterval is the minimum interval between updates of the activity recorded in the index.
+	userSessionIndexActivityInterval = time.Minute
+
+	// userSessionIndexLockStripes is the number of locks shared by the users to serialize the updates of their indexes.
+	userSessionIndexLockStripes = 64
 )
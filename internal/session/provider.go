@@ -3,11 +3,13 @@ package session
 import (
 	"crypto/x509"
 	"encoding/json"
+	"strings"
+	"sync"
 	"time"
 
 	fasthttpsession "github.com/fasthttp/session/v2"
 	"github.com/fasthttp/session/v2/providers/memory"
-	"github.com/fasthttp/session/v2/providers/redis"
+	goredis "github.com/go-redis/redis/v8"
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -16,56 +18,128 @@ import (
 
 // Provider a session provider.
 type Provider struct {
+	sessions []*Session
+
+	provider        fasthttpsession.Provider
+	serializer      *EncryptingSerializer
+	index           userSessionIndexStore
+	indexExpiration time.Duration
+
+	// indexLocks serializes the updates of the index of the sessions of a user, each user is assigned one of the locks
+	// by the hash of the username so the number of locks is bounded.
+	indexLocks [userSessionIndexLockStripes]sync.Mutex
+}
+
+// Session is the session store of a single protected cookie domain.
+type Session struct {
+	Config schema.SessionCookieConfiguration
+
 	sessionHolder *fasthttpsession.Session
-	RememberMe    time.Duration
-	Inactivity    time.Duration
 }
 
 // NewProvider instantiate a session provider given a configuration.
 func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *Provider {
 	c := NewProviderConfig(config, certPool)
 
-	provider := new(Provider)
-	provider.sessionHolder = fasthttpsession.New(c.config)
-
 	logger := logging.Logger()
 
-	provider.Inactivity, provider.RememberMe = config.Inactivity, config.RememberMeDuration
-
 	var (
 		providerImpl fasthttpsession.Provider
+		index        userSessionIndexStore
+		client       goredis.UniversalClient
 		err          error
 	)
 
 	switch {
 	case c.redisConfig != nil:
-		providerImpl, err = redis.New(*c.redisConfig)
-		if err != nil {
+		if client, err = newRedisClient(c.redisConfig); err != nil {
 			logger.Fatal(err)
 		}
+
+		providerImpl = &redisProvider{client: client, keyPrefix: c.redisConfig.KeyPrefix}
+		index = newRedisUserSessionIndexStore(client, c.redisConfig.KeyPrefix)
 	case c.redisSentinelConfig != nil:
-		providerImpl, err = redis.NewFailoverCluster(*c.redisSentinelConfig)
-		if err != nil {
+		if client, err = newRedisSentinelClient(c.redisSentinelConfig); err != nil {
 			logger.Fatal(err)
 		}
+
+		providerImpl = &redisProvider{client: client, keyPrefix: c.redisSentinelConfig.KeyPrefix}
+		index = newRedisUserSessionIndexStore(client, c.redisSentinelConfig.KeyPrefix)
 	default:
 		providerImpl, err = memory.New(memory.Config{})
 		if err != nil {
 			logger.Fatal(err)
 		}
+
+		index = newMemoryUserSessionIndexStore()
 	}
 
-	err = provider.sessionHolder.SetProvider(providerImpl)
-	if err != nil {
-		logger.Fatal(err)
+	provider := &Provider{
+		provider:   providerImpl,
+		serializer: c.serializer,
+		index:      index,
+	}
+
+	for _, cookie := range newCookieConfigurations(config) {
+		sessionConfig := c.config
+
+		setCookieConfiguration(&sessionConfig, cookie)
+
+		session := &Session{
+			Config:        cookie,
+			sessionHolder: fasthttpsession.New(sessionConfig),
+		}
+
+		if err = session.sessionHolder.SetProvider(providerImpl); err != nil {
+			logger.Fatal(err)
+		}
+
+		provider.sessions = append(provider.sessions, session)
+
+		for _, expiration := range []time.Duration{cookie.Expiration, cookie.RememberMeDuration} {
+			if expiration > provider.indexExpiration {
+				provider.indexExpiration = expiration
+			}
+		}
 	}
 
 	return provider
 }
 
+// Get returns the Session of the cookie domain which the host is equal to or is a subdomain of. It returns nil if the
+// host isn't under any of the protected cookie domains.
+func (p *Provider) Get(host string) *Session {
+	host = strings.ToLower(host)
+
+	for _, session := range p.sessions {
+		domain := strings.TrimPrefix(session.Config.Domain, ".")
+
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return session
+		}
+	}
+
+	return nil
+}
+
+// GetCookieConfiguration returns the cookie configuration of the session of the host.
+func (p *Provider) GetCookieConfiguration(host string) schema.SessionCookieConfiguration {
+	return p.get(host).Config
+}
+
+// get returns the Session of the cookie domain of the host, the first cookie domain is used when the host isn't under
+// any of the protected cookie domains.
+func (p *Provider) get(host string) *Session {
+	if session := p.Get(host); session != nil {
+		return session
+	}
+
+	return p.sessions[0]
+}
+
 // GetSession return the user session from a request.
-func (p *Provider) GetSession(ctx *fasthttp.RequestCtx) (UserSession, error) {
-	store, err := p.sessionHolder.Get(ctx)
+func (p *Provider) GetSession(ctx *fasthttp.RequestCtx, host string) (UserSession, error) {
+	store, err := p.get(host).sessionHolder.Get(ctx)
 
 	if err != nil {
 		return NewDefaultUserSession(), err
@@ -93,14 +167,24 @@ func (p *Provider) GetSession(ctx *fasthttp.RequestCtx) (UserSession, error) {
 	return userSession, nil
 }
 
-// SaveSession save the user session.
-func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession) error {
-	store, err := p.sessionHolder.Get(ctx)
+// SaveSession save the user session. The sessions of authenticated users are also recorded in the index of the
+// sessions of the user, a failure to update the index is logged as the session itself was saved.
+func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, host string, userSession UserSession) error {
+	session := p.get(host)
+
+	store, err := session.sessionHolder.Get(ctx)
 
 	if err != nil {
 		return err
 	}
 
+	// Copy the session id as the store is reset when it's saved.
+	id := append([]byte(nil), store.GetSessionID()...)
+
+	now := time.Now()
+
+	indexed := !userSession.IsAnonymous() && setUserSessionIndexState(store, userSessionPublicID(id), userSession.AuthenticationLevel, now)
+
 	userSessionJSON, err := json.Marshal(userSession)
 
 	if err != nil {
@@ -109,30 +193,40 @@ func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession
 
 	store.Set(userSessionStorerKey, userSessionJSON)
 
-	err = p.sessionHolder.Save(ctx, store)
+	err = session.sessionHolder.Save(ctx, store)
 
 	if err != nil {
 		return err
 	}
 
+	if !indexed {
+		return nil
+	}
+
+	if err = p.indexUserSession(ctx, session, id, userSession, now); err != nil {
+		logging.Logger().WithError(err).Errorf("Unable to update the index of the sessions of user '%s'", userSession.Username)
+	}
+
 	return nil
 }
 
 // RegenerateSession regenerate a session ID.
-func (p *Provider) RegenerateSession(ctx *fasthttp.RequestCtx) error {
-	err := p.sessionHolder.Regenerate(ctx)
+func (p *Provider) RegenerateSession(ctx *fasthttp.RequestCtx, host string) error {
+	err := p.get(host).sessionHolder.Regenerate(ctx)
 
 	return err
 }
 
 // DestroySession destroy a session ID and delete the cookie.
-func (p *Provider) DestroySession(ctx *fasthttp.RequestCtx) error {
-	return p.sessionHolder.Destroy(ctx)
+func (p *Provider) DestroySession(ctx *fasthttp.RequestCtx, host string) error {
+	return p.get(host).sessionHolder.Destroy(ctx)
 }
 
 // UpdateExpiration update the expiration of the cookie and session.
-func (p *Provider) UpdateExpiration(ctx *fasthttp.RequestCtx, expiration time.Duration) error {
-	store, err := p.sessionHolder.Get(ctx)
+func (p *Provider) UpdateExpiration(ctx *fasthttp.RequestCtx, host string, expiration time.Duration) error {
+	holder := p.get(host).sessionHolder
+
+	store, err := holder.Get(ctx)
 
 	if err != nil {
 		return err
@@ -144,12 +238,12 @@ func (p *Provider) UpdateExpiration(ctx *fasthttp.RequestCtx, expiration time.Du
 		return err
 	}
 
-	return p.sessionHolder.Save(ctx, store)
+	return holder.Save(ctx, store)
 }
 
 // GetExpiration get the expiration of the current session.
-func (p *Provider) GetExpiration(ctx *fasthttp.RequestCtx) (time.Duration, error) {
-	store, err := p.sessionHolder.Get(ctx)
+func (p *Provider) GetExpiration(ctx *fasthttp.RequestCtx, host string) (time.Duration, error) {
+	store, err := p.get(host).sessionHolder.Get(ctx)
 
 	if err != nil {
 		return time.Duration(0), err
@@ -33,30 +33,14 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 		return bytes
 	}
 
-	// Override the cookie name.
-	c.CookieName = config.Name
-
-	// Set the cookie to the given domain.
-	c.Domain = config.Domain
+	cookies := newCookieConfigurations(config)
 
-	// Set the cookie SameSite option.
-	switch config.SameSite {
-	case "strict":
-		c.CookieSameSite = fasthttp.CookieSameSiteStrictMode
-	case "none":
-		c.CookieSameSite = fasthttp.CookieSameSiteNoneMode
-	case "lax":
-		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
-	default:
-		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
-	}
+	// Set the cookie options of the default cookie domain.
+	setCookieConfiguration(&c, cookies[0])
 
 	// Only serve the header over HTTPS.
 	c.Secure = true
 
-	// Ignore the error as it will be handled by validator.
-	c.Expiration = config.Expiration
-
 	c.IsSecureFunc = func(*fasthttp.RequestCtx) bool {
 		return true
 	}
@@ -67,10 +51,12 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 
 	var providerName string
 
+	var serializer *EncryptingSerializer
+
 	// If redis configuration is provided, then use the redis provider.
 	switch {
 	case config.Redis != nil:
-		serializer := NewEncryptingSerializer(config.Secret)
+		serializer = NewEncryptingSerializer(config.Secret)
 
 		var tlsConfig *tls.Config
 
@@ -148,6 +134,50 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 		c,
 		redisConfig,
 		redisSentinelConfig,
+		serializer,
 		providerName,
 	}
 }
+
+// newCookieConfigurations returns the cookie configurations of the protected domains. If no cookies are configured a
+// single cookie configuration is returned using the legacy domain options.
+func newCookieConfigurations(config schema.SessionConfiguration) []schema.SessionCookieConfiguration {
+	if len(config.Cookies) != 0 {
+		return config.Cookies
+	}
+
+	return []schema.SessionCookieConfiguration{
+		{
+			Domain:             config.Domain,
+			Name:               config.Name,
+			SameSite:           config.SameSite,
+			Expiration:         config.Expiration,
+			Inactivity:         config.Inactivity,
+			RememberMeDuration: config.RememberMeDuration,
+		},
+	}
+}
+
+// setCookieConfiguration sets the cookie options of a session configuration to the values of a cookie configuration.
+func setCookieConfiguration(c *session.Config, cookie schema.SessionCookieConfiguration) {
+	// Override the cookie name.
+	c.CookieName = cookie.Name
+
+	// Set the cookie to the given domain.
+	c.Domain = cookie.Domain
+
+	// Set the cookie SameSite option.
+	switch cookie.SameSite {
+	case "strict":
+		c.CookieSameSite = fasthttp.CookieSameSiteStrictMode
+	case "none":
+		c.CookieSameSite = fasthttp.CookieSameSiteNoneMode
+	case "lax":
+		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
+	default:
+		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
+	}
+
+	// Ignore the error as it will be handled by validator.
+	c.Expiration = cookie.Expiration
+}
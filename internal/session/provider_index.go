@@ -0,0 +1,274 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	fasthttpsession "github.com/fasthttp/session/v2"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// GetUserSessionID returns the identifier of the session of the request as listed by ListUserSessions.
+func (p *Provider) GetUserSessionID(ctx *fasthttp.RequestCtx, host string) (id string, err error) {
+	store, err := p.get(host).sessionHolder.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return userSessionPublicID(store.GetSessionID()), nil
+}
+
+// ListUserSessions returns the metadata of the active sessions of a user ordered by the most recent activity. The
+// sessions which no longer exist in the session store are removed from the index.
+func (p *Provider) ListUserSessions(username string) (sessions []Metadata, err error) {
+	defer p.lockUserSessionIndex(username)()
+
+	var entries map[string]*userSessionIndexEntry
+
+	if entries, err = p.loadUserSessionIndex(username); err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+
+	for id, entry := range entries {
+		var data []byte
+
+		if data, err = p.provider.Get([]byte(entry.SessionID)); err != nil {
+			return nil, err
+		}
+
+		if len(data) == 0 {
+			pruned = append(pruned, id)
+
+			continue
+		}
+
+		sessions = append(sessions, entry.Metadata)
+	}
+
+	if err = p.index.Delete(username, pruned...); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActivityAt.After(sessions[j].LastActivityAt)
+	})
+
+	return sessions, nil
+}
+
+// RevokeUserSession destroys the session of a user with the identifier as listed by ListUserSessions.
+func (p *Provider) RevokeUserSession(username, id string) (err error) {
+	defer p.lockUserSessionIndex(username)()
+
+	var entry *userSessionIndexEntry
+
+	if entry, err = p.loadUserSessionIndexEntry(username, id); err != nil {
+		return err
+	}
+
+	if entry == nil {
+		return ErrUserSessionNotFound
+	}
+
+	if err = p.provider.Destroy([]byte(entry.SessionID)); err != nil {
+		return err
+	}
+
+	return p.index.Delete(username, id)
+}
+
+// RevokeUserSessions destroys all sessions of a user except the session with the identifier provided as except, which
+// may be empty. It returns the number of sessions destroyed.
+func (p *Provider) RevokeUserSessions(username, except string) (revoked int, err error) {
+	defer p.lockUserSessionIndex(username)()
+
+	var entries map[string]*userSessionIndexEntry
+
+	if entries, err = p.loadUserSessionIndex(username); err != nil {
+		return 0, err
+	}
+
+	for id, entry := range entries {
+		if id == except {
+			continue
+		}
+
+		if err = p.provider.Destroy([]byte(entry.SessionID)); err != nil {
+			return revoked, err
+		}
+
+		if err = p.index.Delete(username, id); err != nil {
+			return revoked, err
+		}
+
+		revoked++
+	}
+
+	return revoked, nil
+}
+
+// indexUserSession records the metadata of the session in the index of the sessions of the user. The caller is
+// responsible for only indexing the session when it's new to the index, the authentication level changed, or the
+// recorded activity is older than the userSessionIndexActivityInterval.
+func (p *Provider) indexUserSession(ctx *fasthttp.RequestCtx, session *Session, sessionID []byte, userSession UserSession, now time.Time) (err error) {
+	defer p.lockUserSessionIndex(userSession.Username)()
+
+	id := userSessionPublicID(sessionID)
+
+	var entry *userSessionIndexEntry
+
+	if entry, err = p.loadUserSessionIndexEntry(userSession.Username, id); err != nil {
+		return err
+	}
+
+	if entry == nil {
+		entry = &userSessionIndexEntry{
+			SessionID: string(sessionID),
+			Metadata: Metadata{
+				ID:        id,
+				Domain:    session.Config.Domain,
+				CreatedAt: now,
+			},
+		}
+
+		if userSession.FirstFactorAuthnTimestamp != 0 {
+			entry.CreatedAt = time.Unix(userSession.FirstFactorAuthnTimestamp, 0)
+		}
+	}
+
+	entry.IP, entry.UserAgent = requestRemoteIP(ctx), string(ctx.UserAgent())
+	entry.LastActivityAt, entry.AuthenticationLevel = now, userSession.AuthenticationLevel
+
+	return p.saveUserSessionIndexEntry(userSession.Username, entry)
+}
+
+// setUserSessionIndexState returns true if the session has to be recorded in the index of the sessions of the user,
+// i.e. the session is new to the index, the authentication level changed, or the activity was recorded more than the
+// userSessionIndexActivityInterval ago, in which case the new state is set in the store.
+func setUserSessionIndexState(store *fasthttpsession.Store, id string, level authentication.Level, now time.Time) (required bool) {
+	var state userSessionIndexState
+
+	if data, ok := store.Get(userSessionIndexStorerKey).([]byte); ok && json.Unmarshal(data, &state) == nil {
+		if state.ID == id && state.AuthenticationLevel == level && now.Before(state.TTL) {
+			return false
+		}
+	}
+
+	state = userSessionIndexState{ID: id, AuthenticationLevel: level, TTL: now.Add(userSessionIndexActivityInterval)}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return true
+	}
+
+	store.Set(userSessionIndexStorerKey, data)
+
+	return true
+}
+
+// lockUserSessionIndex locks the index of the sessions of a user and returns the function which unlocks it.
+func (p *Provider) lockUserSessionIndex(username string) (unlock func()) {
+	hash := fnv.New32a()
+
+	_, _ = hash.Write([]byte(username))
+
+	mu := &p.indexLocks[hash.Sum32()%userSessionIndexLockStripes]
+
+	mu.Lock()
+
+	return mu.Unlock
+}
+
+func (p *Provider) loadUserSessionIndex(username string) (entries map[string]*userSessionIndexEntry, err error) {
+	var data map[string][]byte
+
+	if data, err = p.index.GetAll(username); err != nil {
+		return nil, err
+	}
+
+	entries = make(map[string]*userSessionIndexEntry, len(data))
+
+	for id, value := range data {
+		if entries[id], err = p.decodeUserSessionIndexEntry(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+func (p *Provider) loadUserSessionIndexEntry(username, id string) (entry *userSessionIndexEntry, err error) {
+	var data []byte
+
+	if data, err = p.index.Get(username, id); err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return p.decodeUserSessionIndexEntry(data)
+}
+
+func (p *Provider) saveUserSessionIndexEntry(username string, entry *userSessionIndexEntry) (err error) {
+	var data []byte
+
+	if data, err = json.Marshal(entry); err != nil {
+		return fmt.Errorf("unable to encode the session index entry: %w", err)
+	}
+
+	if p.serializer != nil {
+		if data, err = utils.Encrypt(data, &p.serializer.key); err != nil {
+			return fmt.Errorf("unable to encrypt the session index entry: %w", err)
+		}
+	}
+
+	return p.index.Set(username, entry.ID, data, p.indexExpiration)
+}
+
+func (p *Provider) decodeUserSessionIndexEntry(data []byte) (entry *userSessionIndexEntry, err error) {
+	if p.serializer != nil {
+		if data, err = utils.Decrypt(data, &p.serializer.key); err != nil {
+			return nil, fmt.Errorf("unable to decrypt the session index entry: %w", err)
+		}
+	}
+
+	entry = &userSessionIndexEntry{}
+
+	if err = json.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("unable to decode the session index entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// userSessionPublicID returns the identifier of a session which is safe to display, as opposed to the session id
+// itself which is the value of the session cookie.
+func userSessionPublicID(sessionID []byte) string {
+	sum := sha256.Sum256(sessionID)
+
+	return hex.EncodeToString(sum[:16])
+}
+
+// requestRemoteIP returns the remote IP of the request taking the X-Forwarded-For header into account if provided.
+func requestRemoteIP(ctx *fasthttp.RequestCtx) string {
+	if forwardedFor := ctx.Request.Header.PeekBytes(headerXForwardedFor); len(forwardedFor) != 0 {
+		ip, _, _ := strings.Cut(string(forwardedFor), ",")
+
+		return strings.TrimSpace(ip)
+	}
+
+	return ctx.RemoteIP().String()
+}
@@ -0,0 +1,193 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// userSessionIndexStore stores the index of the sessions of each user. The index of a user is a hash of the public
+// identifiers of the sessions to the encoded index entries which expires when it isn't updated for the expiration.
+type userSessionIndexStore interface {
+	Get(username, id string) (data []byte, err error)
+	GetAll(username string) (entries map[string][]byte, err error)
+	Set(username, id string, data []byte, expiration time.Duration) (err error)
+	Delete(username string, ids ...string) (err error)
+}
+
+func newRedisUserSessionIndexStore(client goredis.UniversalClient, keyPrefix string) *redisUserSessionIndexStore {
+	return &redisUserSessionIndexStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+// redisUserSessionIndexStore stores the index of the sessions of each user as a redis hash so each entry is updated
+// with HSET and HDEL instead of rewriting the whole index.
+type redisUserSessionIndexStore struct {
+	client    goredis.UniversalClient
+	keyPrefix string
+}
+
+// Get returns the entry of a session in the index of a user or nil if it doesn't exist.
+func (s *redisUserSessionIndexStore) Get(username, id string) (data []byte, err error) {
+	if data, err = s.client.HGet(context.Background(), s.key(username), id).Bytes(); err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetAll returns the entries of all sessions in the index of a user.
+func (s *redisUserSessionIndexStore) GetAll(username string) (entries map[string][]byte, err error) {
+	var values map[string]string
+
+	if values, err = s.client.HGetAll(context.Background(), s.key(username)).Result(); err != nil {
+		return nil, err
+	}
+
+	entries = make(map[string][]byte, len(values))
+
+	for id, value := range values {
+		entries[id] = []byte(value)
+	}
+
+	return entries, nil
+}
+
+// Set sets the entry of a session in the index of a user and resets the expiration of the index.
+func (s *redisUserSessionIndexStore) Set(username, id string, data []byte, expiration time.Duration) (err error) {
+	key := s.key(username)
+
+	_, err = s.client.TxPipelined(context.Background(), func(pipe goredis.Pipeliner) error {
+		pipe.HSet(context.Background(), key, id, data)
+		pipe.Expire(context.Background(), key, expiration)
+
+		return nil
+	})
+
+	return err
+}
+
+// Delete removes the entries of sessions from the index of a user.
+func (s *redisUserSessionIndexStore) Delete(username string, ids ...string) (err error) {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return s.client.HDel(context.Background(), s.key(username), ids...).Err()
+}
+
+func (s *redisUserSessionIndexStore) key(username string) string {
+	return s.keyPrefix + ":" + userSessionIndexKeyPrefix + username
+}
+
+func newMemoryUserSessionIndexStore() *memoryUserSessionIndexStore {
+	return &memoryUserSessionIndexStore{
+		indexes: map[string]*memoryUserSessionIndex{},
+	}
+}
+
+// memoryUserSessionIndexStore stores the index of the sessions of each user in memory.
+type memoryUserSessionIndexStore struct {
+	indexes map[string]*memoryUserSessionIndex
+
+	mu sync.Mutex
+}
+
+type memoryUserSessionIndex struct {
+	entries   map[string][]byte
+	expiresAt time.Time
+}
+
+// Get returns the entry of a session in the index of a user or nil if it doesn't exist.
+func (s *memoryUserSessionIndexStore) Get(username, id string) (data []byte, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if index := s.get(username); index != nil {
+		return index.entries[id], nil
+	}
+
+	return nil, nil
+}
+
+// GetAll returns the entries of all sessions in the index of a user.
+func (s *memoryUserSessionIndexStore) GetAll(username string) (entries map[string][]byte, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries = map[string][]byte{}
+
+	if index := s.get(username); index != nil {
+		for id, data := range index.entries {
+			entries[id] = data
+		}
+	}
+
+	return entries, nil
+}
+
+// Set sets the entry of a session in the index of a user and resets the expiration of the index.
+func (s *memoryUserSessionIndexStore) Set(username, id string, data []byte, expiration time.Duration) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := s.get(username)
+
+	if index == nil {
+		index = &memoryUserSessionIndex{entries: map[string][]byte{}}
+
+		s.indexes[username] = index
+	}
+
+	index.entries[id] = data
+	index.expiresAt = time.Now().Add(expiration)
+
+	return nil
+}
+
+// Delete removes the entries of sessions from the index of a user.
+func (s *memoryUserSessionIndexStore) Delete(username string, ids ...string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := s.get(username)
+
+	if index == nil {
+		return nil
+	}
+
+	for _, id := range ids {
+		delete(index.entries, id)
+	}
+
+	if len(index.entries) == 0 {
+		delete(s.indexes, username)
+	}
+
+	return nil
+}
+
+// get returns the index of a user, removing it if it has expired.
+func (s *memoryUserSessionIndexStore) get(username string) *memoryUserSessionIndex {
+	index, ok := s.indexes[username]
+	if !ok {
+		return nil
+	}
+
+	if time.Now().After(index.expiresAt) {
+		delete(s.indexes, username)
+
+		return nil
+	}
+
+	return index
+}
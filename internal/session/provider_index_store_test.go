@@ -0,0 +1,45 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryUserSessionIndexStore(t *testing.T) {
+	store := newMemoryUserSessionIndexStore()
+
+	data, err := store.Get(testUsername, "abc")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, store.Set(testUsername, "abc", []byte("one"), time.Minute))
+	require.NoError(t, store.Set(testUsername, "xyz", []byte("two"), time.Minute))
+	require.NoError(t, store.Set("harry", "abc", []byte("three"), time.Minute))
+
+	data, err = store.Get(testUsername, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("one"), data)
+
+	entries, err := store.GetAll(testUsername)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"abc": []byte("one"), "xyz": []byte("two")}, entries)
+
+	require.NoError(t, store.Delete(testUsername, "abc", "missing"))
+
+	entries, err = store.GetAll(testUsername)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"xyz": []byte("two")}, entries)
+
+	require.NoError(t, store.Delete(testUsername, "xyz"))
+	assert.NotContains(t, store.indexes, testUsername)
+
+	require.NoError(t, store.Set("harry", "abc", []byte("three"), -time.Second))
+
+	entries, err = store.GetAll("harry")
+	require.NoError(t, err)
+	assert.Len(t, entries, 0)
+	assert.NotContains(t, store.indexes, "harry")
+}
@@ -0,0 +1,153 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fasthttp/session/v2/providers/redis"
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// newRedisClient returns the redis client shared by the session store and the index of the sessions of the users.
+func newRedisClient(config *redis.Config) (client goredis.UniversalClient, err error) {
+	if config.Logger != nil {
+		goredis.SetLogger(config.Logger)
+	}
+
+	client = goredis.NewClient(&goredis.Options{
+		Network:            config.Network,
+		Addr:               config.Addr,
+		Username:           config.Username,
+		Password:           config.Password,
+		DB:                 config.DB,
+		MaxRetries:         config.MaxRetries,
+		MinRetryBackoff:    config.MinRetryBackoff,
+		MaxRetryBackoff:    config.MaxRetryBackoff,
+		DialTimeout:        config.DialTimeout,
+		ReadTimeout:        config.ReadTimeout,
+		WriteTimeout:       config.WriteTimeout,
+		PoolSize:           config.PoolSize,
+		MinIdleConns:       config.MinIdleConns,
+		MaxConnAge:         config.MaxConnAge,
+		PoolTimeout:        config.PoolTimeout,
+		IdleTimeout:        config.IdleTimeout,
+		IdleCheckFrequency: config.IdleCheckFrequency,
+		TLSConfig:          config.TLSConfig,
+		Limiter:            config.Limiter,
+	})
+
+	if err = client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("redis connection error: %w", err)
+	}
+
+	return client, nil
+}
+
+// newRedisSentinelClient returns the redis sentinel client shared by the session store and the index of the sessions
+// of the users.
+func newRedisSentinelClient(config *redis.FailoverConfig) (client goredis.UniversalClient, err error) {
+	if config.Logger != nil {
+		goredis.SetLogger(config.Logger)
+	}
+
+	client = goredis.NewFailoverClusterClient(&goredis.FailoverOptions{
+		MasterName:         config.MasterName,
+		SentinelAddrs:      config.SentinelAddrs,
+		SentinelUsername:   config.SentinelUsername,
+		SentinelPassword:   config.SentinelPassword,
+		RouteByLatency:     config.RouteByLatency,
+		RouteRandomly:      config.RouteRandomly,
+		SlaveOnly:          config.SlaveOnly,
+		Username:           config.Username,
+		Password:           config.Password,
+		DB:                 config.DB,
+		MaxRetries:         config.MaxRetries,
+		MinRetryBackoff:    config.MinRetryBackoff,
+		MaxRetryBackoff:    config.MaxRetryBackoff,
+		DialTimeout:        config.DialTimeout,
+		ReadTimeout:        config.ReadTimeout,
+		WriteTimeout:       config.WriteTimeout,
+		PoolSize:           config.PoolSize,
+		MinIdleConns:       config.MinIdleConns,
+		MaxConnAge:         config.MaxConnAge,
+		PoolTimeout:        config.PoolTimeout,
+		IdleTimeout:        config.IdleTimeout,
+		IdleCheckFrequency: config.IdleCheckFrequency,
+		TLSConfig:          config.TLSConfig,
+	})
+
+	if err = client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("redis connection error: %w", err)
+	}
+
+	return client, nil
+}
+
+// redisProvider is a fasthttpsession.Provider which stores the sessions in redis using a client shared with the index
+// of the sessions of the users. The keys are the same as the ones of the upstream redis provider.
+type redisProvider struct {
+	client    goredis.UniversalClient
+	keyPrefix string
+}
+
+// Get returns the data of the session.
+func (p *redisProvider) Get(id []byte) (data []byte, err error) {
+	if data, err = p.client.Get(context.Background(), p.key(id)).Bytes(); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Save saves the data of the session with the expiration.
+func (p *redisProvider) Save(id, data []byte, expiration time.Duration) (err error) {
+	return p.client.Set(context.Background(), p.key(id), data, expiration).Err()
+}
+
+// Regenerate renames the session to the new id and sets the expiration.
+func (p *redisProvider) Regenerate(id, newID []byte, expiration time.Duration) (err error) {
+	key, newKey := p.key(id), p.key(newID)
+
+	var exists int64
+
+	if exists, err = p.client.Exists(context.Background(), key).Result(); err != nil || exists == 0 {
+		return err
+	}
+
+	if err = p.client.Rename(context.Background(), key, newKey).Err(); err != nil {
+		return err
+	}
+
+	return p.client.Expire(context.Background(), newKey, expiration).Err()
+}
+
+// Destroy removes the session.
+func (p *redisProvider) Destroy(id []byte) (err error) {
+	return p.client.Del(context.Background(), p.key(id)).Err()
+}
+
+// Count returns the number of sessions.
+func (p *redisProvider) Count() int {
+	keys, err := p.client.Keys(context.Background(), p.key([]byte("*"))).Result()
+	if err != nil {
+		return 0
+	}
+
+	return len(keys)
+}
+
+// NeedGC returns false as redis expires the sessions.
+func (p *redisProvider) NeedGC() bool {
+	return false
+}
+
+// GC does nothing as redis expires the sessions.
+func (p *redisProvider) GC() error {
+	return nil
+}
+
+func (p *redisProvider) key(id []byte) string {
+	return p.keyPrefix + ":" + string(id)
+}
@@ -1,9 +1,12 @@
 package session
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	fasthttpsession "github.com/fasthttp/session/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
@@ -22,7 +25,7 @@ func TestShouldInitializerSession(t *testing.T) {
 	configuration.Expiration = testExpiration
 
 	provider := NewProvider(configuration, nil)
-	session, err := provider.GetSession(ctx)
+	session, err := provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t, NewDefaultUserSession(), session)
@@ -37,15 +40,15 @@ func TestShouldUpdateSession(t *testing.T) {
 	configuration.Expiration = testExpiration
 
 	provider := NewProvider(configuration, nil)
-	session, _ := provider.GetSession(ctx)
+	session, _ := provider.GetSession(ctx, testDomain)
 
 	session.Username = testUsername
 	session.AuthenticationLevel = authentication.TwoFactor
 
-	err := provider.SaveSession(ctx, session)
+	err := provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t, UserSession{
@@ -67,14 +70,14 @@ func TestShouldSetSessionAuthenticationLevels(t *testing.T) {
 	configuration.Expiration = testExpiration
 
 	provider := NewProvider(configuration, nil)
-	session, _ := provider.GetSession(ctx)
+	session, _ := provider.GetSession(ctx, testDomain)
 
 	session.SetOneFactor(timeOneFactor, &authentication.UserDetails{Username: testUsername}, false)
 
-	err := provider.SaveSession(ctx, session)
+	err := provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	authAt, err := session.AuthenticatedTime(authorization.OneFactor)
@@ -94,15 +97,17 @@ func TestShouldSetSessionAuthenticationLevels(t *testing.T) {
 		AuthenticationLevel:       authentication.OneFactor,
 		LastActivity:              timeOneFactor.Unix(),
 		FirstFactorAuthnTimestamp: timeOneFactor.Unix(),
+		FirstFactorAuthnTime:      timeOneFactor.UTC(),
 		AuthenticationMethodRefs:  oidc.AuthenticationMethodsReferences{UsernameAndPassword: true},
+		RevocationCheckTTL:        timeOneFactor.Add(RevocationCheckInterval).UTC(),
 	}, session)
 
 	session.SetTwoFactorDuo(timeTwoFactor)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t, UserSession{
@@ -110,8 +115,10 @@ func TestShouldSetSessionAuthenticationLevels(t *testing.T) {
 		AuthenticationLevel:        authentication.TwoFactor,
 		LastActivity:               timeTwoFactor.Unix(),
 		FirstFactorAuthnTimestamp:  timeOneFactor.Unix(),
+		FirstFactorAuthnTime:       timeOneFactor.UTC(),
 		SecondFactorAuthnTimestamp: timeTwoFactor.Unix(),
 		AuthenticationMethodRefs:   oidc.AuthenticationMethodsReferences{UsernameAndPassword: true, Duo: true},
+		RevocationCheckTTL:         timeOneFactor.Add(RevocationCheckInterval).UTC(),
 	}, session)
 
 	authAt, err = session.AuthenticatedTime(authorization.OneFactor)
@@ -127,6 +134,26 @@ func TestShouldSetSessionAuthenticationLevels(t *testing.T) {
 	assert.Equal(t, timeZeroFactor, authAt)
 }
 
+func TestShouldRetainSecondFactorWhenReauthenticatingFirstFactor(t *testing.T) {
+	timeOneFactor := time.Unix(1625048140, 0)
+	timeTwoFactor := time.Unix(1625048150, 0)
+	timeReauthenticated := time.Unix(1625049140, 0)
+
+	session := NewDefaultUserSession()
+
+	session.SetOneFactor(timeOneFactor, &authentication.UserDetails{Username: testUsername}, false)
+	session.SetTwoFactorTOTP(timeTwoFactor)
+	session.SetOneFactor(timeReauthenticated, &authentication.UserDetails{Username: testUsername}, false)
+
+	assert.Equal(t, authentication.TwoFactor, session.AuthenticationLevel)
+	assert.Equal(t, timeReauthenticated.Unix(), session.FirstFactorAuthnTimestamp)
+	assert.Equal(t, timeTwoFactor.Unix(), session.SecondFactorAuthnTimestamp)
+
+	session.SetOneFactor(timeReauthenticated, &authentication.UserDetails{Username: "harry"}, false)
+
+	assert.Equal(t, authentication.OneFactor, session.AuthenticationLevel)
+}
+
 func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 	ctx := &fasthttp.RequestCtx{}
 	configuration := schema.SessionConfiguration{}
@@ -140,14 +167,14 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 	configuration.Expiration = testExpiration
 
 	provider := NewProvider(configuration, nil)
-	session, _ := provider.GetSession(ctx)
+	session, _ := provider.GetSession(ctx, testDomain)
 
 	session.SetOneFactor(timeOneFactor, &authentication.UserDetails{Username: testUsername}, false)
 
-	err := provider.SaveSession(ctx, session)
+	err := provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	authAt, err := session.AuthenticatedTime(authorization.OneFactor)
@@ -167,15 +194,17 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 		AuthenticationLevel:       authentication.OneFactor,
 		LastActivity:              timeOneFactor.Unix(),
 		FirstFactorAuthnTimestamp: timeOneFactor.Unix(),
+		FirstFactorAuthnTime:      timeOneFactor.UTC(),
 		AuthenticationMethodRefs:  oidc.AuthenticationMethodsReferences{UsernameAndPassword: true},
+		RevocationCheckTTL:        timeOneFactor.Add(RevocationCheckInterval).UTC(),
 	}, session)
 
 	session.SetTwoFactorWebauthn(timeTwoFactor, false, false)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t, oidc.AuthenticationMethodsReferences{UsernameAndPassword: true, Webauthn: true}, session.AuthenticationMethodRefs)
@@ -195,10 +224,10 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 
 	session.SetTwoFactorWebauthn(timeTwoFactor, false, false)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t,
@@ -207,10 +236,10 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 
 	session.SetTwoFactorWebauthn(timeTwoFactor, false, false)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t,
@@ -219,10 +248,10 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 
 	session.SetTwoFactorWebauthn(timeTwoFactor, true, false)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t,
@@ -231,10 +260,10 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 
 	session.SetTwoFactorWebauthn(timeTwoFactor, true, false)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t,
@@ -243,10 +272,10 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 
 	session.SetTwoFactorWebauthn(timeTwoFactor, false, true)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t,
@@ -255,10 +284,10 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 
 	session.SetTwoFactorWebauthn(timeTwoFactor, false, true)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t,
@@ -267,10 +296,10 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 
 	session.SetTwoFactorTOTP(timeTwoFactor)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t,
@@ -279,10 +308,10 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 
 	session.SetTwoFactorTOTP(timeTwoFactor)
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	session, err = provider.GetSession(ctx)
+	session, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	assert.Equal(t,
@@ -298,25 +327,255 @@ func TestShouldDestroySessionAndWipeSessionData(t *testing.T) {
 	configuration.Expiration = testExpiration
 
 	provider := NewProvider(configuration, nil)
-	session, err := provider.GetSession(ctx)
+	session, err := provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 
 	session.Username = testUsername
 	session.AuthenticationLevel = authentication.TwoFactor
 
-	err = provider.SaveSession(ctx, session)
+	err = provider.SaveSession(ctx, testDomain, session)
 	require.NoError(t, err)
 
-	newUserSession, err := provider.GetSession(ctx)
+	newUserSession, err := provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 	assert.Equal(t, testUsername, newUserSession.Username)
 	assert.Equal(t, authentication.TwoFactor, newUserSession.AuthenticationLevel)
 
-	err = provider.DestroySession(ctx)
+	err = provider.DestroySession(ctx, testDomain)
 	require.NoError(t, err)
 
-	newUserSession, err = provider.GetSession(ctx)
+	newUserSession, err = provider.GetSession(ctx, testDomain)
 	require.NoError(t, err)
 	assert.Equal(t, "", newUserSession.Username)
 	assert.Equal(t, authentication.NotAuthenticated, newUserSession.AuthenticationLevel)
 }
+
+func TestShouldSelectSessionByHost(t *testing.T) {
+	configuration := schema.SessionConfiguration{
+		Cookies: []schema.SessionCookieConfiguration{
+			{Domain: testDomain, Name: testName, Expiration: testExpiration, Inactivity: time.Minute},
+			{Domain: "example.org", Name: "other_session", Expiration: testExpiration, Inactivity: time.Hour},
+		},
+	}
+
+	provider := NewProvider(configuration, nil)
+
+	testCases := []struct {
+		name         string
+		host         string
+		expected     string
+		expectedName string
+	}{
+		{"ShouldUseDomain", "example.org", "example.org", "other_session"},
+		{"ShouldUseSubdomain", "auth.example.org", "example.org", "other_session"},
+		{"ShouldUseSubdomainCaseInsensitive", "Auth.Example.ORG", "example.org", "other_session"},
+		{"ShouldUseFirstDomainWhenNotProtected", "example.net", testDomain, testName},
+		{"ShouldUseFirstDomainWhenEmpty", "", testDomain, testName},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+
+			assert.Equal(t, tc.expected, provider.GetCookieConfiguration(tc.host).Domain)
+
+			session, err := provider.GetSession(ctx, tc.host)
+			require.NoError(t, err)
+
+			session.Username = testUsername
+
+			require.NoError(t, provider.SaveSession(ctx, tc.host, session))
+
+			cookie := fasthttp.AcquireCookie()
+			defer fasthttp.ReleaseCookie(cookie)
+
+			cookie.SetKey(tc.expectedName)
+
+			require.True(t, ctx.Response.Header.Cookie(cookie))
+			assert.Equal(t, tc.expected, string(cookie.Domain()))
+		})
+	}
+}
+
+func TestShouldListAndRevokeUserSessions(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil)
+
+	ctxs := make([]*fasthttp.RequestCtx, 3)
+
+	for i := range ctxs {
+		ctx := &fasthttp.RequestCtx{}
+
+		ctx.Request.Header.Set(fasthttp.HeaderXForwardedFor, fmt.Sprintf("192.168.0.%d, 10.0.0.1", i+1))
+		ctx.Request.Header.SetUserAgent("Mozilla/5.0")
+
+		session, err := provider.GetSession(ctx, testDomain)
+		require.NoError(t, err)
+
+		session.SetOneFactor(time.Unix(1625048140, 0), &authentication.UserDetails{Username: testUsername}, false)
+
+		require.NoError(t, provider.SaveSession(ctx, testDomain, session))
+
+		ctxs[i] = ctx
+	}
+
+	anonymous := &fasthttp.RequestCtx{}
+
+	session, err := provider.GetSession(anonymous, testDomain)
+	require.NoError(t, err)
+	require.NoError(t, provider.SaveSession(anonymous, testDomain, session))
+
+	sessions, err := provider.ListUserSessions(testUsername)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+
+	id, err := provider.GetUserSessionID(ctxs[0], testDomain)
+	require.NoError(t, err)
+
+	var current *Metadata
+
+	for i := range sessions {
+		if sessions[i].ID == id {
+			current = &sessions[i]
+		}
+	}
+
+	require.NotNil(t, current)
+	assert.Equal(t, testDomain, current.Domain)
+	assert.Equal(t, "192.168.0.1", current.IP)
+	assert.Equal(t, "Mozilla/5.0", current.UserAgent)
+	assert.True(t, time.Unix(1625048140, 0).Equal(current.CreatedAt))
+	assert.Equal(t, authentication.OneFactor, current.AuthenticationLevel)
+
+	require.NoError(t, provider.RevokeUserSession(testUsername, id))
+	assert.ErrorIs(t, provider.RevokeUserSession(testUsername, id), ErrUserSessionNotFound)
+
+	session, err = provider.GetSession(ctxs[0], testDomain)
+	require.NoError(t, err)
+	assert.True(t, session.IsAnonymous())
+
+	keep, err := provider.GetUserSessionID(ctxs[1], testDomain)
+	require.NoError(t, err)
+
+	revoked, err := provider.RevokeUserSessions(testUsername, keep)
+	require.NoError(t, err)
+	assert.Equal(t, 1, revoked)
+
+	sessions, err = provider.ListUserSessions(testUsername)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, keep, sessions[0].ID)
+
+	require.NoError(t, provider.DestroySession(ctxs[1], testDomain))
+
+	sessions, err = provider.ListUserSessions(testUsername)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 0)
+}
+
+func TestShouldOnlyIndexUserSessionWhenRequired(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil)
+
+	ctx := &fasthttp.RequestCtx{}
+
+	session, err := provider.GetSession(ctx, testDomain)
+	require.NoError(t, err)
+
+	session.SetOneFactor(time.Now(), &authentication.UserDetails{Username: testUsername}, false)
+
+	require.NoError(t, provider.SaveSession(ctx, testDomain, session))
+
+	id, err := provider.GetUserSessionID(ctx, testDomain)
+	require.NoError(t, err)
+
+	sessions, err := provider.ListUserSessions(testUsername)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	// Remove the entry from the index directly to observe whether the next save records it again.
+	require.NoError(t, provider.index.Delete(testUsername, id))
+
+	session, err = provider.GetSession(ctx, testDomain)
+	require.NoError(t, err)
+	require.NoError(t, provider.SaveSession(ctx, testDomain, session))
+
+	sessions, err = provider.ListUserSessions(testUsername)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 0)
+
+	session.SetTwoFactorTOTP(time.Now())
+
+	require.NoError(t, provider.SaveSession(ctx, testDomain, session))
+
+	sessions, err = provider.ListUserSessions(testUsername)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, id, sessions[0].ID)
+	assert.Equal(t, authentication.TwoFactor, sessions[0].AuthenticationLevel)
+}
+
+func TestShouldSetUserSessionIndexState(t *testing.T) {
+	now := time.Unix(1625048140, 0)
+
+	store := fasthttpsession.NewStore()
+
+	assert.True(t, setUserSessionIndexState(store, "abc", authentication.OneFactor, now))
+	assert.False(t, setUserSessionIndexState(store, "abc", authentication.OneFactor, now.Add(time.Second*59)))
+	assert.True(t, setUserSessionIndexState(store, "abc", authentication.OneFactor, now.Add(time.Minute)))
+	assert.True(t, setUserSessionIndexState(store, "abc", authentication.TwoFactor, now.Add(time.Minute)))
+	assert.True(t, setUserSessionIndexState(store, "xyz", authentication.TwoFactor, now.Add(time.Minute)))
+	assert.False(t, setUserSessionIndexState(store, "xyz", authentication.TwoFactor, now.Add(time.Minute)))
+}
+
+func TestShouldSaveSessionWhenIndexFails(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil)
+	provider.index = &failingUserSessionIndexStore{}
+
+	ctx := &fasthttp.RequestCtx{}
+
+	session, err := provider.GetSession(ctx, testDomain)
+	require.NoError(t, err)
+
+	session.SetOneFactor(time.Now(), &authentication.UserDetails{Username: testUsername}, false)
+
+	require.NoError(t, provider.SaveSession(ctx, testDomain, session))
+
+	session, err = provider.GetSession(ctx, testDomain)
+	require.NoError(t, err)
+	assert.Equal(t, testUsername, session.Username)
+
+	_, err = provider.ListUserSessions(testUsername)
+	assert.EqualError(t, err, "unavailable")
+}
+
+type failingUserSessionIndexStore struct{}
+
+func (s *failingUserSessionIndexStore) Get(_, _ string) ([]byte, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (s *failingUserSessionIndexStore) GetAll(_ string) (map[string][]byte, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (s *failingUserSessionIndexStore) Set(_, _ string, _ []byte, _ time.Duration) error {
+	return errors.New("unavailable")
+}
+
+func (s *failingUserSessionIndexStore) Delete(_ string, _ ...string) error {
+	return errors.New("unavailable")
+}
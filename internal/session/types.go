@@ -16,6 +16,7 @@ type ProviderConfig struct {
 	config              session.Config
 	redisConfig         *redis.Config
 	redisSentinelConfig *redis.FailoverConfig
+	serializer          *EncryptingSerializer
 	providerName        string
 }
 
@@ -27,6 +28,8 @@ type UserSession struct {
 	Groups []string
 	Emails []string
 
+	Attributes map[string][]string
+
 	KeepMeLoggedIn      bool
 	AuthenticationLevel authentication.Level
 	LastActivity        int64
@@ -34,6 +37,10 @@ type UserSession struct {
 	FirstFactorAuthnTimestamp  int64
 	SecondFactorAuthnTimestamp int64
 
+	// FirstFactorAuthnTime is the time of the first factor authentication with sub-second precision which is compared
+	// with the time the sessions of the user were revoked.
+	FirstFactorAuthnTime time.Time
+
 	AuthenticationMethodRefs oidc.AuthenticationMethodsReferences
 
 	// Webauthn holds the session registration data for this session.
@@ -44,6 +51,34 @@ type UserSession struct {
 	PasswordResetUsername *string
 
 	RefreshTTL time.Time
+
+	// RevocationCheckTTL is the time after which the session should next be checked for a revocation.
+	RevocationCheckTTL time.Time
+}
+
+// Metadata is the metadata of an active session of a user recorded in the index of the sessions of the user.
+type Metadata struct {
+	ID                  string               `json:"id"`
+	Domain              string               `json:"domain"`
+	IP                  string               `json:"ip"`
+	UserAgent           string               `json:"user_agent"`
+	CreatedAt           time.Time            `json:"created_at"`
+	LastActivityAt      time.Time            `json:"last_activity_at"`
+	AuthenticationLevel authentication.Level `json:"authentication_level"`
+}
+
+// userSessionIndexState is the state of the session last recorded in the index of the sessions of the user which is
+// kept in the session store alongside the user session.
+type userSessionIndexState struct {
+	ID                  string               `json:"id"`
+	AuthenticationLevel authentication.Level `json:"authentication_level"`
+	TTL                 time.Time            `json:"ttl"`
+}
+
+type userSessionIndexEntry struct {
+	SessionID string `json:"session_id"`
+
+	Metadata
 }
 
 // Identity identity of the user who is being verified.
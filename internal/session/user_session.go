@@ -22,11 +22,23 @@ func (s *UserSession) IsAnonymous() bool {
 	return s.Username == "" || s.AuthenticationLevel == authentication.NotAuthenticated
 }
 
-// SetOneFactor sets the 1FA AMR's and expected property values for one factor authentication.
+// SetOneFactor sets the 1FA AMR's and expected property values for one factor authentication. If the same user has
+// already completed two factor authentication in this session the 2FA level is retained so the user only has to
+// re-authenticate the first factor.
 func (s *UserSession) SetOneFactor(now time.Time, details *authentication.UserDetails, keepMeLoggedIn bool) {
+	reauthenticated := s.Username == details.Username && s.AuthenticationLevel == authentication.TwoFactor
+
 	s.FirstFactorAuthnTimestamp = now.Unix()
+	s.FirstFactorAuthnTime = now
 	s.LastActivity = now.Unix()
-	s.AuthenticationLevel = authentication.OneFactor
+
+	// The session was authenticated after any revocation which exists at this time so the storage doesn't need to be
+	// checked until the next interval.
+	s.RevocationCheckTTL = now.Add(RevocationCheckInterval)
+
+	if !reauthenticated {
+		s.AuthenticationLevel = authentication.OneFactor
+	}
 
 	s.KeepMeLoggedIn = keepMeLoggedIn
 
@@ -34,6 +46,7 @@ func (s *UserSession) SetOneFactor(now time.Time, details *authentication.UserDe
 	s.DisplayName = details.DisplayName
 	s.Groups = details.Groups
 	s.Emails = details.Emails
+	s.Attributes = details.Attributes
 
 	s.AuthenticationMethodRefs.UsernameAndPassword = true
 }
@@ -5,13 +5,17 @@ import (
 )
 
 const (
-	tableAuthenticationLogs   = "authentication_logs"
-	tableDuoDevices           = "duo_devices"
-	tableIdentityVerification = "identity_verification"
-	tableTOTPConfigurations   = "totp_configurations"
-	tableUserOpaqueIdentifier = "user_opaque_identifier"
-	tableUserPreferences      = "user_preferences"
-	tableWebauthnDevices      = "webauthn_devices"
+	tableAuthenticationLogs     = "authentication_logs"
+	tableDuoDevices             = "duo_devices"
+	tableIdentityVerification   = "identity_verification"
+	tableTOTPConfigurations     = "totp_configurations"
+	tableUserGroups             = "user_groups"
+	tableUserOpaqueIdentifier   = "user_opaque_identifier"
+	tableUserPasswordHistory    = "user_password_history"
+	tableUserPreferences        = "user_preferences"
+	tableUserSessionRevocations = "user_session_revocations"
+	tableUsers                  = "users"
+	tableWebauthnDevices        = "webauthn_devices"
 
 	tableOAuth2ConsentSession          = "oauth2_consent_session"
 	tableOAuth2ConsentPreConfiguration = "oauth2_consent_preconfiguration"
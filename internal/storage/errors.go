@@ -17,6 +17,9 @@ var (
 	// ErrNoDuoDevice error thrown when no Duo device and method has been found in DB.
 	ErrNoDuoDevice = errors.New("no Duo device and method saved")
 
+	// ErrNoUser error thrown when no user has been found in DB.
+	ErrNoUser = errors.New("no user found")
+
 	// ErrNoAvailableMigrations is returned when no available migrations can be found.
 	ErrNoAvailableMigrations = errors.New("no available migrations")
 
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL CONSTRAINT users_pkey PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    password VARCHAR(512) NOT NULL,
+    display_name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    disabled BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX users_username_key ON users (username);
+CREATE INDEX users_email_idx ON users (email);
+
+CREATE TABLE IF NOT EXISTS user_groups (
+    id SERIAL CONSTRAINT user_groups_pkey PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    group_name VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX user_groups_lookup_key ON user_groups (username, group_name);
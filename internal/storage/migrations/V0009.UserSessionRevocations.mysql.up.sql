@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS user_session_revocations (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    revoked_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    username VARCHAR(100) NOT NULL,
+    UNIQUE KEY (username)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
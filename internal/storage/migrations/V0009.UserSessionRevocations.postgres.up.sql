@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_session_revocations (
+    id SERIAL CONSTRAINT user_session_revocations_pkey PRIMARY KEY,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX user_session_revocations_username_key ON user_session_revocations (username);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS user_session_revocations (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    revoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    UNIQUE (username)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_password_history (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    digest VARCHAR(512) NOT NULL
+);
+
+CREATE INDEX user_password_history_username_idx ON user_password_history (username);
@@ -17,12 +17,20 @@ type Provider interface {
 
 	RegulatorProvider
 
+	UserDatabaseProvider
+
 	storage.Transactional
 
 	SavePreferred2FAMethod(ctx context.Context, username string, method string) (err error)
 	LoadPreferred2FAMethod(ctx context.Context, username string) (method string, err error)
 	LoadUserInfo(ctx context.Context, username string) (info model.UserInfo, err error)
 
+	SaveUserSessionRevocation(ctx context.Context, username string, revokedAt time.Time) (err error)
+	LoadUserSessionRevocation(ctx context.Context, username string) (revokedAt time.Time, err error)
+
+	SaveUserPasswordHistory(ctx context.Context, username, digest string, retain int) (err error)
+	LoadUserPasswordHistory(ctx context.Context, username string, limit int) (digests []string, err error)
+
 	SaveUserOpaqueIdentifier(ctx context.Context, subject model.UserOpaqueIdentifier) (err error)
 	LoadUserOpaqueIdentifier(ctx context.Context, opaqueUUID uuid.UUID) (subject *model.UserOpaqueIdentifier, err error)
 	LoadUserOpaqueIdentifiers(ctx context.Context) (opaqueIDs []model.UserOpaqueIdentifier, err error)
@@ -88,3 +96,14 @@ type RegulatorProvider interface {
 	AppendAuthenticationLog(ctx context.Context, attempt model.AuthenticationAttempt) (err error)
 	LoadAuthenticationLogs(ctx context.Context, username string, fromDate time.Time, limit, page int) (attempts []model.AuthenticationAttempt, err error)
 }
+
+// UserDatabaseProvider is an interface providing storage capabilities for persisting users managed by the storage
+// authentication backend.
+type UserDatabaseProvider interface {
+	SaveUser(ctx context.Context, user model.User) (err error)
+	UpdateUserPassword(ctx context.Context, username, password string) (err error)
+	DeleteUser(ctx context.Context, username string) (err error)
+	LoadUser(ctx context.Context, username string) (user *model.User, err error)
+	LoadUserByEmail(ctx context.Context, email string) (user *model.User, err error)
+	LoadUsers(ctx context.Context, limit, page int) (users []model.User, err error)
+}
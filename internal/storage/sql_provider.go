@@ -65,6 +65,23 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlSelectPreferred2FAMethod: fmt.Sprintf(queryFmtSelectPreferred2FAMethod, tableUserPreferences),
 		sqlSelectUserInfo:           fmt.Sprintf(queryFmtSelectUserInfo, tableTOTPConfigurations, tableWebauthnDevices, tableDuoDevices, tableUserPreferences),
 
+		sqlSelectUser:         fmt.Sprintf(queryFmtSelectUser, tableUsers),
+		sqlSelectUsersByEmail: fmt.Sprintf(queryFmtSelectUsersByEmail, tableUsers),
+		sqlSelectUsers:        fmt.Sprintf(queryFmtSelectUsers, tableUsers),
+		sqlUpsertUser:         fmt.Sprintf(queryFmtUpsertUser, tableUsers),
+		sqlUpdateUserPassword: fmt.Sprintf(queryFmtUpdateUserPassword, tableUsers),
+		sqlDeleteUser:         fmt.Sprintf(queryFmtDeleteUser, tableUsers),
+		sqlSelectUserGroups:   fmt.Sprintf(queryFmtSelectUserGroups, tableUserGroups),
+		sqlInsertUserGroup:    fmt.Sprintf(queryFmtInsertUserGroup, tableUserGroups),
+		sqlDeleteUserGroups:   fmt.Sprintf(queryFmtDeleteUserGroups, tableUserGroups),
+
+		sqlSelectUserSessionRevocation: fmt.Sprintf(queryFmtSelectUserSessionRevocation, tableUserSessionRevocations),
+		sqlUpsertUserSessionRevocation: fmt.Sprintf(queryFmtUpsertUserSessionRevocation, tableUserSessionRevocations),
+
+		sqlSelectUserPasswordHistory:        fmt.Sprintf(queryFmtSelectUserPasswordHistory, tableUserPasswordHistory),
+		sqlInsertUserPasswordHistory:        fmt.Sprintf(queryFmtInsertUserPasswordHistory, tableUserPasswordHistory),
+		sqlDeleteUserPasswordHistoryExpired: fmt.Sprintf(queryFmtDeleteUserPasswordHistoryExpired, tableUserPasswordHistory, tableUserPasswordHistory),
+
 		sqlInsertUserOpaqueIdentifier:            fmt.Sprintf(queryFmtInsertUserOpaqueIdentifier, tableUserOpaqueIdentifier),
 		sqlSelectUserOpaqueIdentifier:            fmt.Sprintf(queryFmtSelectUserOpaqueIdentifier, tableUserOpaqueIdentifier),
 		sqlSelectUserOpaqueIdentifiers:           fmt.Sprintf(queryFmtSelectUserOpaqueIdentifiers, tableUserOpaqueIdentifier),
@@ -182,6 +199,28 @@ type SQLProvider struct {
 	sqlSelectPreferred2FAMethod string
 	sqlSelectUserInfo           string
 
+	// Table: users.
+	sqlSelectUser         string
+	sqlSelectUsersByEmail string
+	sqlSelectUsers        string
+	sqlUpsertUser         string
+	sqlUpdateUserPassword string
+	sqlDeleteUser         string
+
+	// Table: user_groups.
+	sqlSelectUserGroups string
+	sqlInsertUserGroup  string
+	sqlDeleteUserGroups string
+
+	// Table: user_session_revocations.
+	sqlSelectUserSessionRevocation string
+	sqlUpsertUserSessionRevocation string
+
+	// Table: user_password_history.
+	sqlSelectUserPasswordHistory        string
+	sqlInsertUserPasswordHistory        string
+	sqlDeleteUserPasswordHistoryExpired string
+
 	// Table: user_opaque_identifier.
 	sqlInsertUserOpaqueIdentifier            string
 	sqlSelectUserOpaqueIdentifier            string
@@ -338,6 +377,14 @@ func (p *SQLProvider) Rollback(ctx context.Context) (err error) {
 	return tx.Rollback()
 }
 
+func rollbackWithError(tx *sqlx.Tx, err error) error {
+	if rerr := tx.Rollback(); rerr != nil {
+		return fmt.Errorf("rollback error %v: rollback due to error: %w", rerr, err)
+	}
+
+	return fmt.Errorf("rollback due to error: %w", err)
+}
+
 // SaveUserOpaqueIdentifier saves a new opaque user identifier to the database.
 func (p *SQLProvider) SaveUserOpaqueIdentifier(ctx context.Context, opaqueID model.UserOpaqueIdentifier) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserOpaqueIdentifier, opaqueID.Service, opaqueID.SectorID, opaqueID.Username, opaqueID.Identifier); err != nil {
@@ -718,6 +765,199 @@ func (p *SQLProvider) LoadUserInfo(ctx context.Context, username string) (info m
 	}
 }
 
+// SaveUser saves a user managed by the storage authentication backend including their groups.
+func (p *SQLProvider) SaveUser(ctx context.Context, user model.User) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction to save user '%s': %w", user.Username, err)
+	}
+
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlUpsertUser, user.CreatedAt, user.Username, user.Password, user.DisplayName, user.Email, user.Disabled); err != nil {
+		return rollbackWithError(tx, fmt.Errorf("error upserting user '%s': %w", user.Username, err))
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUserGroups, user.Username); err != nil {
+		return rollbackWithError(tx, fmt.Errorf("error deleting groups for user '%s': %w", user.Username, err))
+	}
+
+	for _, group := range user.Groups {
+		if _, err = tx.ExecContext(ctx, p.sqlInsertUserGroup, user.Username, group); err != nil {
+			return rollbackWithError(tx, fmt.Errorf("error inserting group '%s' for user '%s': %w", group, user.Username, err))
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateUserPassword updates the password digest of a user managed by the storage authentication backend.
+func (p *SQLProvider) UpdateUserPassword(ctx context.Context, username, password string) (err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlUpdateUserPassword, password, username); err != nil {
+		return fmt.Errorf("error updating password for user '%s': %w", username, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNoUser
+	}
+
+	return nil
+}
+
+// DeleteUser deletes a user managed by the storage authentication backend including their groups.
+func (p *SQLProvider) DeleteUser(ctx context.Context, username string) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction to delete user '%s': %w", username, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUserGroups, username); err != nil {
+		return rollbackWithError(tx, fmt.Errorf("error deleting groups for user '%s': %w", username, err))
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUser, username); err != nil {
+		return rollbackWithError(tx, fmt.Errorf("error deleting user '%s': %w", username, err))
+	}
+
+	return tx.Commit()
+}
+
+// LoadUser loads a user managed by the storage authentication backend given their username.
+func (p *SQLProvider) LoadUser(ctx context.Context, username string) (user *model.User, err error) {
+	user = &model.User{}
+
+	if err = p.db.GetContext(ctx, user, p.sqlSelectUser, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoUser
+		}
+
+		return nil, fmt.Errorf("error selecting user '%s': %w", username, err)
+	}
+
+	if err = p.loadUserGroups(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// LoadUserByEmail loads a user managed by the storage authentication backend given their email.
+func (p *SQLProvider) LoadUserByEmail(ctx context.Context, email string) (user *model.User, err error) {
+	var users []model.User
+
+	if err = p.db.SelectContext(ctx, &users, p.sqlSelectUsersByEmail, email); err != nil {
+		return nil, fmt.Errorf("error selecting user with email '%s': %w", email, err)
+	}
+
+	switch len(users) {
+	case 0:
+		return nil, ErrNoUser
+	case 1:
+		user = &users[0]
+	default:
+		return nil, fmt.Errorf("error selecting user with email '%s': %d users have this email", email, len(users))
+	}
+
+	if err = p.loadUserGroups(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// LoadUsers loads a page of users managed by the storage authentication backend.
+func (p *SQLProvider) LoadUsers(ctx context.Context, limit, page int) (users []model.User, err error) {
+	users = make([]model.User, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &users, p.sqlSelectUsers, limit, limit*page); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting users: %w", err)
+	}
+
+	for i := range users {
+		if err = p.loadUserGroups(ctx, &users[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return users, nil
+}
+
+func (p *SQLProvider) loadUserGroups(ctx context.Context, user *model.User) (err error) {
+	user.Groups = []string{}
+
+	if err = p.db.SelectContext(ctx, &user.Groups, p.sqlSelectUserGroups, user.Username); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error selecting groups for user '%s': %w", user.Username, err)
+	}
+
+	return nil
+}
+
+// SaveUserSessionRevocation saves the time before which all sessions of a user are considered revoked. The time is
+// truncated to microseconds, which is the precision of the databases, so the sessions authenticated at the time of the
+// revocation are never considered revoked due to rounding.
+func (p *SQLProvider) SaveUserSessionRevocation(ctx context.Context, username string, revokedAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertUserSessionRevocation, revokedAt.Truncate(time.Microsecond), username); err != nil {
+		return fmt.Errorf("error upserting session revocation for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// LoadUserSessionRevocation loads the time before which all sessions of a user are considered revoked. The zero time
+// is returned if the sessions of the user have never been revoked.
+func (p *SQLProvider) LoadUserSessionRevocation(ctx context.Context, username string) (revokedAt time.Time, err error) {
+	if err = p.db.GetContext(ctx, &revokedAt, p.sqlSelectUserSessionRevocation, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, fmt.Errorf("error selecting session revocation for user '%s': %w", username, err)
+	}
+
+	return revokedAt, nil
+}
+
+// SaveUserPasswordHistory saves a password digest to the password history of a user and removes all but the most recent
+// retain entries of the history.
+func (p *SQLProvider) SaveUserPasswordHistory(ctx context.Context, username, digest string, retain int) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction to save password history for user '%s': %w", username, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlInsertUserPasswordHistory, time.Now(), username, digest); err != nil {
+		return rollbackWithError(tx, fmt.Errorf("error inserting password history for user '%s': %w", username, err))
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUserPasswordHistoryExpired, username, username, retain); err != nil {
+		return rollbackWithError(tx, fmt.Errorf("error deleting expired password history for user '%s': %w", username, err))
+	}
+
+	return tx.Commit()
+}
+
+// LoadUserPasswordHistory loads the most recent password digests of a user, newest first.
+func (p *SQLProvider) LoadUserPasswordHistory(ctx context.Context, username string, limit int) (digests []string, err error) {
+	if err = p.db.SelectContext(ctx, &digests, p.sqlSelectUserPasswordHistory, username, limit); err != nil {
+		return nil, fmt.Errorf("error selecting password history for user '%s': %w", username, err)
+	}
+
+	return digests, nil
+}
+
 // SaveIdentityVerification save an identity verification record to the database.
 func (p *SQLProvider) SaveIdentityVerification(ctx context.Context, verification model.IdentityVerification) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlInsertIdentityVerification,
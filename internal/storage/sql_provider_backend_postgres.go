@@ -35,6 +35,8 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlUpsertDuoDevice = fmt.Sprintf(queryFmtUpsertDuoDevicePostgreSQL, tableDuoDevices)
 	provider.sqlUpsertTOTPConfig = fmt.Sprintf(queryFmtUpsertTOTPConfigurationPostgreSQL, tableTOTPConfigurations)
 	provider.sqlUpsertPreferred2FAMethod = fmt.Sprintf(queryFmtUpsertPreferred2FAMethodPostgreSQL, tableUserPreferences)
+	provider.sqlUpsertUser = fmt.Sprintf(queryFmtUpsertUserPostgreSQL, tableUsers)
+	provider.sqlUpsertUserSessionRevocation = fmt.Sprintf(queryFmtUpsertUserSessionRevocationPostgreSQL, tableUserSessionRevocations)
 	provider.sqlUpsertEncryptionValue = fmt.Sprintf(queryFmtUpsertEncryptionValuePostgreSQL, tableEncryption)
 	provider.sqlUpsertOAuth2BlacklistedJTI = fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTIPostgreSQL, tableOAuth2BlacklistedJTI)
 	provider.sqlInsertOAuth2ConsentPreConfiguration = fmt.Sprintf(queryFmtInsertOAuth2ConsentPreConfigurationPostgreSQL, tableOAuth2ConsentPreConfiguration)
@@ -45,6 +47,21 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlSelectPreferred2FAMethod = provider.db.Rebind(provider.sqlSelectPreferred2FAMethod)
 	provider.sqlSelectUserInfo = provider.db.Rebind(provider.sqlSelectUserInfo)
 
+	provider.sqlSelectUser = provider.db.Rebind(provider.sqlSelectUser)
+	provider.sqlSelectUsersByEmail = provider.db.Rebind(provider.sqlSelectUsersByEmail)
+	provider.sqlSelectUsers = provider.db.Rebind(provider.sqlSelectUsers)
+	provider.sqlUpdateUserPassword = provider.db.Rebind(provider.sqlUpdateUserPassword)
+	provider.sqlDeleteUser = provider.db.Rebind(provider.sqlDeleteUser)
+	provider.sqlSelectUserGroups = provider.db.Rebind(provider.sqlSelectUserGroups)
+	provider.sqlInsertUserGroup = provider.db.Rebind(provider.sqlInsertUserGroup)
+	provider.sqlDeleteUserGroups = provider.db.Rebind(provider.sqlDeleteUserGroups)
+
+	provider.sqlSelectUserSessionRevocation = provider.db.Rebind(provider.sqlSelectUserSessionRevocation)
+
+	provider.sqlSelectUserPasswordHistory = provider.db.Rebind(provider.sqlSelectUserPasswordHistory)
+	provider.sqlInsertUserPasswordHistory = provider.db.Rebind(provider.sqlInsertUserPasswordHistory)
+	provider.sqlDeleteUserPasswordHistoryExpired = provider.db.Rebind(provider.sqlDeleteUserPasswordHistoryExpired)
+
 	provider.sqlInsertUserOpaqueIdentifier = provider.db.Rebind(provider.sqlInsertUserOpaqueIdentifier)
 	provider.sqlSelectUserOpaqueIdentifier = provider.db.Rebind(provider.sqlSelectUserOpaqueIdentifier)
 	provider.sqlSelectUserOpaqueIdentifierBySignature = provider.db.Rebind(provider.sqlSelectUserOpaqueIdentifierBySignature)
@@ -55,6 +55,101 @@ const (
 			DO UPDATE SET second_factor_method = $2;`
 )
 
+const (
+	queryFmtSelectUser = `
+		SELECT id, created_at, username, password, display_name, email, disabled
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtSelectUsersByEmail = `
+		SELECT id, created_at, username, password, display_name, email, disabled
+		FROM %s
+		WHERE email = ?;`
+
+	queryFmtSelectUsers = `
+		SELECT id, created_at, username, password, display_name, email, disabled
+		FROM %s
+		ORDER BY username ASC
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtUpsertUser = `
+		REPLACE INTO %s (created_at, username, password, display_name, email, disabled)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpsertUserPostgreSQL = `
+		INSERT INTO %s (created_at, username, password, display_name, email, disabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (username)
+			DO UPDATE SET created_at = $1, password = $3, display_name = $4, email = $5, disabled = $6;`
+
+	//nolint:gosec // These are not hardcoded credentials it's a query to update a password digest.
+	queryFmtUpdateUserPassword = `
+		UPDATE %s
+		SET password = ?
+		WHERE username = ?;`
+
+	queryFmtDeleteUser = `
+		DELETE FROM %s
+		WHERE username = ?;`
+
+	queryFmtSelectUserGroups = `
+		SELECT group_name
+		FROM %s
+		WHERE username = ?
+		ORDER BY id ASC;`
+
+	queryFmtInsertUserGroup = `
+		INSERT INTO %s (username, group_name)
+		VALUES (?, ?);`
+
+	queryFmtDeleteUserGroups = `
+		DELETE FROM %s
+		WHERE username = ?;`
+)
+
+const (
+	queryFmtSelectUserSessionRevocation = `
+		SELECT revoked_at
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtUpsertUserSessionRevocation = `
+		REPLACE INTO %s (revoked_at, username)
+		VALUES (?, ?);`
+
+	queryFmtUpsertUserSessionRevocationPostgreSQL = `
+		INSERT INTO %s (revoked_at, username)
+		VALUES ($1, $2)
+			ON CONFLICT (username)
+			DO UPDATE SET revoked_at = $1;`
+)
+
+const (
+	queryFmtSelectUserPasswordHistory = `
+		SELECT digest
+		FROM %s
+		WHERE username = ?
+		ORDER BY id DESC
+		LIMIT ?;`
+
+	queryFmtInsertUserPasswordHistory = `
+		INSERT INTO %s (created_at, username, digest)
+		VALUES (?, ?, ?);`
+
+	queryFmtDeleteUserPasswordHistoryExpired = `
+		DELETE FROM %s
+		WHERE username = ? AND id NOT IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %s
+				WHERE username = ?
+				ORDER BY id DESC
+				LIMIT ?
+			) AS retained
+		);`
+)
+
 const (
 	queryFmtSelectIdentityVerification = `
 		SELECT id, jti, iat, issued_ip, exp, username, action, consumed, consumed_ip
@@ -17,6 +17,7 @@ it("renders without crashing", () => {
                 require_lowercase: false,
                 require_number: false,
                 require_special: false,
+                breached: false,
                 require_uppercase: false,
                 mode: PasswordPolicyMode.Standard,
             }}
@@ -35,6 +36,7 @@ it("renders adjusted height without crashing", () => {
                 require_lowercase: false,
                 require_number: false,
                 require_special: false,
+                breached: false,
                 require_uppercase: false,
                 mode: PasswordPolicyMode.Standard,
             }}
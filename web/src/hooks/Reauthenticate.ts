@@ -0,0 +1,19 @@
+import queryString from "query-string";
+import { useLocation } from "react-router-dom";
+
+import { AuthenticationLevel } from "@services/State";
+
+export function useReauthenticate() {
+    const location = useLocation();
+    const queryParams = queryString.parse(location.search);
+    const value = queryParams && "reauth" in queryParams ? (queryParams["reauth"] as string) : undefined;
+
+    switch (value) {
+        case "one_factor":
+            return AuthenticationLevel.OneFactor;
+        case "two_factor":
+            return AuthenticationLevel.TwoFactor;
+        default:
+            return undefined;
+    }
+}
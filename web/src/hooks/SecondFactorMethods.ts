@@ -0,0 +1,31 @@
+import { useMemo } from "react";
+
+import queryString from "query-string";
+import { useLocation } from "react-router-dom";
+
+import { SecondFactorMethod } from "@models/Methods";
+import { Method2FA, toEnum } from "@services/UserInfo";
+
+const methods2FA: string[] = ["totp", "webauthn", "mobile_push"];
+
+export function useSecondFactorMethods() {
+    const location = useLocation();
+    const queryParams = queryString.parse(location.search);
+    const value = queryParams && "sfm" in queryParams ? (queryParams["sfm"] as string) : undefined;
+
+    return useMemo(() => {
+        if (!value) {
+            return undefined;
+        }
+
+        const methods = new Set<SecondFactorMethod>();
+
+        value.split(",").forEach((method) => {
+            if (methods2FA.includes(method)) {
+                methods.add(toEnum(method as Method2FA));
+            }
+        });
+
+        return methods.size === 0 ? undefined : methods;
+    }, [value]);
+}
@@ -1,5 +1,6 @@
 import { StatePath } from "@services/Api";
 import { Get } from "@services/Client";
+import { Method2FA } from "@services/UserInfo";
 
 export enum AuthenticationLevel {
     Unauthenticated = 0,
@@ -10,6 +11,7 @@ export enum AuthenticationLevel {
 export interface AutheliaState {
     username: string;
     authentication_level: AuthenticationLevel;
+    second_factor_methods?: Method2FA[];
 }
 
 export async function getState(): Promise<AutheliaState> {
@@ -1,4 +1,4 @@
-import React, { Fragment, ReactNode, useCallback, useEffect, useState } from "react";
+import React, { Fragment, ReactNode, useCallback, useEffect, useMemo, useState } from "react";
 
 import { Route, Routes, useLocation, useNavigate, useSearchParams } from "react-router-dom";
 
@@ -12,13 +12,16 @@ import {
 } from "@constants/Routes";
 import { useConfiguration } from "@hooks/Configuration";
 import { useNotifications } from "@hooks/NotificationsContext";
+import { useReauthenticate } from "@hooks/Reauthenticate";
 import { useRedirectionURL } from "@hooks/RedirectionURL";
 import { useRedirector } from "@hooks/Redirector";
+import { useSecondFactorMethods } from "@hooks/SecondFactorMethods";
 import { useAutheliaState } from "@hooks/State";
 import { useUserInfoPOST } from "@hooks/UserInfo";
 import { SecondFactorMethod } from "@models/Methods";
 import { checkSafeRedirection } from "@services/SafeRedirection";
 import { AuthenticationLevel } from "@services/State";
+import { toEnum } from "@services/UserInfo";
 import LoadingPage from "@views/LoadingPage/LoadingPage";
 import AuthenticatedView from "@views/LoginPortal/AuthenticatedView/AuthenticatedView";
 import FirstFactorForm from "@views/LoginPortal/FirstFactor/FirstFactorForm";
@@ -39,9 +42,12 @@ const LoginPortal = function (props: Props) {
     const navigate = useNavigate();
     const location = useLocation();
     const redirectionURL = useRedirectionURL();
+    const secondFactorMethods = useSecondFactorMethods();
+    const reauthenticate = useReauthenticate();
     const { createErrorNotification } = useNotifications();
     const [firstFactorDisabled, setFirstFactorDisabled] = useState(true);
     const [broadcastRedirect, setBroadcastRedirect] = useState(false);
+    const [stepUpComplete, setStepUpComplete] = useState(false);
     const redirector = useRedirector();
 
     const [state, fetchState, , fetchStateError] = useAutheliaState();
@@ -49,6 +55,43 @@ const LoginPortal = function (props: Props) {
     const [configuration, fetchConfiguration, , fetchConfigurationError] = useConfiguration();
     const [searchParams] = useSearchParams();
 
+    // A step-up is only required when none of the second factor methods the session was authenticated with are allowed.
+    const stepUpRequired = useMemo(() => {
+        if (!state || !secondFactorMethods) {
+            return false;
+        }
+
+        return !(state.second_factor_methods ?? []).some((method) => secondFactorMethods.has(toEnum(method)));
+    }, [state, secondFactorMethods]);
+
+    // The authentication level is lowered when the user must re-authenticate a factor or step-up their second factor
+    // so the relevant form is displayed even though the session is already authenticated at that level.
+    const authenticationLevel = useMemo(() => {
+        if (!state) {
+            return undefined;
+        }
+
+        if (stepUpComplete) {
+            return state.authentication_level;
+        }
+
+        if (
+            reauthenticate === AuthenticationLevel.OneFactor &&
+            state.authentication_level >= AuthenticationLevel.OneFactor
+        ) {
+            return AuthenticationLevel.Unauthenticated;
+        }
+
+        if (
+            (reauthenticate === AuthenticationLevel.TwoFactor || stepUpRequired) &&
+            state.authentication_level === AuthenticationLevel.TwoFactor
+        ) {
+            return AuthenticationLevel.OneFactor;
+        }
+
+        return state.authentication_level;
+    }, [state, stepUpComplete, reauthenticate, stepUpRequired]);
+
     const redirect = useCallback(
         (
             pathname: string,
@@ -81,10 +124,10 @@ const LoginPortal = function (props: Props) {
 
     // Enable first factor when user is unauthenticated.
     useEffect(() => {
-        if (state && state.authentication_level > AuthenticationLevel.Unauthenticated) {
+        if (authenticationLevel !== undefined && authenticationLevel > AuthenticationLevel.Unauthenticated) {
             setFirstFactorDisabled(true);
         }
-    }, [state, setFirstFactorDisabled]);
+    }, [authenticationLevel, setFirstFactorDisabled]);
 
     // Display an error when state fetching fails
     useEffect(() => {
@@ -110,7 +153,7 @@ const LoginPortal = function (props: Props) {
     // Redirect to the correct stage if not enough authenticated
     useEffect(() => {
         (async function () {
-            if (!state) {
+            if (authenticationLevel === undefined) {
                 return;
             }
 
@@ -118,8 +161,8 @@ const LoginPortal = function (props: Props) {
                 redirectionURL &&
                 ((configuration &&
                     configuration.available_methods.size === 0 &&
-                    state.authentication_level >= AuthenticationLevel.OneFactor) ||
-                    state.authentication_level === AuthenticationLevel.TwoFactor ||
+                    authenticationLevel >= AuthenticationLevel.OneFactor) ||
+                    authenticationLevel === AuthenticationLevel.TwoFactor ||
                     broadcastRedirect)
             ) {
                 try {
@@ -135,16 +178,29 @@ const LoginPortal = function (props: Props) {
                 return;
             }
 
-            if (state.authentication_level === AuthenticationLevel.Unauthenticated) {
+            if (authenticationLevel === AuthenticationLevel.Unauthenticated) {
                 setFirstFactorDisabled(false);
                 redirect(IndexRoute);
-            } else if (state.authentication_level >= AuthenticationLevel.OneFactor && userInfo && configuration) {
+            } else if (authenticationLevel >= AuthenticationLevel.OneFactor && userInfo && configuration) {
                 if (configuration.available_methods.size === 0) {
                     redirect(AuthenticatedRoute, false);
                 } else {
-                    if (userInfo.method === SecondFactorMethod.Webauthn) {
+                    let method = userInfo.method;
+
+                    // When a step-up is required the preferred method is only used if it satisfies the requirement.
+                    if (secondFactorMethods && !secondFactorMethods.has(method)) {
+                        const allowed = Array.from(secondFactorMethods).find((m) =>
+                            configuration.available_methods.has(m),
+                        );
+
+                        if (allowed !== undefined) {
+                            method = allowed;
+                        }
+                    }
+
+                    if (method === SecondFactorMethod.Webauthn) {
                         redirect(`${SecondFactorRoute}${SecondFactorWebauthnSubRoute}`);
-                    } else if (userInfo.method === SecondFactorMethod.MobilePush) {
+                    } else if (method === SecondFactorMethod.MobilePush) {
                         redirect(`${SecondFactorRoute}${SecondFactorPushSubRoute}`);
                     } else {
                         redirect(`${SecondFactorRoute}${SecondFactorTOTPSubRoute}`);
@@ -153,8 +209,9 @@ const LoginPortal = function (props: Props) {
             }
         })();
     }, [
-        state,
+        authenticationLevel,
         redirectionURL,
+        secondFactorMethods,
         redirect,
         userInfo,
         setFirstFactorDisabled,
@@ -170,6 +227,8 @@ const LoginPortal = function (props: Props) {
     };
 
     const handleAuthSuccess = async (redirectionURL: string | undefined) => {
+        setStepUpComplete(true);
+
         if (redirectionURL) {
             // Do an external redirection pushed by the server.
             redirector(redirectionURL);
@@ -180,9 +239,7 @@ const LoginPortal = function (props: Props) {
     };
 
     const firstFactorReady =
-        state !== undefined &&
-        state.authentication_level === AuthenticationLevel.Unauthenticated &&
-        location.pathname === IndexRoute;
+        authenticationLevel === AuthenticationLevel.Unauthenticated && location.pathname === IndexRoute;
 
     return (
         <Routes>
@@ -206,11 +263,12 @@ const LoginPortal = function (props: Props) {
             <Route
                 path={`${SecondFactorRoute}*`}
                 element={
-                    state && userInfo && configuration ? (
+                    authenticationLevel !== undefined && userInfo && configuration ? (
                         <SecondFactorForm
-                            authenticationLevel={state.authentication_level}
+                            authenticationLevel={authenticationLevel}
                             userInfo={userInfo}
                             configuration={configuration}
+                            secondFactorMethods={secondFactorMethods}
                             duoSelfEnrollment={props.duoSelfEnrollment}
                             onMethodChanged={() => fetchUserInfo()}
                             onAuthenticationSuccess={handleAuthSuccess}
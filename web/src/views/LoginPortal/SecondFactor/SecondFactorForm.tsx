@@ -29,6 +29,7 @@ export interface Props {
     authenticationLevel: AuthenticationLevel;
     userInfo: UserInfo;
     configuration: Configuration;
+    secondFactorMethods?: Set<SecondFactorMethod>;
     duoSelfEnrollment: boolean;
 
     onMethodChanged: () => void;
@@ -44,6 +45,11 @@ const SecondFactorForm = function (props: Props) {
     const [webauthnSupported, setWebauthnSupported] = useState(false);
     const { t: translate } = useTranslation();
 
+    // When a step-up is required only the methods which satisfy the requirement are offered.
+    const methods = props.secondFactorMethods
+        ? new Set(Array.from(props.configuration.available_methods).filter((m) => props.secondFactorMethods?.has(m)))
+        : props.configuration.available_methods;
+
     useEffect(() => {
         setWebauthnSupported(isWebauthnSupported());
     }, [setWebauthnSupported]);
@@ -86,10 +92,10 @@ const SecondFactorForm = function (props: Props) {
 
     return (
         <LoginLayout id="second-factor-stage" title={`${translate("Hi")} ${props.userInfo.display_name}`} showBrand>
-            {props.configuration.available_methods.size > 1 ? (
+            {methods.size > 1 ? (
                 <MethodSelectionDialog
                     open={methodSelectionOpen}
-                    methods={props.configuration.available_methods}
+                    methods={methods}
                     webauthnSupported={webauthnSupported}
                     onClose={() => setMethodSelectionOpen(false)}
                     onClick={handleMethodSelected}
@@ -100,8 +106,8 @@ const SecondFactorForm = function (props: Props) {
                     <Button color="secondary" onClick={handleLogoutClick} id="logout-button">
                         {translate("Logout")}
                     </Button>
-                    {props.configuration.available_methods.size > 1 ? " | " : null}
-                    {props.configuration.available_methods.size > 1 ? (
+                    {methods.size > 1 ? " | " : null}
+                    {methods.size > 1 ? (
                         <Button color="secondary" onClick={handleMethodSelectionClick} id="methods-button">
                             {translate("Methods")}
                         </Button>
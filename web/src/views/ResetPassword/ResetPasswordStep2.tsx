@@ -37,6 +37,7 @@ const ResetPasswordStep2 = function () {
         require_lowercase: false,
         require_number: false,
         require_special: false,
+        breached: false,
         require_uppercase: false,
         mode: PasswordPolicyMode.Disabled,
     });
@@ -97,6 +98,8 @@ const ResetPasswordStep2 = function () {
             console.error(err);
             if ((err as Error).message.includes("0000052D.")) {
                 createErrorNotification("Your supplied password does not meet the password policy requirements.");
+            } else if ((err as Error).message.includes("data breach")) {
+                createErrorNotification("Your supplied password has appeared in a data breach and can not be used.");
             } else if ((err as Error).message.includes("policy")) {
                 createErrorNotification("Your supplied password does not meet the password policy requirements.");
             } else {